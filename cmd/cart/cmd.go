@@ -3,18 +3,36 @@ package main
 import (
 	"context"
 	"errors"
+	"github.com/klwxsrx/arch-course-project/data/mysql/cart"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/persistence"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/catalogapi"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/deliveryapi"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/mysql"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/orderapi"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/quotetoken"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/redis"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/transport"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/warehouseapi"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	commonMessage "github.com/klwxsrx/arch-course-project/pkg/common/app/message"
 	loggerImpl "github.com/klwxsrx/arch-course-project/pkg/common/infra/logger"
+	commonMysql "github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/pulsar"
 	commonRedis "github.com/klwxsrx/arch-course-project/pkg/common/infra/redis"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+)
+
+const (
+	serviceName = "cart"
+
+	expiredCartsPurgeInterval = time.Hour
 )
 
 func main() {
@@ -34,14 +52,97 @@ func main() {
 	}
 	defer redisCli.Close()
 
+	expiration := domain.ExpirationPolicy{
+		UserCartTTL:  config.UserCartTTL,
+		GuestCartTTL: config.GuestCartTTL,
+	}
+	db, client, err := getDatabaseClient(config, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to setup db connection")
+	}
+	defer db.Close()
+
+	migration, err := commonMysql.NewMigration(client, logger, cart.MysqlMigrations)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to setup db migration")
+	}
+	err = migration.Migrate()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to execute db migration")
+	}
+
+	pulsarConn, err := pulsar.NewConnection(config.MessageBrokerAddress, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to setup message broker connection")
+	}
+
+	messageSender := pulsar.NewMessageSender(pulsarConn)
+	defer messageSender.Close()
+
+	messageDispatcher := commonMessage.NewDispatcher(
+		commonMysql.NewMessageStore(client),
+		messageSender,
+		commonMysql.NewSynchronization(client),
+		logger,
+	)
+	defer messageDispatcher.Close()
+	messageDispatcher.Dispatch()
+
+	cartStorage := redis.NewCartStorage(redisCli, expiration)
+	if config.MySQLPersistence {
+		cartStorage = redis.NewPersistentCartStorage(redisCli, expiration, mysql.NewCartStorage(client, expiration))
+		go purgeExpiredCarts(client, expiration, logger)
+	}
+
+	catalogAPI := catalogapi.New(config.CatalogServiceURL)
+
 	cartService := service.NewCartService(
-		catalogapi.New(config.CatalogServiceURL),
+		catalogAPI,
 		orderapi.New(config.OrderServiceURL),
-		redis.NewCartStorage(redisCli),
+		deliveryapi.New(config.DeliveryServiceURL),
+		warehouseapi.New(config.WarehouseServiceURL),
+		cartStorage,
+		redis.NewCheckoutKeyStorage(redisCli),
+		config.MergeStrategy,
+		quotetoken.NewEncoder(config.QuoteSigningKey),
+		config.QuoteTTL,
 		logger,
 	)
 
-	server, err := startServer(cartService, logger)
+	unitOfWork := mysql.NewUnitOfWork(client)
+	unitOfWork = persistence.NewUnitOfWorkCompleteNotifier(unitOfWork, messageDispatcher.Dispatch)
+	if config.AbandonedCartIdlePeriod > 0 {
+		abandonedCartService := service.NewAbandonedCartService(
+			unitOfWork,
+			expiration,
+			config.AbandonedCartIdlePeriod,
+			logger,
+		)
+		go notifyAbandonedCarts(abandonedCartService, config.AbandonedCartCheckInterval)
+	}
+
+	wishlistService := service.NewWishlistService(
+		unitOfWork,
+		cartStorage,
+		catalogAPI,
+		logger,
+	)
+
+	subscriberCloser, err := pulsar.NewMessageSubscriber(
+		serviceName,
+		[]commonMessage.Handler{
+			message.NewProductPriceChangedHandler(wishlistService),
+			message.NewProductBackInStockHandler(wishlistService),
+		},
+		pulsarConn,
+		logger,
+	)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to run message subscriber")
+	}
+	defer subscriberCloser()
+
+	server, err := startServer(cartService, wishlistService, logger)
 	if err != nil {
 		logger.WithError(err).Fatal("failed to start server")
 	}
@@ -51,8 +152,50 @@ func main() {
 	_ = server.Shutdown(context.Background())
 }
 
-func startServer(service *service.CartService, logger log.Logger) (*http.Server, error) {
-	handler, err := transport.NewHTTPHandler(service, logger)
+func getDatabaseClient(config *config, logger log.Logger) (commonMysql.Connection, commonMysql.TransactionalClient, error) {
+	db, err := commonMysql.NewConnection(commonMysql.Config{DSN: commonMysql.Dsn{
+		User:     config.DBUser,
+		Password: config.DBPassword,
+		Host:     config.DBHost,
+		Port:     config.DBPort,
+		Database: config.DBName,
+	}}, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := db.Client()
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return db, client, nil
+}
+
+func purgeExpiredCarts(client commonMysql.Client, expiration domain.ExpirationPolicy, logger log.Logger) {
+	ticker := time.NewTicker(expiredCartsPurgeInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		err := mysql.DeleteExpiredCarts(client, expiration)
+		if err != nil {
+			logger.WithError(err).Error("failed to purge expired carts")
+		}
+	}
+}
+
+func notifyAbandonedCarts(abandonedCartService *service.AbandonedCartService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		_, _ = abandonedCartService.NotifyAbandonedCarts()
+	}
+}
+
+func startServer(
+	cartService *service.CartService,
+	wishlistService *service.WishlistService,
+	logger log.Logger,
+) (*http.Server, error) {
+	handler, err := transport.NewHTTPHandler(cartService, wishlistService, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -1,15 +1,35 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
 	"os"
+	"strconv"
+	"time"
 )
 
 type config struct {
-	RedisAddress      string
-	RedisPassword     string
-	OrderServiceURL   string
-	CatalogServiceURL string
+	RedisAddress               string
+	RedisPassword              string
+	OrderServiceURL            string
+	CatalogServiceURL          string
+	DeliveryServiceURL         string
+	WarehouseServiceURL        string
+	UserCartTTL                time.Duration
+	GuestCartTTL               time.Duration
+	MergeStrategy              domain.MergeStrategy
+	QuoteTTL                   time.Duration
+	QuoteSigningKey            string
+	MySQLPersistence           bool
+	DBName                     string
+	DBHost                     string
+	DBPort                     string
+	DBUser                     string
+	DBPassword                 string
+	MessageBrokerAddress       string
+	AbandonedCartIdlePeriod    time.Duration
+	AbandonedCartCheckInterval time.Duration
 }
 
 func parseEnvString(key string, err error) (string, error) {
@@ -23,12 +43,70 @@ func parseEnvString(key string, err error) (string, error) {
 	return str, nil
 }
 
+func parseEnvDuration(key string, err error) (time.Duration, error) {
+	str, err := parseEnvString(key, err)
+	if err != nil {
+		return 0, err
+	}
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration environment variable %s: %w", key, err)
+	}
+	return duration, nil
+}
+
+func parseEnvBool(key string, err error) (bool, error) {
+	str, err := parseEnvString(key, err)
+	if err != nil {
+		return false, err
+	}
+	value, err := strconv.ParseBool(str)
+	if err != nil {
+		return false, fmt.Errorf("invalid bool environment variable %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func parseEnvMergeStrategy(key string, err error) (domain.MergeStrategy, error) {
+	str, err := parseEnvString(key, err)
+	if err != nil {
+		return "", err
+	}
+	strategy := domain.MergeStrategy(str)
+	if strategy != domain.MergeStrategySum && strategy != domain.MergeStrategyMax {
+		return "", fmt.Errorf("invalid environment variable %s: %w", key, domain.ErrUnknownMergeStrategy)
+	}
+	return strategy, nil
+}
+
 func parseConfig() (*config, error) {
 	var err error
 	redisAddress, err := parseEnvString("REDIS_ADDRESS", err)
 	redisPassword, err := parseEnvString("REDIS_PASSWORD", err)
 	orderServiceURL, err := parseEnvString("ORDER_SERVICE_URL", err)
 	catalogServiceURL, err := parseEnvString("CATALOG_SERVICE_URL", err)
+	deliveryServiceURL, err := parseEnvString("DELIVERY_SERVICE_URL", err)
+	warehouseServiceURL, err := parseEnvString("WAREHOUSE_SERVICE_URL", err)
+	userCartTTL, err := parseEnvDuration("CART_TTL", err)
+	guestCartTTL, err := parseEnvDuration("GUEST_CART_TTL", err)
+	mergeStrategy, err := parseEnvMergeStrategy("CART_MERGE_STRATEGY", err)
+	quoteTTL, err := parseEnvDuration("QUOTE_TTL", err)
+	quoteSigningKey, err := parseEnvString("QUOTE_SIGNING_KEY", err)
+	mysqlPersistence, err := parseEnvBool("CART_MYSQL_PERSISTENCE", err)
+	dbName, err := parseEnvString("DATABASE_NAME", err)
+	dbHost, err := parseEnvString("DATABASE_HOST", err)
+	dbPort, err := parseEnvString("DATABASE_PORT", err)
+	dbUser, err := parseEnvString("DATABASE_USER", err)
+	dbPassword, err := parseEnvString("DATABASE_PASSWORD", err)
+	messageBrokerAddress, err := parseEnvString("MESSAGE_BROKER_ADDRESS", err)
+	abandonedCartIdlePeriod, err := parseEnvDuration("ABANDONED_CART_IDLE_PERIOD", err)
+	abandonedCartCheckInterval, err := parseEnvDuration("ABANDONED_CART_CHECK_INTERVAL", err)
+	if err == nil && abandonedCartIdlePeriod > 0 && !mysqlPersistence {
+		err = errors.New("ABANDONED_CART_IDLE_PERIOD requires CART_MYSQL_PERSISTENCE to be enabled")
+	}
+	if err == nil && abandonedCartIdlePeriod > 0 && abandonedCartCheckInterval <= 0 {
+		err = errors.New("invalid environment variable ABANDONED_CART_CHECK_INTERVAL")
+	}
 
 	if err != nil {
 		return nil, err
@@ -39,5 +117,21 @@ func parseConfig() (*config, error) {
 		redisPassword,
 		orderServiceURL,
 		catalogServiceURL,
+		deliveryServiceURL,
+		warehouseServiceURL,
+		userCartTTL,
+		guestCartTTL,
+		mergeStrategy,
+		quoteTTL,
+		quoteSigningKey,
+		mysqlPersistence,
+		dbName,
+		dbHost,
+		dbPort,
+		dbUser,
+		dbPassword,
+		messageBrokerAddress,
+		abandonedCartIdlePeriod,
+		abandonedCartCheckInterval,
 	}, nil
 }
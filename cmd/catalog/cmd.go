@@ -4,19 +4,26 @@ import (
 	"context"
 	"errors"
 	"github.com/klwxsrx/arch-course-project/data/mysql/catalog"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/persistence"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/query"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/infra/filesystem"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/infra/mysql"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/infra/transport"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	commonMessage "github.com/klwxsrx/arch-course-project/pkg/common/app/message"
 	loggerImpl "github.com/klwxsrx/arch-course-project/pkg/common/infra/logger"
 	commonMysql "github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/pulsar"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 )
 
+const serviceName = "catalog"
+
 func main() {
 	logger := loggerImpl.New()
 
@@ -40,15 +47,57 @@ func main() {
 		logger.WithError(err).Fatal("failed to execute db migration")
 	}
 
+	pulsarConn, err := pulsar.NewConnection(config.MessageBrokerAddress, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to setup message broker connection")
+	}
+
+	messageSender := pulsar.NewMessageSender(pulsarConn)
+	defer messageSender.Close()
+
+	messageDispatcher := commonMessage.NewDispatcher(
+		commonMysql.NewMessageStore(client),
+		messageSender,
+		commonMysql.NewSynchronization(client),
+		logger,
+	)
+	defer messageDispatcher.Close()
+	messageDispatcher.Dispatch()
+
 	unitOfWork := mysql.NewUnitOfWork(client)
+	unitOfWork = persistence.NewUnitOfWorkCompleteNotifier(unitOfWork, messageDispatcher.Dispatch)
+	imageStorage := filesystem.NewBlobStorage(config.ImageStoragePath, config.ImagePublicURL)
 	productService := service.NewProductService(
 		unitOfWork,
+		imageStorage,
 		logger,
 	)
 
-	productQueryService := mysql.NewProductQueryService(client)
+	categoryService := service.NewCategoryService(
+		unitOfWork,
+		logger,
+	)
+
+	productQueryService := mysql.NewProductQueryService(client, imageStorage)
+	categoryQueryService := mysql.NewCategoryQueryService(client, imageStorage)
+	productSearchIndex := mysql.NewProductSearchIndex(client, imageStorage)
+
+	subscriberCloser, err := pulsar.NewMessageSubscriber(
+		serviceName,
+		[]commonMessage.Handler{
+			message.NewItemBackInStockHandler(productService),
+			message.NewItemOutOfStockHandler(productService),
+			message.NewItemStockSyncedHandler(productService),
+		},
+		pulsarConn,
+		logger,
+	)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to run message subscriber")
+	}
+	defer subscriberCloser()
 
-	server, err := startServer(productService, productQueryService, logger)
+	server, err := startServer(productService, categoryService, productQueryService, categoryQueryService, productSearchIndex, logger)
 	if err != nil {
 		logger.WithError(err).Fatal("failed to start server")
 	}
@@ -77,8 +126,15 @@ func getDatabaseClient(config *config, logger log.Logger) (commonMysql.Connectio
 	return db, client, nil
 }
 
-func startServer(service *service.ProductService, query query.ProductService, logger log.Logger) (*http.Server, error) {
-	handler, err := transport.NewHTTPHandler(service, query, logger)
+func startServer(
+	productService *service.ProductService,
+	categoryService *service.CategoryService,
+	productQueryService query.ProductService,
+	categoryQueryService query.CategoryService,
+	productSearchIndex query.ProductSearchIndex,
+	logger log.Logger,
+) (*http.Server, error) {
+	handler, err := transport.NewHTTPHandler(productService, categoryService, productQueryService, categoryQueryService, productSearchIndex, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -6,11 +6,14 @@ import (
 )
 
 type config struct {
-	DBName     string
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
+	DBName               string
+	DBHost               string
+	DBPort               string
+	DBUser               string
+	DBPassword           string
+	MessageBrokerAddress string
+	ImageStoragePath     string
+	ImagePublicURL       string
 }
 
 func parseEnvString(key string, err error) (string, error) {
@@ -31,6 +34,9 @@ func parseConfig() (*config, error) {
 	dbPort, err := parseEnvString("DATABASE_PORT", err)
 	dbUser, err := parseEnvString("DATABASE_USER", err)
 	dbPassword, err := parseEnvString("DATABASE_PASSWORD", err)
+	messageBrokerAddress, err := parseEnvString("MESSAGE_BROKER_ADDRESS", err)
+	imageStoragePath, err := parseEnvString("IMAGE_STORAGE_PATH", err)
+	imagePublicURL, err := parseEnvString("IMAGE_PUBLIC_URL", err)
 
 	if err != nil {
 		return nil, err
@@ -42,5 +48,8 @@ func parseConfig() (*config, error) {
 		dbPort,
 		dbUser,
 		dbPassword,
+		messageBrokerAddress,
+		imageStoragePath,
+		imagePublicURL,
 	}, nil
 }
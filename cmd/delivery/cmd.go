@@ -21,7 +21,10 @@ import (
 	"syscall"
 )
 
-const serviceName = "delivery"
+const (
+	serviceName         = "delivery"
+	maxDeliveryAttempts = 3
+)
 
 func main() {
 	logger := loggerImpl.New()
@@ -70,6 +73,24 @@ func main() {
 		logger,
 	)
 
+	addressService := service.NewAddressService(
+		unitOfWork,
+		logger,
+	)
+	zoneService := service.NewZoneService(
+		unitOfWork,
+		logger,
+	)
+	courierService := service.NewCourierService(
+		unitOfWork,
+		maxDeliveryAttempts,
+		logger,
+	)
+	pickupPointService := service.NewPickupPointService(
+		unitOfWork,
+		config.PickupStoragePeriod,
+		logger,
+	)
 	deliveryQueryService := mysql.NewQueryService(client)
 
 	subscriberCloser, err := pulsar.NewMessageSubscriber(
@@ -87,7 +108,7 @@ func main() {
 	}
 	defer subscriberCloser()
 
-	server, err := startServer(deliveryService, deliveryQueryService, logger)
+	server, err := startServer(deliveryService, addressService, zoneService, courierService, pickupPointService, deliveryQueryService, logger)
 	if err != nil {
 		logger.WithError(err).Fatal("failed to start server")
 	}
@@ -116,8 +137,16 @@ func getDatabaseClient(config *config, logger log.Logger) (commonMysql.Connectio
 	return db, client, nil
 }
 
-func startServer(service *service.DeliveryService, query query.Service, logger log.Logger) (*http.Server, error) {
-	handler, err := transport.NewHTTPHandler(service, query, logger)
+func startServer(
+	service *service.DeliveryService,
+	addressService *service.AddressService,
+	zoneService *service.ZoneService,
+	courierService *service.CourierService,
+	pickupPointService *service.PickupPointService,
+	query query.Service,
+	logger log.Logger,
+) (*http.Server, error) {
+	handler, err := transport.NewHTTPHandler(service, addressService, zoneService, courierService, pickupPointService, query, logger)
 	if err != nil {
 		return nil, err
 	}
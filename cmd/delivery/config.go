@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
 type config struct {
@@ -12,6 +13,7 @@ type config struct {
 	DBUser               string
 	DBPassword           string
 	MessageBrokerAddress string
+	PickupStoragePeriod  time.Duration
 }
 
 func parseEnvString(key string, err error) (string, error) {
@@ -25,6 +27,18 @@ func parseEnvString(key string, err error) (string, error) {
 	return str, nil
 }
 
+func parseEnvDuration(key string, err error) (time.Duration, error) {
+	str, err := parseEnvString(key, err)
+	if err != nil {
+		return 0, err
+	}
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration environment variable %s: %w", key, err)
+	}
+	return duration, nil
+}
+
 func parseConfig() (*config, error) {
 	var err error
 	dbName, err := parseEnvString("DATABASE_NAME", err)
@@ -33,6 +47,7 @@ func parseConfig() (*config, error) {
 	dbUser, err := parseEnvString("DATABASE_USER", err)
 	dbPassword, err := parseEnvString("DATABASE_PASSWORD", err)
 	messageBrokerAddress, err := parseEnvString("MESSAGE_BROKER_ADDRESS", err)
+	pickupStoragePeriod, err := parseEnvDuration("PICKUP_STORAGE_PERIOD", err)
 
 	if err != nil {
 		return nil, err
@@ -45,5 +60,6 @@ func parseConfig() (*config, error) {
 		dbUser,
 		dbPassword,
 		messageBrokerAddress,
+		pickupStoragePeriod,
 	}, nil
 }
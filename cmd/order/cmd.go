@@ -13,12 +13,14 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/order/app/persistence"
 	"github.com/klwxsrx/arch-course-project/pkg/order/app/query"
 	"github.com/klwxsrx/arch-course-project/pkg/order/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/order/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/order/infra/mysql"
 	"github.com/klwxsrx/arch-course-project/pkg/order/infra/transport"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 const serviceName = "order"
@@ -67,8 +69,10 @@ func main() {
 	unitOfWork = persistence.NewUnitOfWorkCompleteNotifier(unitOfWork, messageDispatcher.Dispatch)
 	orderService := service.NewOrderService(
 		unitOfWork,
+		getRiskRules(),
 		logger,
 	)
+	promoCodeService := service.NewPromoCodeService(unitOfWork, logger)
 
 	subscriberCloser, err := pulsar.NewMessageSubscriber(
 		serviceName,
@@ -77,8 +81,14 @@ func main() {
 			message.NewItemsReservedHandler(orderService),
 			message.NewItemsOutOfStockHandler(orderService),
 			message.NewDeliveryScheduledHandler(orderService),
+			message.NewDeliveryScheduleRejectedHandler(orderService),
+			message.NewOrderDeliveredHandler(orderService),
+			message.NewOrderPartiallyDeliveredHandler(orderService),
+			message.NewDeliveryFailedHandler(orderService),
+			message.NewDeliveryPartiallyFailedHandler(orderService),
 			message.NewPaymentCompletedHandler(orderService),
 			message.NewPaymentCompletionRejectedHandler(orderService),
+			message.NewPaymentRefundedHandler(orderService),
 		},
 		pulsarConn,
 		logger,
@@ -89,7 +99,7 @@ func main() {
 	defer subscriberCloser()
 
 	queryService := mysql.NewOrderQueryService(client)
-	server, err := startServer(orderService, queryService, logger)
+	server, err := startServer(orderService, promoCodeService, queryService, logger)
 	if err != nil {
 		logger.WithError(err).Fatal("failed to start server")
 	}
@@ -99,6 +109,23 @@ func main() {
 	_ = server.Shutdown(context.Background())
 }
 
+func getRiskRules() []domain.RiskRule {
+	return []domain.RiskRule{
+		&domain.AmountThresholdRule{
+			ReviewAmount: 100000,
+			RejectAmount: 1000000,
+		},
+		&domain.OrderVelocityRule{
+			Period:    time.Hour,
+			MaxOrders: 5,
+		},
+		&domain.NewAccountHighValueRule{
+			AccountAge: time.Hour * 24,
+			Amount:     30000,
+		},
+	}
+}
+
 func getDatabaseClient(config *config, logger log.Logger) (commonMysql.Connection, commonMysql.TransactionalClient, error) {
 	db, err := commonMysql.NewConnection(commonMysql.Config{DSN: commonMysql.Dsn{
 		User:     config.DBUser,
@@ -118,8 +145,13 @@ func getDatabaseClient(config *config, logger log.Logger) (commonMysql.Connectio
 	return db, client, nil
 }
 
-func startServer(orderService *service.OrderService, queryService query.Service, logger log.Logger) (*http.Server, error) {
-	handler, err := transport.NewHTTPHandler(orderService, queryService, logger)
+func startServer(
+	orderService *service.OrderService,
+	promoCodeService *service.PromoCodeService,
+	queryService query.Service,
+	logger log.Logger,
+) (*http.Server, error) {
+	handler, err := transport.NewHTTPHandler(orderService, promoCodeService, queryService, logger)
 	if err != nil {
 		return nil, err
 	}
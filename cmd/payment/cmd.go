@@ -69,6 +69,10 @@ func main() {
 		unitOfWork,
 		logger,
 	)
+	storeCreditService := service.NewStoreCreditService(
+		unitOfWork,
+		logger,
+	)
 	paymentQueryService := mysql.NewPaymentQueryService(client)
 
 	subscriberCloser, err := pulsar.NewMessageSubscriber(
@@ -77,6 +81,8 @@ func main() {
 			message.NewAuthorizePaymentHandler(paymentService),
 			message.NewCompletePaymentHandler(paymentService),
 			message.NewCancelPaymentHandler(paymentService),
+			message.NewRefundPaymentHandler(paymentService),
+			message.NewRefundPaymentPartiallyHandler(paymentService),
 		},
 		pulsarConn,
 		logger,
@@ -86,7 +92,7 @@ func main() {
 	}
 	defer subscriberCloser()
 
-	server, err := startServer(paymentService, paymentQueryService, logger)
+	server, err := startServer(paymentService, storeCreditService, paymentQueryService, []byte(config.GatewayWebhookSecret), logger)
 	if err != nil {
 		logger.WithError(err).Fatal("failed to start server")
 	}
@@ -115,8 +121,14 @@ func getDatabaseClient(config *config, logger log.Logger) (commonMysql.Connectio
 	return db, client, nil
 }
 
-func startServer(paymentService *service.PaymentService, paymentQueryService query.PaymentQueryService, logger log.Logger) (*http.Server, error) {
-	handler, err := transport.NewHTTPHandler(paymentService, paymentQueryService, logger)
+func startServer(
+	paymentService *service.PaymentService,
+	storeCreditService *service.StoreCreditService,
+	paymentQueryService query.PaymentQueryService,
+	webhookSecret []byte,
+	logger log.Logger,
+) (*http.Server, error) {
+	handler, err := transport.NewHTTPHandler(paymentService, storeCreditService, paymentQueryService, webhookSecret, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -12,6 +12,7 @@ type config struct {
 	DBUser               string
 	DBPassword           string
 	MessageBrokerAddress string
+	GatewayWebhookSecret string
 }
 
 func parseEnvString(key string, err error) (string, error) {
@@ -33,6 +34,7 @@ func parseConfig() (*config, error) {
 	dbUser, err := parseEnvString("DATABASE_USER", err)
 	dbPassword, err := parseEnvString("DATABASE_PASSWORD", err)
 	messageBrokerAddress, err := parseEnvString("MESSAGE_BROKER_ADDRESS", err)
+	gatewayWebhookSecret, err := parseEnvString("PAYMENT_GATEWAY_WEBHOOK_SECRET", err)
 
 	if err != nil {
 		return nil, err
@@ -45,5 +47,6 @@ func parseConfig() (*config, error) {
 		dbUser,
 		dbPassword,
 		messageBrokerAddress,
+		gatewayWebhookSecret,
 	}, nil
 }
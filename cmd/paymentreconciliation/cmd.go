@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"github.com/klwxsrx/arch-course-project/data/mysql/payment"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	loggerImpl "github.com/klwxsrx/arch-course-project/pkg/common/infra/logger"
+	commonMysql "github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/infra/settlement"
+	"os"
+	"time"
+)
+
+const settlementDateLayout = "2006-01-02"
+
+func main() {
+	logger := loggerImpl.New()
+
+	reportPath := flag.String("report", "", "gateway settlement report csv file")
+	date := flag.String("date", time.Now().UTC().AddDate(0, 0, -1).Format(settlementDateLayout), "settlement date")
+	flag.Parse()
+
+	settlementDate, err := time.Parse(settlementDateLayout, *date)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to parse settlement date")
+	}
+
+	reportFile, err := os.Open(*reportPath)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to open settlement report")
+	}
+	defer reportFile.Close()
+
+	records, err := settlement.ParseCSVReport(reportFile)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to parse settlement report")
+	}
+
+	config, err := parseConfig()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to parse config")
+	}
+
+	db, client, err := getDatabaseClient(config, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to setup db connection")
+	}
+	defer db.Close()
+
+	migration, err := commonMysql.NewMigration(client, logger, payment.MysqlMigrations)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to setup db migration")
+	}
+	err = migration.Migrate()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to execute db migration")
+	}
+
+	reconciliationService := service.NewReconciliationService(mysql.NewUnitOfWork(client), logger)
+	_, err = reconciliationService.Reconcile(settlementDate, records)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to reconcile payments")
+	}
+}
+
+func getDatabaseClient(config *config, logger log.Logger) (commonMysql.Connection, commonMysql.TransactionalClient, error) {
+	db, err := commonMysql.NewConnection(commonMysql.Config{DSN: commonMysql.Dsn{
+		User:     config.DBUser,
+		Password: config.DBPassword,
+		Host:     config.DBHost,
+		Port:     config.DBPort,
+		Database: config.DBName,
+	}}, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := db.Client()
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return db, client, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/infra/gateway"
+	"net/http"
+	"os"
+)
+
+// Signs payment gateway webhook events and posts them to the payment service, so the purchase saga can be completed locally
+func main() {
+	url := flag.String("url", "http://localhost:8080/webhook/payment/gateway", "payment service webhook url")
+	secret := flag.String("secret", os.Getenv("PAYMENT_GATEWAY_WEBHOOK_SECRET"), "webhook signing secret")
+	eventID := flag.String("id", uuid.NewString(), "gateway event id")
+	eventType := flag.String("type", gateway.WebhookEventCaptureSucceeded, "gateway event type")
+	orderID := flag.String("order", "", "order id")
+	amount := flag.Int("amount", 0, "payment amount")
+	dryRun := flag.Bool("dry-run", false, "print signed request instead of sending it")
+	flag.Parse()
+
+	err := run(*url, *secret, *eventID, *eventType, *orderID, *amount, *dryRun)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(url, secret, eventID, eventType, orderID string, amount int, dryRun bool) error {
+	if secret == "" {
+		return fmt.Errorf("webhook secret is not specified")
+	}
+	parsedOrderID, err := uuid.Parse(orderID)
+	if err != nil {
+		return fmt.Errorf("invalid order id: %w", err)
+	}
+
+	body, err := json.Marshal(gateway.WebhookEvent{
+		ID:      eventID,
+		Type:    eventType,
+		OrderID: parsedOrderID,
+		Amount:  amount,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	signature := gateway.Sign([]byte(secret), body)
+
+	if dryRun {
+		fmt.Printf("%s: %s\n%s\n", gateway.SignatureHeader, signature, body)
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(gateway.SignatureHeader, signature)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	fmt.Println(resp.Status)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -74,6 +74,8 @@ func main() {
 		[]commonMessage.Handler{
 			message.NewReserveItemsHandler(warehouseService),
 			message.NewRemoveItemsReservationHandler(warehouseService),
+			message.NewReturnItemsHandler(warehouseService),
+			message.NewReturnItemsPartiallyHandler(warehouseService),
 		},
 		pulsarConn,
 		logger,
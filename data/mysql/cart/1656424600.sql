@@ -0,0 +1,21 @@
+CREATE TABLE `cart`
+(
+    owner_id   BINARY(16) NOT NULL,
+    is_guest   TINYINT(1) NOT NULL,
+    updated_at DATETIME   NOT NULL,
+    PRIMARY KEY (owner_id, is_guest),
+    INDEX updated_at_idx (updated_at)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `cart_item`
+(
+    owner_id   BINARY(16) NOT NULL,
+    is_guest   TINYINT(1) NOT NULL,
+    product_id BINARY(16) NOT NULL,
+    quantity   INT        NOT NULL,
+    PRIMARY KEY (owner_id, is_guest, product_id),
+    FOREIGN KEY (owner_id, is_guest) REFERENCES cart (owner_id, is_guest) ON DELETE CASCADE
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
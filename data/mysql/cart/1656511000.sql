@@ -0,0 +1 @@
+ALTER TABLE `cart_item` ADD COLUMN added_price BIGINT NOT NULL DEFAULT 0 AFTER quantity
\ No newline at end of file
@@ -0,0 +1,25 @@
+CREATE TABLE `wishlist`
+(
+    id          BINARY(16)   NOT NULL,
+    user_id     BINARY(16)   NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    share_token VARCHAR(64)  NULL,
+    created_at  DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    INDEX user_id_idx (user_id),
+    UNIQUE INDEX share_token_idx (share_token)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `wishlist_item`
+(
+    wishlist_id BINARY(16) NOT NULL,
+    product_id  BINARY(16) NOT NULL,
+    added_price BIGINT     NOT NULL,
+    added_at    DATETIME   NOT NULL,
+    PRIMARY KEY (wishlist_id, product_id),
+    INDEX product_id_idx (product_id),
+    FOREIGN KEY (wishlist_id) REFERENCES wishlist (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
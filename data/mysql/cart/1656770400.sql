@@ -0,0 +1 @@
+ALTER TABLE `cart` ADD COLUMN abandoned_at DATETIME NULL AFTER updated_at
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE `cart_item`
+    ADD COLUMN variant_id BINARY(16) NOT NULL DEFAULT '' AFTER product_id,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (owner_id, is_guest, product_id, variant_id)
\ No newline at end of file
@@ -0,0 +1,6 @@
+package cart
+
+import "embed"
+
+//go:embed *.sql
+var MysqlMigrations embed.FS
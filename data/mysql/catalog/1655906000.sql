@@ -0,0 +1 @@
+ALTER TABLE `product` ADD COLUMN weight INT NOT NULL DEFAULT 0 AFTER price
\ No newline at end of file
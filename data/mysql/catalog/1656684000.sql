@@ -0,0 +1 @@
+ALTER TABLE `product` ADD COLUMN max_quantity INT NOT NULL DEFAULT 0 AFTER weight
\ No newline at end of file
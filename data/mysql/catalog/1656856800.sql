@@ -0,0 +1,27 @@
+CREATE TABLE `category`
+(
+    id         BINARY(16)   NOT NULL,
+    parent_id  BINARY(16)   NULL,
+    slug       VARCHAR(255) NOT NULL,
+    title      VARCHAR(255) NOT NULL,
+    position   INT          NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE INDEX slug_idx (slug),
+    INDEX parent_id_idx (parent_id),
+    FOREIGN KEY (parent_id) REFERENCES category (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `product_category`
+(
+    product_id  BINARY(16) NOT NULL,
+    category_id BINARY(16) NOT NULL,
+    PRIMARY KEY (product_id, category_id),
+    INDEX category_id_idx (category_id),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES category (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `product` ADD COLUMN in_stock TINYINT(1) NOT NULL DEFAULT 0 AFTER max_quantity;
+ALTER TABLE `product` ADD FULLTEXT INDEX title_description_idx (title, description)
\ No newline at end of file
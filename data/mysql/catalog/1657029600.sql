@@ -0,0 +1,29 @@
+CREATE TABLE `product_option`
+(
+    product_id    BINARY(16)   NOT NULL,
+    position      INT          NOT NULL,
+    name          VARCHAR(255) NOT NULL,
+    option_values TEXT         NOT NULL,
+    PRIMARY KEY (product_id, position),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `product_variant`
+(
+    id                BINARY(16)   NOT NULL,
+    product_id        BINARY(16)   NOT NULL,
+    position          INT          NOT NULL,
+    sku               VARCHAR(255) NOT NULL,
+    option_values     TEXT         NOT NULL,
+    price             BIGINT       NOT NULL DEFAULT 0,
+    warehouse_item_id BINARY(16)   NOT NULL,
+    in_stock          TINYINT(1)   NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    UNIQUE INDEX sku_idx (sku),
+    INDEX product_id_idx (product_id),
+    INDEX warehouse_item_id_idx (warehouse_item_id),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE `product_image`
+(
+    id           BINARY(16)  NOT NULL,
+    product_id   BINARY(16)  NOT NULL,
+    position     INT         NOT NULL,
+    content_type VARCHAR(32) NOT NULL,
+    PRIMARY KEY (id),
+    INDEX product_id_position_idx (product_id, position),
+    FOREIGN KEY (product_id) REFERENCES product (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
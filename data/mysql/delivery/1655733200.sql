@@ -0,0 +1,31 @@
+CREATE TABLE `address`
+(
+    id          BINARY(16) PRIMARY KEY,
+    user_id     BINARY(16),
+    country     VARCHAR(255),
+    city        VARCHAR(255),
+    street      VARCHAR(255),
+    building    VARCHAR(255),
+    apartment   VARCHAR(255),
+    postal_code VARCHAR(255),
+    recipient   VARCHAR(255),
+    phone       VARCHAR(255),
+    is_default  BOOLEAN,
+    deleted     BOOLEAN,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (user_id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+ALTER TABLE `delivery` ADD COLUMN address_id BINARY(16) NULL AFTER status;
+ALTER TABLE `delivery` ADD COLUMN country VARCHAR(255) DEFAULT '' AFTER address_id;
+ALTER TABLE `delivery` ADD COLUMN city VARCHAR(255) DEFAULT '' AFTER country;
+ALTER TABLE `delivery` ADD COLUMN street VARCHAR(255) DEFAULT '' AFTER city;
+ALTER TABLE `delivery` ADD COLUMN building VARCHAR(255) DEFAULT '' AFTER street;
+ALTER TABLE `delivery` ADD COLUMN apartment VARCHAR(255) DEFAULT '' AFTER building;
+ALTER TABLE `delivery` ADD COLUMN postal_code VARCHAR(255) DEFAULT '' AFTER apartment;
+ALTER TABLE `delivery` ADD COLUMN recipient VARCHAR(255) DEFAULT '' AFTER postal_code;
+ALTER TABLE `delivery` ADD COLUMN phone VARCHAR(255) DEFAULT '' AFTER recipient;
+UPDATE `delivery` SET street = address;
+ALTER TABLE `delivery` DROP COLUMN address
\ No newline at end of file
@@ -0,0 +1,33 @@
+CREATE TABLE `zone`
+(
+    id         BINARY(16) PRIMARY KEY,
+    name       VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `zone_city`
+(
+    city    VARCHAR(255) PRIMARY KEY,
+    zone_id BINARY(16),
+    FOREIGN KEY (zone_id) REFERENCES `zone` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `slot`
+(
+    id         BINARY(16) PRIMARY KEY,
+    zone_id    BINARY(16),
+    starts_at  DATETIME,
+    ends_at    DATETIME,
+    capacity   INT,
+    reserved   INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (zone_id, starts_at),
+    FOREIGN KEY (zone_id) REFERENCES `zone` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+ALTER TABLE `delivery` ADD COLUMN slot_id BINARY(16) NULL AFTER status
\ No newline at end of file
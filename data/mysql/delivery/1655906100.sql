@@ -0,0 +1,22 @@
+ALTER TABLE `zone` ADD COLUMN free_shipping_amount BIGINT NOT NULL DEFAULT 0 AFTER name;
+CREATE TABLE `zone_postal_code_range`
+(
+    zone_id   BINARY(16),
+    code_from VARCHAR(16),
+    code_to   VARCHAR(16),
+    INDEX (code_from, code_to),
+    FOREIGN KEY (zone_id) REFERENCES `zone` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `zone_rate`
+(
+    zone_id          BINARY(16),
+    max_weight       INT,
+    min_order_amount BIGINT,
+    price            BIGINT,
+    INDEX (zone_id),
+    FOREIGN KEY (zone_id) REFERENCES `zone` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
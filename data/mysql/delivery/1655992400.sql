@@ -0,0 +1,34 @@
+CREATE TABLE `courier`
+(
+    id         BINARY(16) PRIMARY KEY,
+    user_id    BINARY(16) UNIQUE,
+    name       VARCHAR(255),
+    phone      VARCHAR(32),
+    zone_id    BINARY(16),
+    capacity   INT,
+    active     TINYINT(1),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (active),
+    FOREIGN KEY (zone_id) REFERENCES `zone` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `route`
+(
+    id         BINARY(16) PRIMARY KEY,
+    courier_id BINARY(16),
+    zone_id    BINARY(16),
+    slot_id    BINARY(16) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (courier_id),
+    FOREIGN KEY (courier_id) REFERENCES `courier` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+ALTER TABLE `delivery`
+    ADD COLUMN courier_id     BINARY(16)   NULL AFTER phone,
+    ADD COLUMN route_id       BINARY(16)   NULL AFTER courier_id,
+    ADD COLUMN failure_reason VARCHAR(255) NULL AFTER route_id,
+    ADD INDEX (status, courier_id),
+    ADD INDEX (courier_id, status)
\ No newline at end of file
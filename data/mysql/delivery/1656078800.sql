@@ -0,0 +1,13 @@
+ALTER TABLE `delivery` ADD COLUMN attempts INT NOT NULL DEFAULT 0 AFTER failure_reason;
+CREATE TABLE `delivery_attempt`
+(
+    order_id     BINARY(16),
+    number       INT,
+    courier_id   BINARY(16) NULL,
+    reason       VARCHAR(255),
+    attempted_at DATETIME,
+    PRIMARY KEY (order_id, number),
+    CONSTRAINT delivery_attempt_ibfk_1 FOREIGN KEY (order_id) REFERENCES `delivery` (order_id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE `delivery` ADD COLUMN estimated_arrival DATETIME NULL AFTER attempts;
+CREATE TABLE `tracking_event`
+(
+    id          BIGINT AUTO_INCREMENT,
+    order_id    BINARY(16),
+    type        INT,
+    details     VARCHAR(255) NULL,
+    occurred_at DATETIME,
+    PRIMARY KEY (id),
+    INDEX order_id_idx (order_id, id),
+    CONSTRAINT tracking_event_ibfk_1 FOREIGN KEY (order_id) REFERENCES `delivery` (order_id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
@@ -0,0 +1,38 @@
+CREATE TABLE `pickup_point`
+(
+    id          BINARY(16) PRIMARY KEY,
+    name        VARCHAR(255),
+    country     VARCHAR(255),
+    city        VARCHAR(255),
+    street      VARCHAR(255),
+    building    VARCHAR(255),
+    apartment   VARCHAR(255),
+    postal_code VARCHAR(32),
+    recipient   VARCHAR(255),
+    phone       VARCHAR(32),
+    capacity    INT,
+    active      TINYINT(1),
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (active, city)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `pickup_point_opening_hours`
+(
+    pickup_point_id BINARY(16),
+    weekday         INT,
+    opens           VARCHAR(5),
+    closes          VARCHAR(5),
+    PRIMARY KEY (pickup_point_id, weekday),
+    FOREIGN KEY (pickup_point_id) REFERENCES `pickup_point` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+ALTER TABLE `delivery`
+    ADD COLUMN user_id         BINARY(16)  NULL AFTER status,
+    ADD COLUMN pickup_point_id BINARY(16)  NULL AFTER address_id,
+    ADD COLUMN pickup_code     VARCHAR(16) NULL AFTER pickup_point_id,
+    ADD COLUMN arrived_at      DATETIME    NULL AFTER pickup_code,
+    ADD INDEX (pickup_point_id, status),
+    ADD INDEX (status, arrived_at)
\ No newline at end of file
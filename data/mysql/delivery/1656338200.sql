@@ -0,0 +1,32 @@
+ALTER TABLE `delivery_attempt` DROP FOREIGN KEY delivery_attempt_ibfk_1;
+ALTER TABLE `tracking_event` DROP FOREIGN KEY tracking_event_ibfk_1;
+ALTER TABLE `delivery` ADD COLUMN id BINARY(16) NULL FIRST;
+UPDATE `delivery` SET id = order_id;
+ALTER TABLE `delivery`
+    DROP PRIMARY KEY,
+    MODIFY id BINARY(16) NOT NULL,
+    ADD PRIMARY KEY (id),
+    ADD INDEX (order_id);
+ALTER TABLE `delivery_attempt` ADD COLUMN delivery_id BINARY(16) NULL FIRST;
+UPDATE `delivery_attempt` SET delivery_id = order_id;
+ALTER TABLE `delivery_attempt`
+    DROP PRIMARY KEY,
+    DROP COLUMN order_id,
+    MODIFY delivery_id BINARY(16) NOT NULL,
+    ADD PRIMARY KEY (delivery_id, number),
+    ADD CONSTRAINT delivery_attempt_delivery_fk FOREIGN KEY (delivery_id) REFERENCES `delivery` (id);
+ALTER TABLE `tracking_event` ADD COLUMN delivery_id BINARY(16) NULL AFTER order_id;
+UPDATE `tracking_event` SET delivery_id = order_id;
+ALTER TABLE `tracking_event`
+    MODIFY delivery_id BINARY(16) NOT NULL,
+    ADD CONSTRAINT tracking_event_delivery_fk FOREIGN KEY (delivery_id) REFERENCES `delivery` (id);
+CREATE TABLE `delivery_item`
+(
+    delivery_id BINARY(16),
+    product_id  BINARY(16),
+    quantity    INT,
+    PRIMARY KEY (delivery_id, product_id),
+    CONSTRAINT delivery_item_delivery_fk FOREIGN KEY (delivery_id) REFERENCES `delivery` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
@@ -0,0 +1,46 @@
+ALTER TABLE `order` ADD COLUMN promo_code VARCHAR(255) NULL AFTER address_id;
+ALTER TABLE `order_item` ADD COLUMN discount BIGINT DEFAULT 0;
+CREATE TABLE `promo_code`
+(
+    code              VARCHAR(255) PRIMARY KEY,
+    discount_type     TINYINT,
+    discount_value    BIGINT,
+    min_basket_amount BIGINT,
+    per_user_limit    INT,
+    valid_from        DATETIME,
+    valid_to          DATETIME,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `promo_code_product`
+(
+    code       VARCHAR(255),
+    product_id BINARY(16),
+    PRIMARY KEY (code, product_id),
+    FOREIGN KEY (code) REFERENCES `promo_code` (code)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `promo_code_category`
+(
+    code        VARCHAR(255),
+    category_id BINARY(16),
+    PRIMARY KEY (code, category_id),
+    FOREIGN KEY (code) REFERENCES `promo_code` (code)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `promo_code_usage`
+(
+    order_id   BINARY(16) PRIMARY KEY,
+    code       VARCHAR(255),
+    user_id    BINARY(16),
+    status     TINYINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (code, user_id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
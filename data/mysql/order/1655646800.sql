@@ -0,0 +1,2 @@
+ALTER TABLE `order` ADD COLUMN risk_reasons VARCHAR(255) NULL AFTER total_amount;
+ALTER TABLE `order` ADD INDEX (user_id, created_at)
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `order` ADD COLUMN delivery_slot_id BINARY(16) NULL AFTER address_id
\ No newline at end of file
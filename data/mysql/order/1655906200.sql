@@ -0,0 +1 @@
+ALTER TABLE `order` ADD COLUMN shipping_fee BIGINT NOT NULL DEFAULT 0 AFTER promo_code
\ No newline at end of file
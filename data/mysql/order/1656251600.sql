@@ -0,0 +1 @@
+ALTER TABLE `order` ADD COLUMN pickup_point_id BINARY(16) NULL AFTER delivery_slot_id
\ No newline at end of file
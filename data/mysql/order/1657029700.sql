@@ -0,0 +1 @@
+ALTER TABLE `order_item` ADD COLUMN variant_id BINARY(16) NULL AFTER id, ADD COLUMN warehouse_item_id BINARY(16) NULL AFTER variant_id
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `order` ADD COLUMN idempotence_key VARCHAR(255) NULL AFTER id, ADD UNIQUE INDEX idempotence_key_idx (idempotence_key)
\ No newline at end of file
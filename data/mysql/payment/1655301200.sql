@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS `idk`
+(
+    `key` VARCHAR(255) PRIMARY KEY
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
@@ -0,0 +1,23 @@
+CREATE TABLE `reconciliation_run`
+(
+    id              BINARY(16) PRIMARY KEY,
+    settlement_date DATE,
+    records_count   INT,
+    matched_count   INT,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `reconciliation_mismatch`
+(
+    run_id            BINARY(16),
+    order_id          BINARY(16),
+    type              TINYINT,
+    payment_status    TINYINT NULL,
+    payment_amount    BIGINT NULL,
+    settlement_status TINYINT NULL,
+    settlement_amount BIGINT NULL,
+    FOREIGN KEY (run_id) REFERENCES `reconciliation_run` (id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
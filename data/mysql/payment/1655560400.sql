@@ -0,0 +1,34 @@
+ALTER TABLE `payment` ADD COLUMN user_id BINARY(16) NULL AFTER order_id;
+ALTER TABLE `payment` ADD COLUMN credit_amount BIGINT DEFAULT 0 AFTER total_amount;
+CREATE TABLE `store_credit`
+(
+    user_id    BINARY(16) PRIMARY KEY,
+    balance    BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `store_credit_operation`
+(
+    id             INT AUTO_INCREMENT PRIMARY KEY,
+    user_id        BINARY(16),
+    type           TINYINT,
+    amount         BIGINT,
+    order_id       BINARY(16) NULL,
+    gift_card_code VARCHAR(255) NULL,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX (user_id)
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci;
+CREATE TABLE `gift_card`
+(
+    code        VARCHAR(255) PRIMARY KEY,
+    amount      BIGINT,
+    redeemed_by BINARY(16) NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE = InnoDB
+  CHARACTER SET utf8mb4
+  COLLATE utf8mb4_unicode_ci
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `payment` ADD COLUMN refunded_amount BIGINT NOT NULL DEFAULT 0 AFTER credit_amount
\ No newline at end of file
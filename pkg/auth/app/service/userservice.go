@@ -3,12 +3,14 @@ package service
 import (
 	"errors"
 	"github.com/google/uuid"
+	"time"
 )
 
 type User struct {
 	ID              uuid.UUID
 	Login           string
 	EncodedPassword string
+	CreatedAt       time.Time
 }
 
 var ErrUserByLoginAlreadyExists = errors.New("user with specified login exists")
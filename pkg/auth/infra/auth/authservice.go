@@ -20,20 +20,39 @@ type SessionService struct {
 }
 
 func (s *SessionService) Auth(r *http.Request, w http.ResponseWriter) {
+	s.auth(r, w, false)
+}
+
+func (s *SessionService) OptionalAuth(r *http.Request, w http.ResponseWriter) {
+	s.auth(r, w, true)
+}
+
+func (s *SessionService) auth(r *http.Request, w http.ResponseWriter, optional bool) {
+	unauthorized := func() {
+		if optional {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
 	cookie, err := r.Cookie(SessionIDCookieName)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		unauthorized()
 		return
 	}
 
 	session, err := s.sessionStorage.Get(cookie.Value)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		unauthorized()
 		return
 	}
 
 	w.Header().Set("X-Auth-User-ID", session.UserID.String())
 	w.Header().Set("X-Auth-User-Login", session.Login)
+	if !session.UserRegisteredAt.IsZero() {
+		w.Header().Set("X-Auth-User-Registered-At", session.UserRegisteredAt.Format(time.RFC3339))
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -52,8 +71,9 @@ func (s *SessionService) Login(login, password string, w http.ResponseWriter) {
 
 	sessionId := s.generateSessionId()
 	err = s.sessionStorage.Add(sessionId, &Session{
-		UserID: user.ID,
-		Login:  user.Login,
+		UserID:           user.ID,
+		Login:            user.Login,
+		UserRegisteredAt: user.CreatedAt,
 	}, sessionLifetime)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
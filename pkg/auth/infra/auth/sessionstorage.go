@@ -7,8 +7,9 @@ import (
 )
 
 type Session struct {
-	UserID uuid.UUID
-	Login  string
+	UserID           uuid.UUID
+	Login            string
+	UserRegisteredAt time.Time
 }
 
 var ErrSessionNotFound = errors.New("session not found")
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/auth/app/service"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"time"
 )
 
 type userRepo struct {
@@ -17,7 +18,7 @@ func (r *userRepo) NextID() uuid.UUID {
 }
 
 func (r *userRepo) GetByLogin(login string) (*service.User, error) {
-	const query = "SELECT id, login, encoded_password FROM user WHERE login = ?"
+	const query = "SELECT id, login, encoded_password, created_at FROM user WHERE login = ?"
 
 	var userSqlx sqlxUser
 	err := r.client.Get(&userSqlx, query, login)
@@ -32,6 +33,7 @@ func (r *userRepo) GetByLogin(login string) (*service.User, error) {
 		ID:              userSqlx.ID,
 		Login:           userSqlx.Login,
 		EncodedPassword: userSqlx.EncodedPassword,
+		CreatedAt:       userSqlx.CreatedAt,
 	}, nil
 }
 
@@ -60,4 +62,5 @@ type sqlxUser struct {
 	ID              uuid.UUID `db:"id"`
 	Login           string    `db:"login"`
 	EncodedPassword string    `db:"encoded_password"`
+	CreatedAt       time.Time `db:"created_at"`
 }
@@ -11,8 +11,9 @@ import (
 )
 
 type sessionJSONSchema struct {
-	UserID uuid.UUID `json:"user_id"`
-	Login  string    `json:"login"`
+	UserID           uuid.UUID `json:"user_id"`
+	Login            string    `json:"login"`
+	UserRegisteredAt time.Time `json:"user_registered_at"`
 }
 
 type sessionStorage struct {
@@ -57,8 +58,9 @@ func (s *sessionStorage) getSessionKey(sessionID string) string {
 
 func (s *sessionStorage) encodeSession(session *auth.Session) string {
 	bytes, _ := json.Marshal(sessionJSONSchema{
-		UserID: session.UserID,
-		Login:  session.Login,
+		UserID:           session.UserID,
+		Login:            session.Login,
+		UserRegisteredAt: session.UserRegisteredAt,
 	})
 	return string(bytes)
 }
@@ -71,8 +73,9 @@ func (s *sessionStorage) decodeSession(session string) (*auth.Session, error) {
 	}
 
 	return &auth.Session{
-		UserID: result.UserID,
-		Login:  result.Login,
+		UserID:           result.UserID,
+		Login:            result.Login,
+		UserRegisteredAt: result.UserRegisteredAt,
 	}, nil
 }
 
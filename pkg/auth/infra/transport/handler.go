@@ -33,6 +33,12 @@ func getRoutes() []route {
 			"/auth",
 			authHandler,
 		},
+		{
+			"optionalAuth",
+			http.MethodGet,
+			"/auth/optional",
+			optionalAuthHandler,
+		},
 		{
 			"login",
 			http.MethodPost,
@@ -64,6 +70,10 @@ func authHandler(_ *service.UserService, sessionService *auth.SessionService, w
 	sessionService.Auth(r, w)
 }
 
+func optionalAuthHandler(_ *service.UserService, sessionService *auth.SessionService, w http.ResponseWriter, r *http.Request) {
+	sessionService.OptionalAuth(r, w)
+}
+
 func loginHandler(_ *service.UserService, sessionService *auth.SessionService, w http.ResponseWriter, r *http.Request) {
 	var credentials credentials
 	err := json.NewDecoder(r.Body).Decode(&credentials)
@@ -0,0 +1,41 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+)
+
+type productBackInStockHandler struct {
+	service *service.WishlistService
+}
+
+func (h *productBackInStockHandler) TopicName() string {
+	return productEventTopicName
+}
+
+func (h *productBackInStockHandler) Type() string {
+	return "product_back_in_stock"
+}
+
+func (h *productBackInStockHandler) Handle(msg *message.Message) error {
+	var body struct {
+		ProductID uuid.UUID `json:"product_id"`
+	}
+	err := json.Unmarshal(msg.Body, &body)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.NotifyBackInStock(body.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to notify wishlists about product back in stock: %w", err)
+	}
+	return nil
+}
+
+func NewProductBackInStockHandler(service *service.WishlistService) message.Handler {
+	return &productBackInStockHandler{service: service}
+}
@@ -0,0 +1,43 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+)
+
+type productPriceChangedHandler struct {
+	service *service.WishlistService
+}
+
+func (h *productPriceChangedHandler) TopicName() string {
+	return productEventTopicName
+}
+
+func (h *productPriceChangedHandler) Type() string {
+	return "product_price_changed"
+}
+
+func (h *productPriceChangedHandler) Handle(msg *message.Message) error {
+	var body struct {
+		ProductID uuid.UUID `json:"product_id"`
+		OldPrice  int       `json:"old_price"`
+		NewPrice  int       `json:"new_price"`
+	}
+	err := json.Unmarshal(msg.Body, &body)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.NotifyPriceChanged(body.ProductID, body.OldPrice, body.NewPrice)
+	if err != nil {
+		return fmt.Errorf("failed to notify wishlists about price change: %w", err)
+	}
+	return nil
+}
+
+func NewProductPriceChangedHandler(service *service.WishlistService) message.Handler {
+	return &productPriceChangedHandler{service: service}
+}
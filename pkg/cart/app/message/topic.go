@@ -0,0 +1,3 @@
+package message
+
+const productEventTopicName = "product_event"
@@ -0,0 +1,17 @@
+package persistence
+
+import (
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+)
+
+type PersistentProvider interface {
+	WishlistRepository() domain.WishlistRepository
+	WishlistEventAPI() async.WishlistEventAPI
+	AbandonedCartRepository() domain.AbandonedCartRepository
+	CartEventAPI() async.CartEventAPI
+}
+
+type UnitOfWork interface {
+	Execute(f func(p PersistentProvider) error) error
+}
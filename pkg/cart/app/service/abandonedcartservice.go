@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"time"
+)
+
+const abandonedCartsBatchSize = 100
+
+type AbandonedCartService struct {
+	ufw        persistence.UnitOfWork
+	expiration domain.ExpirationPolicy
+	idlePeriod time.Duration
+	logger     log.Logger
+}
+
+func (s *AbandonedCartService) NotifyAbandonedCarts() (int, error) {
+	var total int
+	for {
+		count, err := s.notifyAbandonedCartsBatch()
+		total += count
+		if err != nil {
+			s.logger.WithError(err).Error("failed to notify abandoned carts")
+			return total, err
+		}
+		if count < abandonedCartsBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *AbandonedCartService) notifyAbandonedCartsBatch() (int, error) {
+	var count int
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		now := time.Now()
+		expiredBefore := time.Time{}
+		if ttl := s.expiration.TTL(domain.CartOwner{}); ttl > 0 {
+			expiredBefore = now.Add(-ttl)
+		}
+
+		carts, err := p.AbandonedCartRepository().LockAbandoned(now.Add(-s.idlePeriod), expiredBefore, abandonedCartsBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get abandoned carts: %w", err)
+		}
+
+		for i := range carts {
+			cart := &carts[i]
+			err = p.AbandonedCartRepository().MarkAbandoned(cart.Owner, now)
+			if err != nil {
+				return fmt.Errorf("failed to mark cart abandoned: %w", err)
+			}
+			if len(cart.Products) == 0 {
+				continue
+			}
+
+			err = p.CartEventAPI().NotifyCartAbandoned(cart)
+			if err != nil {
+				return fmt.Errorf("failed to notify cart abandoned: %w", err)
+			}
+		}
+		count = len(carts)
+		return nil
+	})
+	return count, err
+}
+
+func NewAbandonedCartService(
+	ufw persistence.UnitOfWork,
+	expiration domain.ExpirationPolicy,
+	idlePeriod time.Duration,
+	logger log.Logger,
+) *AbandonedCartService {
+	return &AbandonedCartService{
+		ufw:        ufw,
+		expiration: expiration,
+		idlePeriod: idlePeriod,
+		logger:     logger,
+	}
+}
@@ -6,8 +6,30 @@ import (
 )
 
 type Product struct {
-	ID    uuid.UUID
-	Price int
+	ID          uuid.UUID
+	Title       string
+	Price       int
+	Weight      int
+	MaxQuantity int
+	CategoryIDs []uuid.UUID
+	Variants    []ProductVariant
+}
+
+type ProductVariant struct {
+	ID              uuid.UUID
+	SKU             string
+	Options         map[string]string
+	Price           int
+	WarehouseItemID uuid.UUID
+}
+
+func (p *Product) GetVariant(variantID uuid.UUID) (*ProductVariant, bool) {
+	for i := range p.Variants {
+		if p.Variants[i].ID == variantID {
+			return &p.Variants[i], true
+		}
+	}
+	return nil, false
 }
 
 var ErrProductsNotFound = errors.New("one or more products are not found")
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"errors"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAddressNotFound     = errors.New("address not found")
+	ErrShippingUnavailable = errors.New("shipping is unavailable")
+	ErrPickupPointNotFound = errors.New("pickup point not found")
+)
+
+type DeliveryAPI interface {
+	ValidateAddress(userID, addressID uuid.UUID) error
+	GetDefaultAddressID(userID uuid.UUID) (uuid.UUID, error)
+	QuoteShipping(userID, addressID uuid.UUID, weight, orderAmount int) (int, error)
+	QuotePickupShipping(pickupPointID uuid.UUID, weight, orderAmount int) (int, error)
+}
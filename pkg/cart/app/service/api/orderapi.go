@@ -1,22 +1,51 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"time"
 )
 
+var ErrOrderAlreadyCreated = errors.New("order with idempotence key is already created")
+
 type CreateOrderProductData struct {
-	ID           uuid.UUID
-	ProductPrice int
-	Quantity     int
+	ID              uuid.UUID
+	VariantID       uuid.UUID
+	WarehouseItemID uuid.UUID
+	ProductPrice    int
+	Weight          int
+	Quantity        int
+	CategoryIDs     []uuid.UUID
 }
 
 type CreateOrderData struct {
-	IdempotenceKey string
-	UserID         uuid.UUID
-	AddressID      uuid.UUID
-	Products       []CreateOrderProductData
+	IdempotenceKey   string
+	UserID           uuid.UUID
+	UserRegisteredAt time.Time
+	AddressID        uuid.UUID
+	PickupPointID    uuid.UUID
+	DeliverySlotID   uuid.UUID
+	ShippingFee      int
+	Products         []CreateOrderProductData
+	PromoCode        string
+}
+
+type ProductDiscount struct {
+	ID        uuid.UUID
+	VariantID uuid.UUID
+	Discount  int
+}
+
+type PromoCodeRejectedError struct {
+	Reason string
+}
+
+func (e *PromoCodeRejectedError) Error() string {
+	return fmt.Sprintf("promo code rejected: %s", e.Reason)
 }
 
 type OrderAPI interface {
 	CreateOrder(data *CreateOrderData) (orderID uuid.UUID, err error)
+	CalculateDiscounts(userID uuid.UUID, promoCode string, products []CreateOrderProductData) ([]ProductDiscount, error)
 }
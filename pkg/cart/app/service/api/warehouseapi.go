@@ -0,0 +1,12 @@
+package api
+
+import (
+	"errors"
+	"github.com/google/uuid"
+)
+
+var ErrItemsNotFound = errors.New("one or more items are not found")
+
+type WarehouseAPI interface {
+	GetAvailableQuantity(itemIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
@@ -0,0 +1,7 @@
+package async
+
+import "github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+
+type CartEventAPI interface {
+	NotifyCartAbandoned(cart *domain.Cart) error
+}
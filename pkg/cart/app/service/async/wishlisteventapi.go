@@ -0,0 +1,8 @@
+package async
+
+import "github.com/google/uuid"
+
+type WishlistEventAPI interface {
+	NotifyPriceDropped(userID, productID uuid.UUID, oldPrice, newPrice int) error
+	NotifyBackInStock(userID, productID uuid.UUID) error
+}
@@ -7,192 +7,861 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/api"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"time"
 )
 
 var (
-	ErrInvalidQuantity   = errors.New("invalid product quantity")
-	ErrInvalidProduct    = errors.New("invalid product id")
-	ErrEmptyCartCheckout = errors.New("user has empty cart to checkout")
+	ErrInvalidQuantity     = errors.New("invalid product quantity")
+	ErrInvalidProduct      = errors.New("invalid product id")
+	ErrInvalidVariant      = errors.New("invalid product variant")
+	ErrEmptyCartCheckout   = errors.New("user has empty cart to checkout")
+	ErrInvalidAddress      = errors.New("invalid delivery address")
+	ErrInvalidPickupPoint  = errors.New("invalid pickup point")
+	ErrShippingUnavailable = errors.New("shipping is unavailable")
+	ErrInvalidQuote        = errors.New("invalid quote")
+	ErrQuoteExpired        = errors.New("quote expired")
+	ErrCartChanged         = errors.New("cart changed since quote")
+	ErrDiscountChanged     = errors.New("discount changed since quote")
+	ErrCheckoutInProgress  = errors.New("checkout with idempotence key is in progress")
+	ErrQuantityLimit       = errors.New("product quantity limit exceeded")
+	ErrNotEnoughStock      = errors.New("not enough product in stock")
 )
 
+type UnavailableProductsError struct {
+	ProductIDs []uuid.UUID
+}
+
+func (e *UnavailableProductsError) Error() string {
+	return fmt.Sprintf("cart has unavailable products: %v", e.ProductIDs)
+}
+
+type CartItemPreview struct {
+	ProductID         uuid.UUID
+	VariantID         uuid.UUID
+	SKU               string
+	Options           map[string]string
+	Title             string
+	ItemPrice         int
+	AddedPrice        int
+	Quantity          int
+	LineTotal         int
+	AvailableQuantity int
+	MaxQuantity       int
+}
+
+func (p CartItemPreview) Available() bool {
+	return p.AvailableQuantity >= p.Quantity
+}
+
+func (p CartItemPreview) QuantityLimitExceeded() bool {
+	return p.MaxQuantity > 0 && p.Quantity > p.MaxQuantity
+}
+
+func (p CartItemPreview) PriceChanged() bool {
+	return p.AddedPrice != 0 && p.AddedPrice != p.ItemPrice
+}
+
+type CartPreview struct {
+	Items       []CartItemPreview
+	TotalAmount int
+}
+
+type CartLinePreview struct {
+	ProductID uuid.UUID
+	VariantID uuid.UUID
+	ItemPrice int
+	Quantity  int
+	Discount  int
+}
+
+type CartDiscountPreview struct {
+	Lines       []CartLinePreview
+	Amount      int
+	Discount    int
+	TotalAmount int
+}
+
+type ShippingQuote struct {
+	AddressID     uuid.UUID
+	PickupPointID uuid.UUID
+	Weight        int
+	OrderAmount   int
+	ShippingFee   int
+}
+
+type QuoteData struct {
+	UserID        uuid.UUID
+	AddressID     uuid.UUID
+	PickupPointID uuid.UUID
+	PromoCode     string
+}
+
+type CheckoutData struct {
+	IdempotenceKey   string
+	UserID           uuid.UUID
+	UserRegisteredAt time.Time
+	AddressID        uuid.UUID
+	PickupPointID    uuid.UUID
+	DeliverySlotID   uuid.UUID
+	PromoCode        string
+	QuoteToken       string
+}
+
 type CartService struct {
-	catalogAPI api.CatalogAPI
-	orderAPI   api.OrderAPI
-	repo       domain.CartStorage
-	logger     log.Logger
+	catalogAPI    api.CatalogAPI
+	orderAPI      api.OrderAPI
+	deliveryAPI   api.DeliveryAPI
+	warehouseAPI  api.WarehouseAPI
+	repo          domain.CartStorage
+	checkoutKeys  domain.CheckoutKeyStorage
+	mergeStrategy domain.MergeStrategy
+	quoteEncoder  domain.QuoteTokenEncoder
+	quoteTTL      time.Duration
+	logger        log.Logger
 }
 
-func (s *CartService) GetCart(userID uuid.UUID) (*domain.Cart, error) {
-	return s.repo.GetByUserID(userID)
+func (s *CartService) GetCartPreview(owner domain.CartOwner) (*CartPreview, error) {
+	cart, err := s.repo.GetByOwner(owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user cart: %w", err)
+	}
+	if len(cart.Products) == 0 {
+		return &CartPreview{}, nil
+	}
+
+	preview, err := func() (*CartPreview, error) {
+		products, err := s.getCartLineProducts(cart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cart products: %w", err)
+		}
+
+		availableQuantity, err := s.getAvailableQuantity(getStockItemIDs(products))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cart products availability: %w", err)
+		}
+
+		result := &CartPreview{Items: make([]CartItemPreview, 0, len(cart.Products))}
+		for _, cartProduct := range cart.Products {
+			product := products[cartProduct.Line()]
+			item := CartItemPreview{
+				ProductID:   cartProduct.ID,
+				VariantID:   cartProduct.VariantID,
+				Title:       product.Title,
+				ItemPrice:   product.ItemPrice(),
+				AddedPrice:  cartProduct.AddedPrice,
+				Quantity:    cartProduct.Quantity,
+				LineTotal:   product.ItemPrice() * cartProduct.Quantity,
+				MaxQuantity: product.MaxQuantity,
+			}
+			if !product.VariantMissing {
+				item.AvailableQuantity = availableQuantity[product.StockItemID()]
+			}
+			if product.Variant != nil {
+				item.SKU = product.Variant.SKU
+				item.Options = product.Variant.Options
+			}
+			result.Items = append(result.Items, item)
+			result.TotalAmount += item.LineTotal
+		}
+		return result, nil
+	}()
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"ownerID": owner.ID, "guest": owner.Guest}).Error("failed to get cart preview")
+	}
+	return preview, err
 }
 
-func (s *CartService) AddProduct(userID, productID uuid.UUID, expectedQuantity int) error {
+func (s *CartService) AddProduct(owner domain.CartOwner, productID, variantID uuid.UUID, expectedQuantity int) error {
 	if expectedQuantity <= 0 {
 		return ErrInvalidQuantity
 	}
 
-	err := s.validateProductID(productID)
+	product, err := s.getProduct(productID)
 	if err != nil {
 		return err
 	}
-
-	cart, err := s.repo.GetByUserID(userID)
+	lineProduct, err := getCartLineProduct(product, variantID)
 	if err != nil {
-		return fmt.Errorf("failed to get user cart: %w", err)
+		return err
+	}
+	if product.MaxQuantity > 0 && expectedQuantity > product.MaxQuantity {
+		return ErrQuantityLimit
 	}
 
-	for i, product := range cart.Products {
-		if product.ID != productID {
-			continue
-		}
+	stockItemID := lineProduct.StockItemID()
+	availableQuantity, err := s.getAvailableQuantity([]uuid.UUID{stockItemID})
+	if err != nil {
+		return fmt.Errorf("failed to get product availability: %w", err)
+	}
+	if availableQuantity[stockItemID] < expectedQuantity {
+		return ErrNotEnoughStock
+	}
 
-		cart.Products[i].Quantity = expectedQuantity
-		err := s.repo.Store(cart)
-		if err != nil {
-			return fmt.Errorf("failed to update product quantity: %w", err)
-		}
+	err = s.repo.Update(owner, func(cart *domain.Cart) error {
+		cart.SetProduct(domain.ProductQuantity{
+			ID:         productID,
+			VariantID:  variantID,
+			Quantity:   expectedQuantity,
+			AddedPrice: lineProduct.ItemPrice(),
+		})
 		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"ownerID": owner.ID, "guest": owner.Guest, "productID": productID, "variantID": variantID}).Error("failed to add product")
 	}
+	return err
+}
 
-	cart.Products = append(cart.Products, domain.ProductQuantity{
-		ID:       productID,
-		Quantity: expectedQuantity,
+func (s *CartService) DeleteProduct(owner domain.CartOwner, productID, variantID uuid.UUID) error {
+	err := s.repo.Update(owner, func(cart *domain.Cart) error {
+		cart.RemoveProduct(domain.CartLine{ProductID: productID, VariantID: variantID})
+		return nil
 	})
+	if err != nil {
+		return fmt.Errorf("failed to delete product from cart: %w", err)
+	}
+	return nil
+}
 
-	err = s.repo.Store(cart)
+func (s *CartService) MergeGuestCart(guestID, userID uuid.UUID) error {
+	// the guest cart is taken away first, so concurrent merges can't apply it twice
+	guestCart, err := s.repo.Take(domain.GuestCartOwner(guestID))
 	if err != nil {
-		s.logger.WithError(err).With(log.Fields{"userID": userID, "productID": productID}).Error("failed to add product")
+		return fmt.Errorf("failed to take guest cart: %w", err)
+	}
+	if len(guestCart.Products) == 0 {
+		return nil
+	}
+
+	err = s.repo.Update(domain.UserCartOwner(userID), func(cart *domain.Cart) error {
+		return cart.Merge(guestCart, s.mergeStrategy)
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"userID": userID, "guestID": guestID}).Error("failed to merge guest cart")
+		restoreErr := s.repo.Store(guestCart)
+		if restoreErr != nil {
+			s.logger.WithError(restoreErr).With(log.Fields{"guestID": guestID}).Error("failed to restore guest cart")
+		}
 	}
 	return err
 }
 
-func (s *CartService) DeleteProduct(userID, productID uuid.UUID) error {
-	cart, err := s.repo.GetByUserID(userID)
+func (s *CartService) GetDiscountPreview(userID uuid.UUID, promoCode string) (*CartDiscountPreview, error) {
+	cart, err := s.repo.GetByOwner(domain.UserCartOwner(userID))
 	if err != nil {
-		return fmt.Errorf("failed to get user cart: %w", err)
+		return nil, fmt.Errorf("failed to get user cart: %w", err)
+	}
+	if len(cart.Products) == 0 {
+		return &CartDiscountPreview{}, nil
 	}
 
-	for i := 0; i < len(cart.Products); i++ {
-		prod := &cart.Products[i]
-		if prod.ID == productID {
-			cart.Products[i] = cart.Products[len(cart.Products)-1]
-			cart.Products = cart.Products[:len(cart.Products)-1]
-			err := s.repo.Store(cart)
+	orderProducts, err := s.getOrderProducts(cart)
+	if err != nil {
+		return nil, err
+	}
+
+	discountByLine, err := s.calculateDiscounts(userID, promoCode, orderProducts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CartDiscountPreview{Lines: make([]CartLinePreview, 0, len(orderProducts))}
+	for _, product := range orderProducts {
+		line := CartLinePreview{
+			ProductID: product.ID,
+			VariantID: product.VariantID,
+			ItemPrice: product.ProductPrice,
+			Quantity:  product.Quantity,
+			Discount:  discountByLine[getOrderProductLine(product)],
+		}
+		result.Lines = append(result.Lines, line)
+		result.Amount += line.ItemPrice * line.Quantity
+		result.Discount += line.Discount
+	}
+	result.TotalAmount = result.Amount - result.Discount
+	return result, nil
+}
+
+func (s *CartService) GetShippingQuote(userID, addressID, pickupPointID uuid.UUID) (*ShippingQuote, error) {
+	cart, err := s.repo.GetByOwner(domain.UserCartOwner(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user cart: %w", err)
+	}
+
+	if pickupPointID == uuid.Nil {
+		addressID, err = s.resolveAddressID(userID, addressID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(cart.Products) == 0 {
+		return &ShippingQuote{AddressID: addressID, PickupPointID: pickupPointID}, nil
+	}
+
+	orderProducts, err := s.getOrderProducts(cart)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.quoteShipping(userID, addressID, pickupPointID, orderProducts)
+	if err != nil && !isShippingError(err) {
+		s.logger.WithError(err).With(log.Fields{"userID": userID}).Error("failed to quote shipping")
+	}
+	return quote, err
+}
+
+func (s *CartService) CreateQuote(data *QuoteData) (*domain.Quote, string, error) {
+	quote, err := func() (*domain.Quote, error) {
+		cart, err := s.repo.GetByOwner(domain.UserCartOwner(data.UserID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user cart: %w", err)
+		}
+		if len(cart.Products) == 0 {
+			return nil, ErrEmptyCartCheckout
+		}
+		err = s.checkCartAvailable(cart)
+		if err != nil {
+			return nil, err
+		}
+
+		addressID := data.AddressID
+		if data.PickupPointID == uuid.Nil {
+			addressID, err = s.resolveAddressID(data.UserID, addressID)
 			if err != nil {
-				return fmt.Errorf("failed to delete product from cart: %w", err)
+				return nil, err
 			}
-			return nil
 		}
+
+		orderProducts, err := s.getOrderProducts(cart)
+		if err != nil {
+			return nil, err
+		}
+
+		var discountByLine map[domain.CartLine]int
+		if data.PromoCode != "" {
+			discountByLine, err = s.calculateDiscounts(data.UserID, data.PromoCode, orderProducts)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		shippingQuote, err := s.quoteShipping(data.UserID, addressID, data.PickupPointID, orderProducts)
+		if err != nil {
+			return nil, err
+		}
+
+		quote := &domain.Quote{
+			UserID:        data.UserID,
+			AddressID:     shippingQuote.AddressID,
+			PickupPointID: shippingQuote.PickupPointID,
+			PromoCode:     data.PromoCode,
+			Products:      make([]domain.QuoteProduct, 0, len(orderProducts)),
+			ShippingFee:   shippingQuote.ShippingFee,
+			ExpiresAt:     time.Now().Add(s.quoteTTL),
+		}
+		for _, product := range orderProducts {
+			quote.Products = append(quote.Products, domain.QuoteProduct{
+				ID:              product.ID,
+				VariantID:       product.VariantID,
+				WarehouseItemID: product.WarehouseItemID,
+				Price:           product.ProductPrice,
+				Weight:          product.Weight,
+				Quantity:        product.Quantity,
+				Discount:        discountByLine[getOrderProductLine(product)],
+				CategoryIDs:     product.CategoryIDs,
+			})
+		}
+		return quote, nil
+	}()
+	var rejectedErr *api.PromoCodeRejectedError
+	var unavailableErr *UnavailableProductsError
+	if errors.Is(err, ErrEmptyCartCheckout) ||
+		isShippingError(err) ||
+		errors.Is(err, ErrQuantityLimit) ||
+		errors.As(err, &unavailableErr) ||
+		errors.As(err, &rejectedErr) {
+		return nil, "", err
 	}
-	return nil
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"userID": data.UserID}).Error("failed to create quote")
+		return nil, "", err
+	}
+
+	token, err := s.quoteEncoder.Encode(quote)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode quote: %w", err)
+	}
+	return quote, token, nil
 }
 
-func (s *CartService) Checkout(userID, addressID uuid.UUID) (uuid.UUID, error) {
+func (s *CartService) Checkout(data *CheckoutData) (uuid.UUID, error) {
 	var orderID uuid.UUID
 	err := func() error {
-		// TODO: validate addressID in delivery service
+		var err error
+		orderID, err = s.checkoutKeys.GetOrderID(data.UserID, data.IdempotenceKey)
+		if err != nil {
+			return fmt.Errorf("failed to get checkout by idempotence key: %w", err)
+		}
+		if orderID != uuid.Nil {
+			return nil
+		}
 
-		cart, err := s.repo.GetByUserID(userID)
+		cart, err := s.repo.GetByOwner(domain.UserCartOwner(data.UserID))
 		if err != nil {
 			return fmt.Errorf("failed to get user cart: %w", err)
 		}
 		if len(cart.Products) == 0 {
 			return ErrEmptyCartCheckout
 		}
+		err = s.checkCartAvailable(cart)
+		if err != nil {
+			return err
+		}
 
-		orderID, err = s.createOrder(cart, userID, addressID)
+		promoCode := data.PromoCode
+		var quote *ShippingQuote
+		var orderProducts []api.CreateOrderProductData
+		if data.QuoteToken != "" {
+			var lockedQuote *domain.Quote
+			lockedQuote, err = s.getLockedQuote(data, cart)
+			if err != nil {
+				return err
+			}
+			promoCode = lockedQuote.PromoCode
+			quote = &ShippingQuote{
+				AddressID:     lockedQuote.AddressID,
+				PickupPointID: lockedQuote.PickupPointID,
+				ShippingFee:   lockedQuote.ShippingFee,
+			}
+			orderProducts = getQuoteOrderProducts(lockedQuote)
+		} else {
+			quote, orderProducts, err = s.getCurrentOrder(data, cart)
+			if err != nil {
+				return err
+			}
+		}
+
+		orderID, err = s.createOrder(data, promoCode, quote, orderProducts)
+		if errors.Is(err, api.ErrOrderAlreadyCreated) {
+			return ErrCheckoutInProgress
+		}
 		if err != nil {
 			return fmt.Errorf("failed to checkout: %w", err)
 		}
 
-		_ = s.repo.Delete(userID)
+		err = s.checkoutKeys.Store(data.UserID, data.IdempotenceKey, orderID)
+		if err != nil {
+			return fmt.Errorf("failed to store checkout idempotence key: %w", err)
+		}
+
+		err = s.repo.Delete(domain.UserCartOwner(data.UserID))
+		if err != nil {
+			s.logger.WithError(err).With(log.Fields{"userID": data.UserID, "orderID": orderID}).Error("failed to clear cart after checkout")
+		}
 		return nil
 	}()
-	if errors.Is(err, ErrEmptyCartCheckout) {
-		return orderID, err
+	var rejectedErr *api.PromoCodeRejectedError
+	var unavailableErr *UnavailableProductsError
+	if errors.Is(err, ErrEmptyCartCheckout) ||
+		isShippingError(err) ||
+		isQuoteError(err) ||
+		errors.Is(err, ErrCheckoutInProgress) ||
+		errors.Is(err, ErrQuantityLimit) ||
+		errors.As(err, &unavailableErr) ||
+		errors.As(err, &rejectedErr) {
+		return uuid.Nil, err
 	}
 	if err != nil {
 		s.logger.WithError(err).With(log.Fields{
-			"userID": userID,
+			"userID": data.UserID,
 		}).Error("failed to checkout cart")
 		return uuid.UUID{}, err
 	}
 	return orderID, nil
 }
 
-func (s *CartService) validateProductID(id uuid.UUID) error {
-	_, err := s.catalogAPI.GetProducts([]uuid.UUID{id})
-	if errors.Is(err, api.ErrProductsNotFound) {
-		return ErrInvalidProduct
+func (s *CartService) getCurrentOrder(
+	data *CheckoutData,
+	cart *domain.Cart,
+) (*ShippingQuote, []api.CreateOrderProductData, error) {
+	var err error
+	addressID := data.AddressID
+	if data.PickupPointID == uuid.Nil {
+		addressID, err = s.resolveAddressID(data.UserID, addressID)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
-	return err
+
+	orderProducts, err := s.getOrderProducts(cart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	quote, err := s.quoteShipping(data.UserID, addressID, data.PickupPointID, orderProducts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return quote, orderProducts, nil
 }
 
-func (s *CartService) createOrder(cart *domain.Cart, userID, addressID uuid.UUID) (uuid.UUID, error) {
-	productIDs := make([]uuid.UUID, 0, len(cart.Products))
-	for _, product := range cart.Products {
-		productIDs = append(productIDs, product.ID)
+func (s *CartService) getLockedQuote(data *CheckoutData, cart *domain.Cart) (*domain.Quote, error) {
+	quote, err := s.quoteEncoder.Decode(data.QuoteToken)
+	if errors.Is(err, domain.ErrInvalidQuoteToken) || (err == nil && quote.UserID != data.UserID) {
+		return nil, ErrInvalidQuote
+	}
+	if err != nil {
+		return nil, err
+	}
+	if quote.IsExpired(time.Now()) {
+		return nil, ErrQuoteExpired
+	}
+	if !quote.MatchesCart(cart) {
+		return nil, ErrCartChanged
+	}
+	if quote.PromoCode == "" {
+		return quote, nil
 	}
 
-	products, err := s.catalogAPI.GetProducts(productIDs)
+	discountByLine, err := s.calculateDiscounts(data.UserID, quote.PromoCode, getQuoteOrderProducts(quote))
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range quote.Products {
+		if discountByLine[product.Line()] != product.Discount {
+			return nil, ErrDiscountChanged
+		}
+	}
+	return quote, nil
+}
+
+func getQuoteOrderProducts(quote *domain.Quote) []api.CreateOrderProductData {
+	orderProducts := make([]api.CreateOrderProductData, 0, len(quote.Products))
+	for _, product := range quote.Products {
+		orderProducts = append(orderProducts, api.CreateOrderProductData{
+			ID:              product.ID,
+			VariantID:       product.VariantID,
+			WarehouseItemID: product.WarehouseItemID,
+			ProductPrice:    product.Price,
+			Weight:          product.Weight,
+			Quantity:        product.Quantity,
+			CategoryIDs:     product.CategoryIDs,
+		})
+	}
+	return orderProducts
+}
+
+func getOrderProductLine(product api.CreateOrderProductData) domain.CartLine {
+	return domain.CartLine{ProductID: product.ID, VariantID: product.VariantID}
+}
+
+func (s *CartService) calculateDiscounts(
+	userID uuid.UUID,
+	promoCode string,
+	orderProducts []api.CreateOrderProductData,
+) (map[domain.CartLine]int, error) {
+	discounts, err := s.orderAPI.CalculateDiscounts(userID, promoCode, orderProducts)
 	if err != nil {
-		return uuid.UUID{}, fmt.Errorf("failed to get products for checkout: %w", err)
+		var rejectedErr *api.PromoCodeRejectedError
+		if !errors.As(err, &rejectedErr) {
+			s.logger.WithError(err).With(log.Fields{"userID": userID}).Error("failed to calculate cart discounts")
+		}
+		return nil, err
 	}
 
-	orderData, err := s.createOrderData(userID, addressID, cart, products)
+	discountByLine := make(map[domain.CartLine]int, len(discounts))
+	for _, discount := range discounts {
+		discountByLine[domain.CartLine{ProductID: discount.ID, VariantID: discount.VariantID}] = discount.Discount
+	}
+	return discountByLine, nil
+}
+
+func (s *CartService) resolveAddressID(userID, addressID uuid.UUID) (uuid.UUID, error) {
+	var err error
+	if addressID == uuid.Nil {
+		addressID, err = s.deliveryAPI.GetDefaultAddressID(userID)
+	} else {
+		err = s.deliveryAPI.ValidateAddress(userID, addressID)
+	}
+	if errors.Is(err, api.ErrAddressNotFound) {
+		return uuid.Nil, ErrInvalidAddress
+	}
 	if err != nil {
-		return uuid.UUID{}, err
+		return uuid.Nil, fmt.Errorf("failed to validate address: %w", err)
+	}
+	return addressID, nil
+}
+
+func isShippingError(err error) bool {
+	return errors.Is(err, ErrInvalidAddress) ||
+		errors.Is(err, ErrInvalidPickupPoint) ||
+		errors.Is(err, ErrShippingUnavailable)
+}
+
+func isQuoteError(err error) bool {
+	return errors.Is(err, ErrInvalidQuote) ||
+		errors.Is(err, ErrQuoteExpired) ||
+		errors.Is(err, ErrCartChanged) ||
+		errors.Is(err, ErrDiscountChanged)
+}
+
+func (s *CartService) getProduct(id uuid.UUID) (*api.Product, error) {
+	products, err := s.catalogAPI.GetProducts([]uuid.UUID{id})
+	if errors.Is(err, api.ErrProductsNotFound) || (err == nil && len(products) == 0) {
+		return nil, ErrInvalidProduct
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+func (s *CartService) checkCartAvailable(cart *domain.Cart) error {
+	products, err := s.getCartLineProducts(cart)
+	if err != nil {
+		return fmt.Errorf("failed to get cart products: %w", err)
+	}
+
+	availableQuantity, err := s.getAvailableQuantity(getStockItemIDs(products))
+	if err != nil {
+		return fmt.Errorf("failed to get cart products availability: %w", err)
+	}
+
+	var unavailable []uuid.UUID
+	for _, cartProduct := range cart.Products {
+		product := products[cartProduct.Line()]
+		if product.VariantMissing || availableQuantity[product.StockItemID()] < cartProduct.Quantity {
+			unavailable = append(unavailable, cartProduct.ID)
+		}
+	}
+	if len(unavailable) > 0 {
+		return &UnavailableProductsError{ProductIDs: unavailable}
+	}
+	return nil
+}
+
+func (s *CartService) getAvailableQuantity(itemIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	if len(itemIDs) == 0 {
+		return map[uuid.UUID]int{}, nil
+	}
+	quantity, err := s.warehouseAPI.GetAvailableQuantity(itemIDs)
+	if !errors.Is(err, api.ErrItemsNotFound) {
+		return quantity, err
+	}
+
+	// warehouse rejects the whole batch if any of the items has never been stocked
+	quantity = make(map[uuid.UUID]int, len(itemIDs))
+	for _, itemID := range itemIDs {
+		itemQuantity, err := s.warehouseAPI.GetAvailableQuantity([]uuid.UUID{itemID})
+		if errors.Is(err, api.ErrItemsNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		quantity[itemID] = itemQuantity[itemID]
+	}
+	return quantity, nil
+}
+
+func (s *CartService) quoteShipping(
+	userID, addressID, pickupPointID uuid.UUID,
+	orderProducts []api.CreateOrderProductData,
+) (*ShippingQuote, error) {
+	quote := &ShippingQuote{AddressID: addressID, PickupPointID: pickupPointID}
+	for _, product := range orderProducts {
+		quote.Weight += product.Weight * product.Quantity
+		quote.OrderAmount += product.ProductPrice * product.Quantity
+	}
+
+	var shippingFee int
+	var err error
+	if pickupPointID != uuid.Nil {
+		shippingFee, err = s.deliveryAPI.QuotePickupShipping(pickupPointID, quote.Weight, quote.OrderAmount)
+	} else {
+		shippingFee, err = s.deliveryAPI.QuoteShipping(userID, addressID, quote.Weight, quote.OrderAmount)
+	}
+	if errors.Is(err, api.ErrAddressNotFound) {
+		return nil, ErrInvalidAddress
+	}
+	if errors.Is(err, api.ErrPickupPointNotFound) {
+		return nil, ErrInvalidPickupPoint
+	}
+	if errors.Is(err, api.ErrShippingUnavailable) {
+		return nil, ErrShippingUnavailable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote shipping: %w", err)
 	}
 
-	orderID, err := s.orderAPI.CreateOrder(orderData)
+	quote.ShippingFee = shippingFee
+	return quote, nil
+}
+
+func (s *CartService) createOrder(
+	data *CheckoutData,
+	promoCode string,
+	quote *ShippingQuote,
+	orderProducts []api.CreateOrderProductData,
+) (uuid.UUID, error) {
+	orderID, err := s.orderAPI.CreateOrder(&api.CreateOrderData{
+		IdempotenceKey:   fmt.Sprintf("cart_checkout:%v:%s", data.UserID, data.IdempotenceKey),
+		UserID:           data.UserID,
+		UserRegisteredAt: data.UserRegisteredAt,
+		AddressID:        quote.AddressID,
+		PickupPointID:    quote.PickupPointID,
+		DeliverySlotID:   data.DeliverySlotID,
+		ShippingFee:      quote.ShippingFee,
+		Products:         orderProducts,
+		PromoCode:        promoCode,
+	})
 	if err != nil {
 		return uuid.UUID{}, fmt.Errorf("failed to create order: %w", err)
 	}
 	return orderID, nil
 }
 
-func (s *CartService) createOrderData(userID, addressID uuid.UUID, cart *domain.Cart, products []api.Product) (*api.CreateOrderData, error) {
-	findProductPrice := func(id uuid.UUID, products []api.Product) (int, error) {
-		for _, apiProduct := range products {
-			if id == apiProduct.ID {
-				return apiProduct.Price, nil
-			}
-		}
-		return 0, fmt.Errorf("failed to get product price for %v", id)
+func (s *CartService) getOrderProducts(cart *domain.Cart) ([]api.CreateOrderProductData, error) {
+	products, err := s.getCartLineProducts(cart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products for checkout: %w", err)
 	}
 
 	orderProducts := make([]api.CreateOrderProductData, 0, len(cart.Products))
+	var unavailable []uuid.UUID
 	for _, cartProduct := range cart.Products {
-		price, err := findProductPrice(cartProduct.ID, products)
-		if err != nil {
-			return nil, err
+		product := products[cartProduct.Line()]
+		if product.VariantMissing {
+			unavailable = append(unavailable, cartProduct.ID)
+			continue
+		}
+		if product.MaxQuantity > 0 && cartProduct.Quantity > product.MaxQuantity {
+			return nil, ErrQuantityLimit
 		}
 
-		orderProducts = append(orderProducts, api.CreateOrderProductData{
+		orderProduct := api.CreateOrderProductData{
 			ID:           cartProduct.ID,
-			ProductPrice: price,
+			ProductPrice: product.ItemPrice(),
+			Weight:       product.Weight,
 			Quantity:     cartProduct.Quantity,
-		})
+			CategoryIDs:  product.CategoryIDs,
+		}
+		if product.Variant != nil {
+			orderProduct.VariantID = product.Variant.ID
+			orderProduct.WarehouseItemID = product.Variant.WarehouseItemID
+		}
+		orderProducts = append(orderProducts, orderProduct)
+	}
+	if len(unavailable) > 0 {
+		return nil, &UnavailableProductsError{ProductIDs: unavailable}
 	}
+	return orderProducts, nil
+}
+
+func (s *CartService) getCartLineProducts(cart *domain.Cart) (map[domain.CartLine]cartLineProduct, error) {
+	productIDs := make([]uuid.UUID, 0, len(cart.Products))
+	seen := make(map[uuid.UUID]bool, len(cart.Products))
+	for _, product := range cart.Products {
+		if !seen[product.ID] {
+			seen[product.ID] = true
+			productIDs = append(productIDs, product.ID)
+		}
+	}
+
+	products, err := s.catalogAPI.GetProducts(productIDs)
+	if err != nil {
+		return nil, err
+	}
+	productByID := make(map[uuid.UUID]*api.Product, len(products))
+	for i := range products {
+		productByID[products[i].ID] = &products[i]
+	}
+
+	result := make(map[domain.CartLine]cartLineProduct, len(cart.Products))
+	for _, cartProduct := range cart.Products {
+		product, ok := productByID[cartProduct.ID]
+		if !ok {
+			return nil, fmt.Errorf("failed to get product price for %v", cartProduct.ID)
+		}
+		lineProduct, err := getCartLineProduct(product, cartProduct.VariantID)
+		if errors.Is(err, ErrInvalidVariant) {
+			lineProduct = cartLineProduct{Product: product, VariantMissing: true}
+		}
+		result[cartProduct.Line()] = lineProduct
+	}
+	return result, nil
+}
 
-	return &api.CreateOrderData{
-		IdempotenceKey: uuid.New().String(),
-		UserID:         userID,
-		AddressID:      addressID,
-		Products:       orderProducts,
-	}, nil
+type cartLineProduct struct {
+	*api.Product
+	Variant        *api.ProductVariant
+	VariantMissing bool
+}
+
+func (p cartLineProduct) ItemPrice() int {
+	if p.Variant != nil {
+		return p.Variant.Price
+	}
+	return p.Price
+}
+
+func (p cartLineProduct) StockItemID() uuid.UUID {
+	if p.Variant != nil {
+		return p.Variant.WarehouseItemID
+	}
+	return p.ID
+}
+
+func getCartLineProduct(product *api.Product, variantID uuid.UUID) (cartLineProduct, error) {
+	if len(product.Variants) == 0 {
+		if variantID != uuid.Nil {
+			return cartLineProduct{}, ErrInvalidVariant
+		}
+		return cartLineProduct{Product: product}, nil
+	}
+
+	variant, ok := product.GetVariant(variantID)
+	if !ok {
+		return cartLineProduct{}, ErrInvalidVariant
+	}
+	return cartLineProduct{Product: product, Variant: variant}, nil
+}
+
+func getStockItemIDs(products map[domain.CartLine]cartLineProduct) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(products))
+	seen := make(map[uuid.UUID]bool, len(products))
+	for _, product := range products {
+		if product.VariantMissing || seen[product.StockItemID()] {
+			continue
+		}
+		seen[product.StockItemID()] = true
+		result = append(result, product.StockItemID())
+	}
+	return result
 }
 
 func NewCartService(
 	catalogAPI api.CatalogAPI,
 	orderAPI api.OrderAPI,
+	deliveryAPI api.DeliveryAPI,
+	warehouseAPI api.WarehouseAPI,
 	repo domain.CartStorage,
+	checkoutKeys domain.CheckoutKeyStorage,
+	mergeStrategy domain.MergeStrategy,
+	quoteEncoder domain.QuoteTokenEncoder,
+	quoteTTL time.Duration,
 	logger log.Logger,
 ) *CartService {
 	return &CartService{
-		catalogAPI: catalogAPI,
-		orderAPI:   orderAPI,
-		repo:       repo,
-		logger:     logger,
+		catalogAPI:    catalogAPI,
+		orderAPI:      orderAPI,
+		deliveryAPI:   deliveryAPI,
+		warehouseAPI:  warehouseAPI,
+		repo:          repo,
+		checkoutKeys:  checkoutKeys,
+		mergeStrategy: mergeStrategy,
+		quoteEncoder:  quoteEncoder,
+		quoteTTL:      quoteTTL,
+		logger:        logger,
 	}
 }
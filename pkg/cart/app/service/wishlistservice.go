@@ -0,0 +1,357 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/api"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidWishlistName  = errors.New("invalid wishlist name")
+	ErrWishlistNotFound     = errors.New("wishlist not found")
+	ErrWishlistItemNotFound = errors.New("wishlist item not found")
+	ErrProductNotInCart     = errors.New("product is not in cart")
+)
+
+type WishlistItemPreview struct {
+	ProductID  uuid.UUID
+	Title      string
+	Price      int
+	AddedPrice int
+}
+
+func (p WishlistItemPreview) PriceDropped() bool {
+	return p.Price < p.AddedPrice
+}
+
+type WishlistPreview struct {
+	ID         uuid.UUID
+	Name       string
+	ShareToken string
+	Items      []WishlistItemPreview
+}
+
+type WishlistService struct {
+	ufw        persistence.UnitOfWork
+	cartRepo   domain.CartStorage
+	catalogAPI api.CatalogAPI
+	logger     log.Logger
+}
+
+func (s *WishlistService) GetWishlists(userID uuid.UUID) ([]domain.Wishlist, error) {
+	var result []domain.Wishlist
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		wishlists, err := p.WishlistRepository().GetByUserID(userID)
+		result = wishlists
+		return err
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"userID": userID}).Error("failed to get wishlists")
+	}
+	return result, err
+}
+
+func (s *WishlistService) GetWishlist(userID, wishlistID uuid.UUID) (*WishlistPreview, error) {
+	var wishlist *domain.Wishlist
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		var err error
+		wishlist, err = s.getUserWishlist(p, userID, wishlistID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getPreview(wishlist)
+}
+
+func (s *WishlistService) GetSharedWishlist(shareToken string) (*WishlistPreview, error) {
+	var wishlist *domain.Wishlist
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		var err error
+		wishlist, err = p.WishlistRepository().GetByShareToken(shareToken)
+		if errors.Is(err, domain.ErrWishlistNotFound) {
+			return ErrWishlistNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := s.getPreview(wishlist)
+	if err != nil {
+		return nil, err
+	}
+	preview.ShareToken = ""
+	return preview, nil
+}
+
+func (s *WishlistService) CreateWishlist(userID uuid.UUID, name string) (uuid.UUID, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return uuid.Nil, ErrInvalidWishlistName
+	}
+
+	var wishlistID uuid.UUID
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		wishlistID = p.WishlistRepository().NextID()
+		return p.WishlistRepository().Store(&domain.Wishlist{
+			ID:        wishlistID,
+			UserID:    userID,
+			Name:      name,
+			CreatedAt: time.Now(),
+		})
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"userID": userID}).Error("failed to create wishlist")
+	}
+	return wishlistID, err
+}
+
+func (s *WishlistService) DeleteWishlist(userID, wishlistID uuid.UUID) error {
+	return s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		_, err := s.getUserWishlist(p, userID, wishlistID)
+		if err != nil {
+			return err
+		}
+		return p.WishlistRepository().Delete(wishlistID)
+	})
+}
+
+func (s *WishlistService) ShareWishlist(userID, wishlistID uuid.UUID) (string, error) {
+	var shareToken string
+	err := s.updateUserWishlist(userID, wishlistID, func(wishlist *domain.Wishlist) error {
+		err := wishlist.Share()
+		shareToken = wishlist.ShareToken
+		return err
+	})
+	return shareToken, err
+}
+
+func (s *WishlistService) UnshareWishlist(userID, wishlistID uuid.UUID) error {
+	return s.updateUserWishlist(userID, wishlistID, func(wishlist *domain.Wishlist) error {
+		wishlist.ShareToken = ""
+		return nil
+	})
+}
+
+func (s *WishlistService) AddProduct(userID, wishlistID, productID uuid.UUID) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	return s.updateUserWishlist(userID, wishlistID, func(wishlist *domain.Wishlist) error {
+		wishlist.AddItem(domain.WishlistItem{
+			ProductID:  productID,
+			AddedPrice: product.Price,
+			AddedAt:    time.Now(),
+		})
+		return nil
+	})
+}
+
+func (s *WishlistService) RemoveProduct(userID, wishlistID, productID uuid.UUID) error {
+	return s.updateUserWishlist(userID, wishlistID, func(wishlist *domain.Wishlist) error {
+		err := wishlist.RemoveItem(productID)
+		if errors.Is(err, domain.ErrWishlistItemNotFound) {
+			return ErrWishlistItemNotFound
+		}
+		return err
+	})
+}
+
+func (s *WishlistService) MoveToCart(userID, wishlistID, productID, variantID uuid.UUID) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+	lineProduct, err := getCartLineProduct(product, variantID)
+	if err != nil {
+		return err
+	}
+
+	return s.updateUserWishlist(userID, wishlistID, func(wishlist *domain.Wishlist) error {
+		err := wishlist.RemoveItem(productID)
+		if errors.Is(err, domain.ErrWishlistItemNotFound) {
+			return ErrWishlistItemNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.cartRepo.Update(domain.UserCartOwner(userID), func(cart *domain.Cart) error {
+			line := domain.CartLine{ProductID: productID, VariantID: variantID}
+			quantity := 1
+			if cartProduct, ok := cart.GetProduct(line); ok {
+				quantity += cartProduct.Quantity
+			}
+			cart.SetProduct(domain.ProductQuantity{
+				ID:         productID,
+				VariantID:  variantID,
+				Quantity:   quantity,
+				AddedPrice: lineProduct.ItemPrice(),
+			})
+			return nil
+		})
+	})
+}
+
+func (s *WishlistService) MoveFromCart(userID, wishlistID, productID, variantID uuid.UUID) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	err = s.cartRepo.Update(domain.UserCartOwner(userID), func(cart *domain.Cart) error {
+		line := domain.CartLine{ProductID: productID, VariantID: variantID}
+		if _, ok := cart.GetProduct(line); !ok {
+			return ErrProductNotInCart
+		}
+		cart.RemoveProduct(line)
+
+		return s.updateUserWishlist(userID, wishlistID, func(wishlist *domain.Wishlist) error {
+			wishlist.AddItem(domain.WishlistItem{
+				ProductID:  productID,
+				AddedPrice: product.Price,
+				AddedAt:    time.Now(),
+			})
+			return nil
+		})
+	})
+	if err != nil && !errors.Is(err, ErrProductNotInCart) && !errors.Is(err, ErrWishlistNotFound) {
+		s.logger.WithError(err).With(log.Fields{"userID": userID, "wishlistID": wishlistID, "productID": productID}).Error("failed to move product to wishlist")
+	}
+	return err
+}
+
+func (s *WishlistService) NotifyPriceChanged(productID uuid.UUID, oldPrice, newPrice int) error {
+	if newPrice >= oldPrice {
+		return nil
+	}
+
+	return s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		userIDs, err := p.WishlistRepository().GetUserIDsByProductID(productID)
+		if err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			err = p.WishlistEventAPI().NotifyPriceDropped(userID, productID, oldPrice, newPrice)
+			if err != nil {
+				return fmt.Errorf("failed to notify price dropped: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *WishlistService) NotifyBackInStock(productID uuid.UUID) error {
+	return s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		userIDs, err := p.WishlistRepository().GetUserIDsByProductID(productID)
+		if err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			err = p.WishlistEventAPI().NotifyBackInStock(userID, productID)
+			if err != nil {
+				return fmt.Errorf("failed to notify back in stock: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *WishlistService) updateUserWishlist(userID, wishlistID uuid.UUID, f func(wishlist *domain.Wishlist) error) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		wishlist, err := s.getUserWishlist(p, userID, wishlistID)
+		if err != nil {
+			return err
+		}
+
+		err = f(wishlist)
+		if err != nil {
+			return err
+		}
+		return p.WishlistRepository().Store(wishlist)
+	})
+	if err != nil && !errors.Is(err, ErrWishlistNotFound) && !errors.Is(err, ErrWishlistItemNotFound) {
+		s.logger.WithError(err).With(log.Fields{"userID": userID, "wishlistID": wishlistID}).Error("failed to update wishlist")
+	}
+	return err
+}
+
+func (s *WishlistService) getUserWishlist(p persistence.PersistentProvider, userID, wishlistID uuid.UUID) (*domain.Wishlist, error) {
+	wishlist, err := p.WishlistRepository().GetByID(wishlistID)
+	if errors.Is(err, domain.ErrWishlistNotFound) || (err == nil && wishlist.UserID != userID) {
+		return nil, ErrWishlistNotFound
+	}
+	return wishlist, err
+}
+
+func (s *WishlistService) getPreview(wishlist *domain.Wishlist) (*WishlistPreview, error) {
+	preview := &WishlistPreview{
+		ID:         wishlist.ID,
+		Name:       wishlist.Name,
+		ShareToken: wishlist.ShareToken,
+		Items:      make([]WishlistItemPreview, 0, len(wishlist.Items)),
+	}
+	if len(wishlist.Items) == 0 {
+		return preview, nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(wishlist.Items))
+	for _, item := range wishlist.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	products, err := s.catalogAPI.GetProducts(productIDs)
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"wishlistID": wishlist.ID}).Error("failed to get wishlist products")
+		return nil, err
+	}
+
+	productsMap := make(map[uuid.UUID]api.Product, len(products))
+	for _, product := range products {
+		productsMap[product.ID] = product
+	}
+	for _, item := range wishlist.Items {
+		product := productsMap[item.ProductID]
+		preview.Items = append(preview.Items, WishlistItemPreview{
+			ProductID:  item.ProductID,
+			Title:      product.Title,
+			Price:      product.Price,
+			AddedPrice: item.AddedPrice,
+		})
+	}
+	return preview, nil
+}
+
+func (s *WishlistService) getProduct(id uuid.UUID) (*api.Product, error) {
+	products, err := s.catalogAPI.GetProducts([]uuid.UUID{id})
+	if errors.Is(err, api.ErrProductsNotFound) || (err == nil && len(products) == 0) {
+		return nil, ErrInvalidProduct
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+func NewWishlistService(
+	ufw persistence.UnitOfWork,
+	cartRepo domain.CartStorage,
+	catalogAPI api.CatalogAPI,
+	logger log.Logger,
+) *WishlistService {
+	return &WishlistService{
+		ufw:        ufw,
+		cartRepo:   cartRepo,
+		catalogAPI: catalogAPI,
+		logger:     logger,
+	}
+}
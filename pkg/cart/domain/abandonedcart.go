@@ -0,0 +1,8 @@
+package domain
+
+import "time"
+
+type AbandonedCartRepository interface {
+	LockAbandoned(idleBefore, expiredBefore time.Time, limit int) ([]Cart, error)
+	MarkAbandoned(owner CartOwner, abandonedAt time.Time) error
+}
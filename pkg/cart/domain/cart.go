@@ -1,19 +1,131 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrUnknownMergeStrategy = errors.New("unknown cart merge strategy")
+	ErrCartUpdateConflict   = errors.New("cart is concurrently modified")
+)
+
+type MergeStrategy string
+
+const (
+	MergeStrategySum MergeStrategy = "sum"
+	MergeStrategyMax MergeStrategy = "max"
+)
+
+type CartOwner struct {
+	ID    uuid.UUID
+	Guest bool
+}
+
+func UserCartOwner(userID uuid.UUID) CartOwner {
+	return CartOwner{ID: userID}
+}
+
+func GuestCartOwner(guestID uuid.UUID) CartOwner {
+	return CartOwner{ID: guestID, Guest: true}
+}
+
+type CartLine struct {
+	ProductID uuid.UUID
+	VariantID uuid.UUID
+}
 
 type ProductQuantity struct {
-	ID       uuid.UUID
-	Quantity int
+	ID         uuid.UUID
+	VariantID  uuid.UUID
+	Quantity   int
+	AddedPrice int
+}
+
+func (p ProductQuantity) Line() CartLine {
+	return CartLine{ProductID: p.ID, VariantID: p.VariantID}
 }
 
 type Cart struct {
-	UserID   uuid.UUID
-	Products []ProductQuantity
+	Owner     CartOwner
+	Products  []ProductQuantity
+	UpdatedAt time.Time
+}
+
+func (c *Cart) SetProduct(product ProductQuantity) {
+	for i := range c.Products {
+		if c.Products[i].Line() == product.Line() {
+			c.Products[i] = product
+			return
+		}
+	}
+	c.Products = append(c.Products, product)
+}
+
+func (c *Cart) GetProduct(line CartLine) (ProductQuantity, bool) {
+	for _, product := range c.Products {
+		if product.Line() == line {
+			return product, true
+		}
+	}
+	return ProductQuantity{}, false
+}
+
+func (c *Cart) RemoveProduct(line CartLine) {
+	for i := range c.Products {
+		if c.Products[i].Line() == line {
+			c.Products = append(c.Products[:i], c.Products[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *Cart) Merge(other *Cart, strategy MergeStrategy) error {
+	if strategy != MergeStrategySum && strategy != MergeStrategyMax {
+		return ErrUnknownMergeStrategy
+	}
+
+outer:
+	for _, otherProduct := range other.Products {
+		for i := range c.Products {
+			product := &c.Products[i]
+			if product.Line() != otherProduct.Line() {
+				continue
+			}
+			if strategy == MergeStrategySum {
+				product.Quantity += otherProduct.Quantity
+			} else if otherProduct.Quantity > product.Quantity {
+				product.Quantity = otherProduct.Quantity
+			}
+			continue outer
+		}
+		c.Products = append(c.Products, otherProduct)
+	}
+	return nil
+}
+
+type ExpirationPolicy struct {
+	UserCartTTL  time.Duration
+	GuestCartTTL time.Duration
+}
+
+func (p ExpirationPolicy) TTL(owner CartOwner) time.Duration {
+	if owner.Guest {
+		return p.GuestCartTTL
+	}
+	return p.UserCartTTL
+}
+
+func (p ExpirationPolicy) IsExpired(cart *Cart, now time.Time) bool {
+	ttl := p.TTL(cart.Owner)
+	return ttl > 0 && !cart.UpdatedAt.IsZero() && cart.UpdatedAt.Add(ttl).Before(now)
 }
 
 type CartStorage interface {
-	GetByUserID(userID uuid.UUID) (*Cart, error)
+	GetByOwner(owner CartOwner) (*Cart, error)
+	Update(owner CartOwner, f func(cart *Cart) error) error
 	Store(cart *Cart) error
-	Delete(userID uuid.UUID) error
+	Delete(owner CartOwner) error
+	Take(owner CartOwner) (*Cart, error)
 }
@@ -0,0 +1,8 @@
+package domain
+
+import "github.com/google/uuid"
+
+type CheckoutKeyStorage interface {
+	GetOrderID(userID uuid.UUID, idempotenceKey string) (uuid.UUID, error)
+	Store(userID uuid.UUID, idempotenceKey string, orderID uuid.UUID) error
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrInvalidQuoteToken = errors.New("invalid quote token")
+
+type QuoteProduct struct {
+	ID              uuid.UUID
+	VariantID       uuid.UUID
+	WarehouseItemID uuid.UUID
+	Price           int
+	Weight          int
+	Quantity        int
+	Discount        int
+	CategoryIDs     []uuid.UUID
+}
+
+func (p QuoteProduct) Line() CartLine {
+	return CartLine{ProductID: p.ID, VariantID: p.VariantID}
+}
+
+type Quote struct {
+	UserID        uuid.UUID
+	AddressID     uuid.UUID
+	PickupPointID uuid.UUID
+	PromoCode     string
+	Products      []QuoteProduct
+	ShippingFee   int
+	ExpiresAt     time.Time
+}
+
+func (q *Quote) Amount() int {
+	var amount int
+	for _, product := range q.Products {
+		amount += product.Price * product.Quantity
+	}
+	return amount
+}
+
+func (q *Quote) Discount() int {
+	var discount int
+	for _, product := range q.Products {
+		discount += product.Discount
+	}
+	return discount
+}
+
+func (q *Quote) TotalAmount() int {
+	return q.Amount() - q.Discount() + q.ShippingFee
+}
+
+func (q *Quote) IsExpired(now time.Time) bool {
+	return !q.ExpiresAt.After(now)
+}
+
+func (q *Quote) MatchesCart(cart *Cart) bool {
+	if len(q.Products) != len(cart.Products) {
+		return false
+	}
+
+	quantityByLine := make(map[CartLine]int, len(cart.Products))
+	for _, product := range cart.Products {
+		quantityByLine[product.Line()] = product.Quantity
+	}
+	for _, product := range q.Products {
+		if quantity, ok := quantityByLine[product.Line()]; !ok || quantity != product.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+type QuoteTokenEncoder interface {
+	Encode(quote *Quote) (string, error)
+	Decode(token string) (*Quote, error)
+}
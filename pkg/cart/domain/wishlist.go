@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrWishlistNotFound     = errors.New("wishlist not found")
+	ErrWishlistItemNotFound = errors.New("wishlist item not found")
+)
+
+const shareTokenLength = 16
+
+type WishlistItem struct {
+	ProductID  uuid.UUID
+	AddedPrice int
+	AddedAt    time.Time
+}
+
+type Wishlist struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	ShareToken string
+	Items      []WishlistItem
+	CreatedAt  time.Time
+}
+
+func (w *Wishlist) AddItem(item WishlistItem) {
+	for _, existing := range w.Items {
+		if existing.ProductID == item.ProductID {
+			return
+		}
+	}
+	w.Items = append(w.Items, item)
+}
+
+func (w *Wishlist) RemoveItem(productID uuid.UUID) error {
+	for i := range w.Items {
+		if w.Items[i].ProductID == productID {
+			w.Items = append(w.Items[:i], w.Items[i+1:]...)
+			return nil
+		}
+	}
+	return ErrWishlistItemNotFound
+}
+
+func (w *Wishlist) Share() error {
+	if w.ShareToken != "" {
+		return nil
+	}
+
+	b := make([]byte, shareTokenLength)
+	_, err := rand.Read(b)
+	if err != nil {
+		return err
+	}
+	w.ShareToken = base64.RawURLEncoding.EncodeToString(b)
+	return nil
+}
+
+type WishlistRepository interface {
+	NextID() uuid.UUID
+	GetByID(id uuid.UUID) (*Wishlist, error)
+	GetByShareToken(token string) (*Wishlist, error)
+	GetByUserID(userID uuid.UUID) ([]Wishlist, error)
+	GetUserIDsByProductID(productID uuid.UUID) ([]uuid.UUID, error)
+	Store(wishlist *Wishlist) error
+	Delete(id uuid.UUID) error
+}
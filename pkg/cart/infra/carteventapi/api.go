@@ -0,0 +1,64 @@
+package carteventapi
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
+	"time"
+)
+
+const cartEventTopicName = "cart_event"
+
+type api struct {
+	eventDispatcher event.Dispatcher
+}
+
+func (a *api) NotifyCartAbandoned(cart *domain.Cart) error {
+	type productJSONSchema struct {
+		ProductID  uuid.UUID  `json:"product_id"`
+		VariantID  *uuid.UUID `json:"variant_id,omitempty"`
+		Quantity   int        `json:"quantity"`
+		AddedPrice int        `json:"added_price"`
+	}
+
+	products := make([]productJSONSchema, 0, len(cart.Products))
+	for _, product := range cart.Products {
+		item := productJSONSchema{
+			ProductID:  product.ID,
+			Quantity:   product.Quantity,
+			AddedPrice: product.AddedPrice,
+		}
+		if product.VariantID != uuid.Nil {
+			variantID := product.VariantID
+			item.VariantID = &variantID
+		}
+		products = append(products, item)
+	}
+
+	body, err := json.Marshal(struct {
+		UserID    uuid.UUID           `json:"user_id"`
+		Products  []productJSONSchema `json:"products"`
+		UpdatedAt time.Time           `json:"updated_at"`
+	}{cart.Owner.ID, products, cart.UpdatedAt})
+	if err != nil {
+		return errors.New("failed to encode cart event")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "cart_abandoned",
+		TopicName: cartEventTopicName,
+		Key:       cart.Owner.ID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func New(eventDispatcher event.Dispatcher) async.CartEventAPI {
+	return &api{eventDispatcher: eventDispatcher}
+}
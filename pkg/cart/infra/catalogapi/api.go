@@ -39,8 +39,19 @@ func (c *apiClient) GetProducts(productIDs []uuid.UUID) ([]api.Product, error) {
 	}
 
 	var productPrices []struct {
-		ID    uuid.UUID `json:"id"`
-		Price int       `json:"price"`
+		ID          uuid.UUID   `json:"id"`
+		Title       string      `json:"title"`
+		Price       int         `json:"price"`
+		Weight      int         `json:"weight"`
+		MaxQuantity int         `json:"max_quantity"`
+		CategoryIDs []uuid.UUID `json:"category_ids"`
+		Variants    []struct {
+			ID              uuid.UUID         `json:"id"`
+			SKU             string            `json:"sku"`
+			Options         map[string]string `json:"options"`
+			Price           int               `json:"price"`
+			WarehouseItemID uuid.UUID         `json:"warehouse_item_id"`
+		} `json:"variants"`
 	}
 	err = json.NewDecoder(resp.Body).Decode(&productPrices)
 	if err != nil {
@@ -49,9 +60,24 @@ func (c *apiClient) GetProducts(productIDs []uuid.UUID) ([]api.Product, error) {
 
 	result := make([]api.Product, 0, len(productPrices))
 	for _, item := range productPrices {
+		variants := make([]api.ProductVariant, 0, len(item.Variants))
+		for _, variant := range item.Variants {
+			variants = append(variants, api.ProductVariant{
+				ID:              variant.ID,
+				SKU:             variant.SKU,
+				Options:         variant.Options,
+				Price:           variant.Price,
+				WarehouseItemID: variant.WarehouseItemID,
+			})
+		}
 		result = append(result, api.Product{
-			ID:    item.ID,
-			Price: item.Price,
+			ID:          item.ID,
+			Title:       item.Title,
+			Price:       item.Price,
+			Weight:      item.Weight,
+			MaxQuantity: item.MaxQuantity,
+			CategoryIDs: item.CategoryIDs,
+			Variants:    variants,
 		})
 	}
 	return result, nil
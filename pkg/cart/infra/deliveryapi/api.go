@@ -0,0 +1,112 @@
+package deliveryapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/api"
+	"net/http"
+)
+
+type apiClient struct {
+	client     *http.Client
+	serviceURL string
+}
+
+func (c *apiClient) ValidateAddress(userID, addressID uuid.UUID) error {
+	_, err := c.getAddressID(fmt.Sprintf("%s/delivery/users/%s/addresses/%s", c.serviceURL, userID, addressID))
+	return err
+}
+
+func (c *apiClient) GetDefaultAddressID(userID uuid.UUID) (uuid.UUID, error) {
+	return c.getAddressID(fmt.Sprintf("%s/delivery/users/%s/addresses/default", c.serviceURL, userID))
+}
+
+type quoteShippingSchema struct {
+	UserID        uuid.UUID `json:"user_id"`
+	AddressID     uuid.UUID `json:"address_id"`
+	PickupPointID uuid.UUID `json:"pickup_point_id"`
+	Weight        int       `json:"weight"`
+	OrderAmount   int       `json:"order_amount"`
+}
+
+func (c *apiClient) QuoteShipping(userID, addressID uuid.UUID, weight, orderAmount int) (int, error) {
+	return c.quoteShipping(&quoteShippingSchema{
+		UserID:      userID,
+		AddressID:   addressID,
+		Weight:      weight,
+		OrderAmount: orderAmount,
+	}, api.ErrAddressNotFound)
+}
+
+func (c *apiClient) QuotePickupShipping(pickupPointID uuid.UUID, weight, orderAmount int) (int, error) {
+	return c.quoteShipping(&quoteShippingSchema{
+		PickupPointID: pickupPointID,
+		Weight:        weight,
+		OrderAmount:   orderAmount,
+	}, api.ErrPickupPointNotFound)
+}
+
+func (c *apiClient) quoteShipping(body *quoteShippingSchema, notFoundErr error) (int, error) {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode quote for request: %w", err)
+	}
+
+	resp, err := c.client.Post(fmt.Sprintf("%s/delivery/shipping/quote", c.serviceURL), "application/json", bytes.NewBuffer(bodyJSON))
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return 0, notFoundErr
+	case http.StatusUnprocessableEntity:
+		return 0, api.ErrShippingUnavailable
+	default:
+		return 0, fmt.Errorf("failed to quoteShipping, httpCode: %v", resp.StatusCode)
+	}
+
+	var quote struct {
+		Price int `json:"price"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&quote)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode quoteShipping response: %w", err)
+	}
+	return quote.Price, nil
+}
+
+func (c *apiClient) getAddressID(url string) (uuid.UUID, error) {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to execute http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return uuid.Nil, api.ErrAddressNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return uuid.Nil, fmt.Errorf("failed to getAddress, httpCode: %v", resp.StatusCode)
+	}
+
+	var address struct {
+		ID uuid.UUID `json:"id"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&address)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to decode getAddress response: %w", err)
+	}
+	return address.ID, nil
+}
+
+func New(serviceURL string) api.DeliveryAPI {
+	return &apiClient{
+		client:     &http.Client{},
+		serviceURL: serviceURL,
+	}
+}
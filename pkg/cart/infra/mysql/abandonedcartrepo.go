@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"time"
+)
+
+type abandonedCartRepo struct {
+	client mysql.Client
+}
+
+func (r *abandonedCartRepo) LockAbandoned(idleBefore, expiredBefore time.Time, limit int) ([]domain.Cart, error) {
+	const query = `
+		SELECT owner_id, updated_at
+		FROM cart
+		WHERE is_guest = 0 AND abandoned_at IS NULL AND updated_at < ? AND updated_at >= ?
+		ORDER BY updated_at
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var cartsSqlx []struct {
+		OwnerID   uuid.UUID `db:"owner_id"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+	err := r.client.Select(&cartsSqlx, query, idleBefore.UTC(), expiredBefore.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Cart, 0, len(cartsSqlx))
+	for _, cartSqlx := range cartsSqlx {
+		binaryOwnerID, err := cartSqlx.OwnerID.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		var itemsSqlx []sqlxCartItem
+		err = r.client.Select(&itemsSqlx, `SELECT product_id, variant_id, quantity, added_price FROM cart_item WHERE owner_id = ? AND is_guest = 0`, binaryOwnerID)
+		if err != nil {
+			return nil, err
+		}
+
+		cart := domain.Cart{
+			Owner:     domain.UserCartOwner(cartSqlx.OwnerID),
+			Products:  make([]domain.ProductQuantity, 0, len(itemsSqlx)),
+			UpdatedAt: cartSqlx.UpdatedAt,
+		}
+		for _, itemSqlx := range itemsSqlx {
+			cart.Products = append(cart.Products, domain.ProductQuantity{
+				ID:         itemSqlx.ProductID,
+				VariantID:  itemSqlx.VariantID,
+				Quantity:   itemSqlx.Quantity,
+				AddedPrice: itemSqlx.AddedPrice,
+			})
+		}
+		result = append(result, cart)
+	}
+	return result, nil
+}
+
+func (r *abandonedCartRepo) MarkAbandoned(owner domain.CartOwner, abandonedAt time.Time) error {
+	binaryOwnerID, err := owner.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`UPDATE cart SET abandoned_at = ? WHERE owner_id = ? AND is_guest = ?`, abandonedAt.UTC(), binaryOwnerID, owner.Guest)
+	return err
+}
+
+func NewAbandonedCartRepository(client mysql.Client) domain.AbandonedCartRepository {
+	return &abandonedCartRepo{client: client}
+}
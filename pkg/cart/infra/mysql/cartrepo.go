@@ -0,0 +1,254 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"strings"
+	"time"
+)
+
+type cartRepo struct {
+	client     mysql.TransactionalClient
+	expiration domain.ExpirationPolicy
+}
+
+type sqlxCartItem struct {
+	ProductID  uuid.UUID `db:"product_id"`
+	VariantID  uuid.UUID `db:"variant_id"`
+	Quantity   int       `db:"quantity"`
+	AddedPrice int       `db:"added_price"`
+}
+
+func (r *cartRepo) GetByOwner(owner domain.CartOwner) (*domain.Cart, error) {
+	binaryOwnerID, err := owner.ID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := r.getCart(r.client, owner, binaryOwnerID, false)
+	if err != nil {
+		return nil, err
+	}
+	if !r.expiration.IsExpired(cart, time.Now()) {
+		return cart, nil
+	}
+
+	err = r.Delete(owner)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Cart{Owner: owner}, nil
+}
+
+func (r *cartRepo) Update(owner domain.CartOwner, f func(cart *domain.Cart) error) error {
+	binaryOwnerID, err := owner.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.client.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start tx: %w", err)
+	}
+
+	err = func() error {
+		_, err := tx.Exec(`INSERT IGNORE INTO cart (owner_id, is_guest, updated_at) VALUES (?, ?, ?)`, binaryOwnerID, owner.Guest, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		cart, err := r.getCart(tx, owner, binaryOwnerID, true)
+		if err != nil {
+			return err
+		}
+
+		err = f(cart)
+		if err != nil {
+			return err
+		}
+
+		cart.UpdatedAt = time.Now()
+		return r.storeCart(tx, binaryOwnerID, cart)
+	}()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepo) Store(cart *domain.Cart) error {
+	binaryOwnerID, err := cart.Owner.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.client.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start tx: %w", err)
+	}
+
+	err = r.storeCart(tx, binaryOwnerID, cart)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepo) Delete(owner domain.CartOwner) error {
+	binaryOwnerID, err := owner.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`DELETE FROM cart WHERE owner_id = ? AND is_guest = ?`, binaryOwnerID, owner.Guest)
+	return err
+}
+
+func (r *cartRepo) Take(owner domain.CartOwner) (*domain.Cart, error) {
+	binaryOwnerID, err := owner.ID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.client.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start tx: %w", err)
+	}
+
+	cart, err := func() (*domain.Cart, error) {
+		cart, err := r.getCart(tx, owner, binaryOwnerID, true)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(`DELETE FROM cart WHERE owner_id = ? AND is_guest = ?`, binaryOwnerID, owner.Guest)
+		return cart, err
+	}()
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return cart, nil
+}
+
+func (r *cartRepo) getCart(client mysql.Client, owner domain.CartOwner, binaryOwnerID []byte, lock bool) (*domain.Cart, error) {
+	cartQuery := `SELECT updated_at FROM cart WHERE owner_id = ? AND is_guest = ?`
+	if lock {
+		cartQuery += ` FOR UPDATE`
+	}
+
+	var updatedAt time.Time
+	err := client.Get(&updatedAt, cartQuery, binaryOwnerID, owner.Guest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &domain.Cart{Owner: owner}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cart := &domain.Cart{Owner: owner, UpdatedAt: updatedAt}
+	if r.expiration.IsExpired(cart, time.Now()) {
+		return cart, nil
+	}
+
+	var itemsSqlx []sqlxCartItem
+	err = client.Select(&itemsSqlx, `SELECT product_id, variant_id, quantity, added_price FROM cart_item WHERE owner_id = ? AND is_guest = ?`, binaryOwnerID, owner.Guest)
+	if err != nil {
+		return nil, err
+	}
+
+	cart.Products = make([]domain.ProductQuantity, 0, len(itemsSqlx))
+	for _, itemSqlx := range itemsSqlx {
+		cart.Products = append(cart.Products, domain.ProductQuantity{
+			ID:         itemSqlx.ProductID,
+			VariantID:  itemSqlx.VariantID,
+			Quantity:   itemSqlx.Quantity,
+			AddedPrice: itemSqlx.AddedPrice,
+		})
+	}
+	return cart, nil
+}
+
+func (r *cartRepo) storeCart(client mysql.Client, binaryOwnerID []byte, cart *domain.Cart) error {
+	const cartQuery = `
+		INSERT INTO cart (owner_id, is_guest, updated_at)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			updated_at = VALUES(updated_at), abandoned_at = NULL
+	`
+
+	_, err := client.Exec(cartQuery, binaryOwnerID, cart.Owner.Guest, cart.UpdatedAt.UTC())
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Exec(`DELETE FROM cart_item WHERE owner_id = ? AND is_guest = ?`, binaryOwnerID, cart.Owner.Guest)
+	if err != nil {
+		return err
+	}
+
+	if len(cart.Products) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO cart_item (owner_id, is_guest, product_id, variant_id, quantity, added_price)
+		VALUES %s%s
+	`, "(?, ?, ?, ?, ?, ?)", strings.Repeat(", (?, ?, ?, ?, ?, ?)", len(cart.Products)-1))
+	args := make([]any, 0, len(cart.Products)*6) // arguments count
+	for _, product := range cart.Products {
+		binaryProductID, err := product.ID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		binaryVariantID, err := product.VariantID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		args = append(args, binaryOwnerID, cart.Owner.Guest, binaryProductID, binaryVariantID, product.Quantity, product.AddedPrice)
+	}
+
+	_, err = client.Exec(insertQuery, args...)
+	return err
+}
+
+func DeleteExpiredCarts(client mysql.Client, expiration domain.ExpirationPolicy) error {
+	now := time.Now().UTC()
+	for _, guest := range []bool{false, true} {
+		ttl := expiration.TTL(domain.CartOwner{Guest: guest})
+		if ttl <= 0 {
+			continue
+		}
+
+		_, err := client.Exec(`DELETE FROM cart WHERE is_guest = ? AND updated_at < ?`, guest, now.Add(-ttl))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewCartStorage(client mysql.TransactionalClient, expiration domain.ExpirationPolicy) domain.CartStorage {
+	return &cartRepo{client: client, expiration: expiration}
+}
@@ -0,0 +1,64 @@
+package mysql
+
+import (
+	"fmt"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/carteventapi"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/infra/wishlisteventapi"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+)
+
+type persistentProvider struct {
+	db mysql.Client
+}
+
+func (p *persistentProvider) WishlistRepository() domain.WishlistRepository {
+	return NewWishlistRepository(p.db)
+}
+
+func (p *persistentProvider) WishlistEventAPI() async.WishlistEventAPI {
+	return wishlisteventapi.New(p.eventDispatcher())
+}
+
+func (p *persistentProvider) AbandonedCartRepository() domain.AbandonedCartRepository {
+	return NewAbandonedCartRepository(p.db)
+}
+
+func (p *persistentProvider) CartEventAPI() async.CartEventAPI {
+	return carteventapi.New(p.eventDispatcher())
+}
+
+func (p *persistentProvider) eventDispatcher() event.Dispatcher {
+	return event.NewDispatcher(mysql.NewMessageStore(p.db))
+}
+
+type unitOfWork struct {
+	client mysql.TransactionalClient
+}
+
+func (u *unitOfWork) Execute(f func(p persistence.PersistentProvider) error) error {
+	tx, err := u.client.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start tx: %w", err)
+	}
+
+	pp := &persistentProvider{tx}
+	err = f(pp)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return nil
+}
+
+func NewUnitOfWork(client mysql.TransactionalClient) persistence.UnitOfWork {
+	return &unitOfWork{client: client}
+}
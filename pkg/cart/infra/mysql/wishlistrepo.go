@@ -0,0 +1,199 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"strings"
+	"time"
+)
+
+type wishlistRepo struct {
+	client mysql.Client
+}
+
+func (r *wishlistRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *wishlistRepo) GetByID(id uuid.UUID) (*domain.Wishlist, error) {
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return r.getWishlist(`SELECT id, user_id, name, share_token, created_at FROM wishlist WHERE id = ?`, binaryID)
+}
+
+func (r *wishlistRepo) GetByShareToken(token string) (*domain.Wishlist, error) {
+	return r.getWishlist(`SELECT id, user_id, name, share_token, created_at FROM wishlist WHERE share_token = ?`, token)
+}
+
+func (r *wishlistRepo) GetByUserID(userID uuid.UUID) ([]domain.Wishlist, error) {
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var wishlistsSqlx []sqlxWishlist
+	err = r.client.Select(&wishlistsSqlx, `SELECT id, user_id, name, share_token, created_at FROM wishlist WHERE user_id = ? ORDER BY created_at`, binaryUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Wishlist, 0, len(wishlistsSqlx))
+	for _, wishlistSqlx := range wishlistsSqlx {
+		wishlist, err := r.toDomain(&wishlistSqlx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *wishlist)
+	}
+	return result, nil
+}
+
+func (r *wishlistRepo) GetUserIDsByProductID(productID uuid.UUID) ([]uuid.UUID, error) {
+	const query = `
+		SELECT DISTINCT w.user_id
+		FROM wishlist_item wi
+			INNER JOIN wishlist w ON w.id = wi.wishlist_id
+		WHERE wi.product_id = ?
+	`
+
+	binaryProductID, err := productID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []uuid.UUID
+	err = r.client.Select(&userIDs, query, binaryProductID)
+	return userIDs, err
+}
+
+func (r *wishlistRepo) Store(wishlist *domain.Wishlist) error {
+	const query = `
+		INSERT INTO wishlist (id, user_id, name, share_token, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			name = VALUES(name), share_token = VALUES(share_token)
+	`
+
+	binaryID, err := wishlist.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	binaryUserID, err := wishlist.UserID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		query,
+		binaryID,
+		binaryUserID,
+		wishlist.Name,
+		sql.NullString{String: wishlist.ShareToken, Valid: wishlist.ShareToken != ""},
+		wishlist.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`DELETE FROM wishlist_item WHERE wishlist_id = ?`, binaryID)
+	if err != nil {
+		return err
+	}
+
+	if len(wishlist.Items) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO wishlist_item (wishlist_id, product_id, added_price, added_at)
+		VALUES %s%s
+	`, "(?, ?, ?, ?)", strings.Repeat(", (?, ?, ?, ?)", len(wishlist.Items)-1))
+	args := make([]any, 0, len(wishlist.Items)*4) // arguments count
+	for _, item := range wishlist.Items {
+		binaryProductID, err := item.ProductID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		args = append(args, binaryID, binaryProductID, item.AddedPrice, item.AddedAt.UTC())
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func (r *wishlistRepo) Delete(id uuid.UUID) error {
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`DELETE FROM wishlist WHERE id = ?`, binaryID)
+	return err
+}
+
+func (r *wishlistRepo) getWishlist(query string, args ...any) (*domain.Wishlist, error) {
+	var wishlistSqlx sqlxWishlist
+	err := r.client.Get(&wishlistSqlx, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrWishlistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomain(&wishlistSqlx)
+}
+
+func (r *wishlistRepo) toDomain(wishlistSqlx *sqlxWishlist) (*domain.Wishlist, error) {
+	binaryID, err := wishlistSqlx.ID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var itemsSqlx []sqlxWishlistItem
+	err = r.client.Select(&itemsSqlx, `SELECT product_id, added_price, added_at FROM wishlist_item WHERE wishlist_id = ? ORDER BY added_at`, binaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.WishlistItem, 0, len(itemsSqlx))
+	for _, itemSqlx := range itemsSqlx {
+		items = append(items, domain.WishlistItem{
+			ProductID:  itemSqlx.ProductID,
+			AddedPrice: itemSqlx.AddedPrice,
+			AddedAt:    itemSqlx.AddedAt,
+		})
+	}
+
+	return &domain.Wishlist{
+		ID:         wishlistSqlx.ID,
+		UserID:     wishlistSqlx.UserID,
+		Name:       wishlistSqlx.Name,
+		ShareToken: wishlistSqlx.ShareToken.String,
+		Items:      items,
+		CreatedAt:  wishlistSqlx.CreatedAt,
+	}, nil
+}
+
+func NewWishlistRepository(client mysql.Client) domain.WishlistRepository {
+	return &wishlistRepo{client: client}
+}
+
+type sqlxWishlist struct {
+	ID         uuid.UUID      `db:"id"`
+	UserID     uuid.UUID      `db:"user_id"`
+	Name       string         `db:"name"`
+	ShareToken sql.NullString `db:"share_token"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+type sqlxWishlistItem struct {
+	ProductID  uuid.UUID `db:"product_id"`
+	AddedPrice int       `db:"added_price"`
+	AddedAt    time.Time `db:"added_at"`
+}
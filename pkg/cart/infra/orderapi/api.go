@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/api"
 	"net/http"
+	"time"
 )
 
 type apiClient struct {
@@ -15,30 +16,37 @@ type apiClient struct {
 }
 
 type createOrderItemSchema struct {
-	ID        uuid.UUID `json:"id"`
-	ItemPrice int       `json:"item_price"`
-	Quantity  int       `json:"quantity"`
+	ID              uuid.UUID   `json:"id"`
+	VariantID       *uuid.UUID  `json:"variant_id,omitempty"`
+	WarehouseItemID *uuid.UUID  `json:"warehouse_item_id,omitempty"`
+	ItemPrice       int         `json:"item_price"`
+	Quantity        int         `json:"quantity"`
+	CategoryIDs     []uuid.UUID `json:"category_ids"`
 }
 
 type createOrderDataSchema struct {
-	UserID    uuid.UUID               `json:"user_id"`
-	AddressID uuid.UUID               `json:"address_id"`
-	Items     []createOrderItemSchema `json:"items"`
+	UserID           uuid.UUID               `json:"user_id"`
+	UserRegisteredAt *time.Time              `json:"user_registered_at,omitempty"`
+	AddressID        uuid.UUID               `json:"address_id"`
+	PickupPointID    uuid.UUID               `json:"pickup_point_id"`
+	DeliverySlotID   uuid.UUID               `json:"delivery_slot_id"`
+	ShippingFee      int                     `json:"shipping_fee"`
+	Items            []createOrderItemSchema `json:"items"`
+	PromoCode        string                  `json:"promo_code,omitempty"`
 }
 
 func (c *apiClient) CreateOrder(data *api.CreateOrderData) (uuid.UUID, error) {
-	itemData := make([]createOrderItemSchema, 0, len(data.Products))
-	for _, item := range data.Products {
-		itemData = append(itemData, createOrderItemSchema{
-			ID:        item.ID,
-			ItemPrice: item.ProductPrice,
-			Quantity:  item.Quantity,
-		})
-	}
 	orderData := createOrderDataSchema{
-		UserID:    data.UserID,
-		AddressID: data.AddressID,
-		Items:     itemData,
+		UserID:         data.UserID,
+		AddressID:      data.AddressID,
+		PickupPointID:  data.PickupPointID,
+		DeliverySlotID: data.DeliverySlotID,
+		ShippingFee:    data.ShippingFee,
+		Items:          getCreateOrderItems(data.Products),
+		PromoCode:      data.PromoCode,
+	}
+	if !data.UserRegisteredAt.IsZero() {
+		orderData.UserRegisteredAt = &data.UserRegisteredAt
 	}
 
 	orderJSON, err := json.Marshal(orderData)
@@ -58,7 +66,10 @@ func (c *apiClient) CreateOrder(data *api.CreateOrderData) (uuid.UUID, error) {
 		return uuid.UUID{}, fmt.Errorf("failed to execute http request: %w", err)
 	}
 	if resp.StatusCode == http.StatusConflict {
-		return uuid.UUID{}, nil
+		return uuid.UUID{}, api.ErrOrderAlreadyCreated
+	}
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return uuid.UUID{}, decodePromoCodeRejectedError(resp)
 	}
 	if resp.StatusCode != http.StatusOK {
 		return uuid.UUID{}, fmt.Errorf("failed to createOrder, httpCode: %v", resp.StatusCode)
@@ -73,6 +84,98 @@ func (c *apiClient) CreateOrder(data *api.CreateOrderData) (uuid.UUID, error) {
 	return orderID, nil
 }
 
+func (c *apiClient) CalculateDiscounts(
+	userID uuid.UUID,
+	promoCode string,
+	products []api.CreateOrderProductData,
+) ([]api.ProductDiscount, error) {
+	requestJSON, err := json.Marshal(struct {
+		UserID    uuid.UUID               `json:"user_id"`
+		PromoCode string                  `json:"promo_code"`
+		Items     []createOrderItemSchema `json:"items"`
+	}{
+		userID,
+		promoCode,
+		getCreateOrderItems(products),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode discounts request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/orders/discounts", c.serviceURL)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(requestJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute http request: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return nil, decodePromoCodeRejectedError(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to calculateDiscounts, httpCode: %v", resp.StatusCode)
+	}
+
+	var discounts []struct {
+		ID        uuid.UUID  `json:"id"`
+		VariantID *uuid.UUID `json:"variant_id"`
+		Discount  int        `json:"discount"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&discounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode calculateDiscounts response: %w", err)
+	}
+
+	result := make([]api.ProductDiscount, 0, len(discounts))
+	for _, item := range discounts {
+		discount := api.ProductDiscount{
+			ID:       item.ID,
+			Discount: item.Discount,
+		}
+		if item.VariantID != nil {
+			discount.VariantID = *item.VariantID
+		}
+		result = append(result, discount)
+	}
+	return result, nil
+}
+
+func getCreateOrderItems(products []api.CreateOrderProductData) []createOrderItemSchema {
+	result := make([]createOrderItemSchema, 0, len(products))
+	for _, item := range products {
+		result = append(result, createOrderItemSchema{
+			ID:              item.ID,
+			VariantID:       nullableUUID(item.VariantID),
+			WarehouseItemID: nullableUUID(item.WarehouseItemID),
+			ItemPrice:       item.ProductPrice,
+			Quantity:        item.Quantity,
+			CategoryIDs:     item.CategoryIDs,
+		})
+	}
+	return result
+}
+
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+func decodePromoCodeRejectedError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	err := json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return fmt.Errorf("failed to decode promo code error: %w", err)
+	}
+	return &api.PromoCodeRejectedError{Reason: body.Error}
+}
+
 func New(serviceURL string) api.OrderAPI {
 	return &apiClient{
 		client:     &http.Client{},
@@ -0,0 +1,108 @@
+package quotetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"strings"
+	"time"
+)
+
+type encoder struct {
+	key []byte
+}
+
+type quoteProductJSONSchema struct {
+	ID              uuid.UUID   `json:"id"`
+	VariantID       uuid.UUID   `json:"variant_id"`
+	WarehouseItemID uuid.UUID   `json:"warehouse_item_id"`
+	Price           int         `json:"price"`
+	Weight          int         `json:"weight"`
+	Quantity        int         `json:"quantity"`
+	Discount        int         `json:"discount"`
+	CategoryIDs     []uuid.UUID `json:"category_ids,omitempty"`
+}
+
+type quoteJSONSchema struct {
+	UserID        uuid.UUID                `json:"user_id"`
+	AddressID     uuid.UUID                `json:"address_id"`
+	PickupPointID uuid.UUID                `json:"pickup_point_id"`
+	PromoCode     string                   `json:"promo_code"`
+	Products      []quoteProductJSONSchema `json:"products"`
+	ShippingFee   int                      `json:"shipping_fee"`
+	ExpiresAt     time.Time                `json:"expires_at"`
+}
+
+func (e *encoder) Encode(quote *domain.Quote) (string, error) {
+	products := make([]quoteProductJSONSchema, 0, len(quote.Products))
+	for _, product := range quote.Products {
+		products = append(products, quoteProductJSONSchema(product))
+	}
+
+	payload, err := json.Marshal(quoteJSONSchema{
+		UserID:        quote.UserID,
+		AddressID:     quote.AddressID,
+		PickupPointID: quote.PickupPointID,
+		PromoCode:     quote.PromoCode,
+		Products:      products,
+		ShippingFee:   quote.ShippingFee,
+		ExpiresAt:     quote.ExpiresAt.UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(e.sign(encodedPayload)), nil
+}
+
+func (e *encoder) Decode(token string) (*domain.Quote, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, domain.ErrInvalidQuoteToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, e.sign(encodedPayload)) {
+		return nil, domain.ErrInvalidQuoteToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, domain.ErrInvalidQuoteToken
+	}
+
+	var quoteJSON quoteJSONSchema
+	err = json.Unmarshal(payload, &quoteJSON)
+	if err != nil {
+		return nil, domain.ErrInvalidQuoteToken
+	}
+
+	products := make([]domain.QuoteProduct, 0, len(quoteJSON.Products))
+	for _, product := range quoteJSON.Products {
+		products = append(products, domain.QuoteProduct(product))
+	}
+
+	return &domain.Quote{
+		UserID:        quoteJSON.UserID,
+		AddressID:     quoteJSON.AddressID,
+		PickupPointID: quoteJSON.PickupPointID,
+		PromoCode:     quoteJSON.PromoCode,
+		Products:      products,
+		ShippingFee:   quoteJSON.ShippingFee,
+		ExpiresAt:     quoteJSON.ExpiresAt,
+	}, nil
+}
+
+func (e *encoder) sign(encodedPayload string) []byte {
+	mac := hmac.New(sha256.New, e.key)
+	mac.Write([]byte(encodedPayload))
+	return mac.Sum(nil)
+}
+
+func NewEncoder(key string) domain.QuoteTokenEncoder {
+	return &encoder{key: []byte(key)}
+}
@@ -7,74 +7,215 @@ import (
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/redis"
+	"math/rand"
+	"time"
+)
+
+const (
+	maxUpdateAttempts  = 20
+	updateRetryBackoff = 5 * time.Millisecond
 )
 
 type cartRepo struct {
-	client redis.Client
+	client     redis.Client
+	expiration domain.ExpirationPolicy
+	persistent domain.CartStorage
 }
 
 type productQuantity struct {
-	ProductID uuid.UUID `json:"product_id"`
-	Quantity  int       `json:"quantity"`
+	ProductID  uuid.UUID  `json:"product_id"`
+	VariantID  *uuid.UUID `json:"variant_id,omitempty"`
+	Quantity   int        `json:"quantity"`
+	AddedPrice int        `json:"added_price,omitempty"`
 }
 
-func (r *cartRepo) GetByUserID(userID uuid.UUID) (*domain.Cart, error) {
-	cartStr, err := r.client.Get(r.getCartKey(userID))
-	if errors.Is(err, redis.ErrKeyDoesNotExist) {
-		return &domain.Cart{
-			UserID:   userID,
-			Products: nil,
-		}, nil
+func (r *cartRepo) GetByOwner(owner domain.CartOwner) (*domain.Cart, error) {
+	cart, found, err := r.getCart(r.client.Get, owner)
+	if err != nil || found || len(cart.Products) == 0 {
+		return cart, err
 	}
+
+	err = r.client.Set(r.getCartKey(owner), r.encodeCart(cart), r.getTTL(cart))
 	if err != nil {
 		return nil, err
 	}
+	return cart, nil
+}
+
+func (r *cartRepo) Update(owner domain.CartOwner, f func(cart *domain.Cart) error) error {
+	key := r.getCartKey(owner)
+	var cart *domain.Cart
+	err := r.watch(key, func(tx redis.Tx) error {
+		var err error
+		cart, _, err = r.getCart(tx.Get, owner)
+		if err != nil {
+			return err
+		}
+
+		err = f(cart)
+		if err != nil {
+			return err
+		}
+
+		cart.UpdatedAt = time.Now()
+		tx.Set(key, r.encodeCart(cart), r.getTTL(cart))
+		return nil
+	})
+	if err != nil || r.persistent == nil {
+		return err
+	}
+
+	// only the state committed to redis is persisted
+	err = r.persistent.Store(cart)
+	if err != nil {
+		return fmt.Errorf("failed to persist cart: %w", err)
+	}
+	return nil
+}
+
+func (r *cartRepo) Store(cart *domain.Cart) error {
+	return r.Update(cart.Owner, func(storedCart *domain.Cart) error {
+		storedCart.Products = cart.Products
+		return nil
+	})
+}
+
+func (r *cartRepo) Delete(owner domain.CartOwner) error {
+	if r.persistent != nil {
+		err := r.persistent.Delete(owner)
+		if err != nil {
+			return fmt.Errorf("failed to delete persisted cart: %w", err)
+		}
+	}
+	return r.client.Del(r.getCartKey(owner))
+}
+
+func (r *cartRepo) Take(owner domain.CartOwner) (*domain.Cart, error) {
+	key := r.getCartKey(owner)
+	var cart *domain.Cart
+	err := r.watch(key, func(tx redis.Tx) error {
+		var err error
+		cart, _, err = r.getCart(tx.Get, owner)
+		if err != nil {
+			return err
+		}
+
+		// the key is replaced by an empty cart, so a concurrent take doesn't fall back to the persisted one
+		emptyCart := &domain.Cart{Owner: owner, UpdatedAt: time.Now()}
+		tx.Set(key, r.encodeCart(emptyCart), r.getTTL(emptyCart))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.persistent != nil {
+		err = r.persistent.Delete(owner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete persisted cart: %w", err)
+		}
+	}
+	return cart, nil
+}
+
+func (r *cartRepo) watch(key string, f func(tx redis.Tx) error) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		err := r.client.Watch(key, f)
+		if errors.Is(err, redis.ErrTxConflict) {
+			time.Sleep(time.Duration(rand.Int63n(int64(updateRetryBackoff) * int64(attempt+1))))
+			continue
+		}
+		return err
+	}
+	return domain.ErrCartUpdateConflict
+}
+
+func (r *cartRepo) getCart(get func(key string) (string, error), owner domain.CartOwner) (*domain.Cart, bool, error) {
+	cartStr, err := get(r.getCartKey(owner))
+	if errors.Is(err, redis.ErrKeyDoesNotExist) {
+		if r.persistent == nil {
+			return &domain.Cart{Owner: owner}, false, nil
+		}
+		cart, err := r.persistent.GetByOwner(owner)
+		return cart, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
 
 	var quantity []productQuantity
 	err = json.Unmarshal([]byte(cartStr), &quantity)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	domainQuantity := make([]domain.ProductQuantity, 0, len(quantity))
 	for _, item := range quantity {
-		domainQuantity = append(domainQuantity, domain.ProductQuantity{
-			ID:       item.ProductID,
-			Quantity: item.Quantity,
-		})
+		product := domain.ProductQuantity{
+			ID:         item.ProductID,
+			Quantity:   item.Quantity,
+			AddedPrice: item.AddedPrice,
+		}
+		if item.VariantID != nil {
+			product.VariantID = *item.VariantID
+		}
+		domainQuantity = append(domainQuantity, product)
 	}
 
 	return &domain.Cart{
-		UserID:   userID,
+		Owner:    owner,
 		Products: domainQuantity,
-	}, nil
+	}, true, nil
 }
 
-func (r *cartRepo) Store(cart *domain.Cart) error {
+func (r *cartRepo) encodeCart(cart *domain.Cart) string {
 	quantity := make([]productQuantity, 0, len(cart.Products))
 	for _, item := range cart.Products {
-		quantity = append(quantity, productQuantity{
-			ProductID: item.ID,
-			Quantity:  item.Quantity,
-		})
+		product := productQuantity{
+			ProductID:  item.ID,
+			Quantity:   item.Quantity,
+			AddedPrice: item.AddedPrice,
+		}
+		if item.VariantID != uuid.Nil {
+			variantID := item.VariantID
+			product.VariantID = &variantID
+		}
+		quantity = append(quantity, product)
 	}
 
-	result, err := json.Marshal(quantity)
-	if err != nil {
-		return err
-	}
+	result, _ := json.Marshal(quantity)
+	return string(result)
+}
 
-	return r.client.Set(r.getCartKey(cart.UserID), string(result), nil)
+func (r *cartRepo) getTTL(cart *domain.Cart) *time.Duration {
+	ttl := r.expiration.TTL(cart.Owner)
+	if ttl <= 0 {
+		return nil
+	}
+	if !cart.UpdatedAt.IsZero() {
+		ttl -= time.Since(cart.UpdatedAt)
+	}
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	return &ttl
 }
 
-func (r *cartRepo) Delete(userID uuid.UUID) error {
-	return r.client.Del(r.getCartKey(userID))
+func (r *cartRepo) getCartKey(owner domain.CartOwner) string {
+	if owner.Guest {
+		return fmt.Sprintf("shopping_cart:guest:%v:products", owner.ID)
+	}
+	return fmt.Sprintf("shopping_cart:%v:products", owner.ID)
 }
 
-func (r *cartRepo) getCartKey(userID uuid.UUID) string {
-	return fmt.Sprintf("shopping_cart:%v:products", userID)
+func NewCartStorage(client redis.Client, expiration domain.ExpirationPolicy) domain.CartStorage {
+	return &cartRepo{client: client, expiration: expiration}
 }
 
-func NewCartStorage(client redis.Client) domain.CartStorage {
-	return &cartRepo{client: client}
+func NewPersistentCartStorage(
+	client redis.Client,
+	expiration domain.ExpirationPolicy,
+	persistent domain.CartStorage,
+) domain.CartStorage {
+	return &cartRepo{client: client, expiration: expiration, persistent: persistent}
 }
@@ -0,0 +1,259 @@
+package redis
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/redis"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryClient struct {
+	mutex    sync.Mutex
+	values   map[string]string
+	versions map[string]int
+}
+
+func (c *memoryClient) Set(key, value string, _ *time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.values[key] = value
+	c.versions[key]++
+	return nil
+}
+
+func (c *memoryClient) Get(key string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	value, ok := c.values[key]
+	if !ok {
+		return "", redis.ErrKeyDoesNotExist
+	}
+	return value, nil
+}
+
+func (c *memoryClient) Del(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.values, key)
+	c.versions[key]++
+	return nil
+}
+
+func (c *memoryClient) Watch(key string, f func(tx redis.Tx) error) error {
+	c.mutex.Lock()
+	version := c.versions[key]
+	c.mutex.Unlock()
+
+	tx := &memoryTx{client: c}
+	err := f(tx)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.versions[key] != version {
+		return redis.ErrTxConflict
+	}
+	for _, command := range tx.commands {
+		command()
+	}
+	return nil
+}
+
+func (c *memoryClient) Close() {}
+
+type memoryTx struct {
+	client   *memoryClient
+	commands []func()
+}
+
+func (t *memoryTx) Get(key string) (string, error) {
+	value, err := t.client.Get(key)
+	runtime.Gosched()
+	return value, err
+}
+
+func (t *memoryTx) Set(key, value string, _ *time.Duration) {
+	t.commands = append(t.commands, func() {
+		t.client.values[key] = value
+		t.client.versions[key]++
+	})
+}
+
+func (t *memoryTx) Del(key string) {
+	t.commands = append(t.commands, func() {
+		delete(t.client.values, key)
+		t.client.versions[key]++
+	})
+}
+
+func newMemoryClient() *memoryClient {
+	return &memoryClient{
+		values:   make(map[string]string),
+		versions: make(map[string]int),
+	}
+}
+
+func TestConcurrentCartUpdatesAreNotLost(t *testing.T) {
+	const (
+		workers            = 10
+		updatesPerWorker   = 10
+		concurrentProducts = workers
+	)
+
+	repo := NewCartStorage(newMemoryClient(), domain.ExpirationPolicy{})
+	owner := domain.UserCartOwner(uuid.New())
+	counterProductID := uuid.New()
+
+	var successfulIncrements int
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			productID := uuid.New()
+			err := repo.Update(owner, func(cart *domain.Cart) error {
+				cart.SetProduct(domain.ProductQuantity{ID: productID, Quantity: 1})
+				return nil
+			})
+			if err != nil {
+				t.Errorf("failed to add product: %v", err)
+			}
+
+			for j := 0; j < updatesPerWorker; j++ {
+				err := repo.Update(owner, func(cart *domain.Cart) error {
+					quantity := 0
+					for _, product := range cart.Products {
+						if product.ID == counterProductID {
+							quantity = product.Quantity
+						}
+					}
+					cart.SetProduct(domain.ProductQuantity{ID: counterProductID, Quantity: quantity + 1})
+					return nil
+				})
+				if errors.Is(err, domain.ErrCartUpdateConflict) {
+					continue
+				}
+				if err != nil {
+					t.Errorf("failed to increment product quantity: %v", err)
+					continue
+				}
+				mutex.Lock()
+				successfulIncrements++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	cart, err := repo.GetByOwner(owner)
+	if err != nil {
+		t.Fatalf("failed to get cart: %v", err)
+	}
+
+	if len(cart.Products) != concurrentProducts+1 {
+		t.Errorf("expected %d products in cart, got %d", concurrentProducts+1, len(cart.Products))
+	}
+	if successfulIncrements == 0 {
+		t.Fatal("expected at least one successful increment")
+	}
+	for _, product := range cart.Products {
+		if product.ID == counterProductID && product.Quantity != successfulIncrements {
+			t.Errorf("expected quantity %d, got %d", successfulIncrements, product.Quantity)
+		}
+	}
+}
+
+func TestUpdateReturnsConflictWhenRetriesExhausted(t *testing.T) {
+	client := newMemoryClient()
+	repo := NewCartStorage(client, domain.ExpirationPolicy{})
+	owner := domain.UserCartOwner(uuid.New())
+
+	err := repo.Update(owner, func(cart *domain.Cart) error {
+		_ = client.Set("shopping_cart:"+owner.ID.String()+":products", "[]", nil)
+		return nil
+	})
+	if !errors.Is(err, domain.ErrCartUpdateConflict) {
+		t.Errorf("expected ErrCartUpdateConflict, got %v", err)
+	}
+}
+
+func TestConcurrentTakeReturnsCartOnce(t *testing.T) {
+	const workers = 10
+
+	repo := NewCartStorage(newMemoryClient(), domain.ExpirationPolicy{})
+	owner := domain.GuestCartOwner(uuid.New())
+	err := repo.Update(owner, func(cart *domain.Cart) error {
+		cart.SetProduct(domain.ProductQuantity{ID: uuid.New(), Quantity: 1})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to add product: %v", err)
+	}
+
+	var taken int
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cart, err := repo.Take(owner)
+			if err != nil {
+				t.Errorf("failed to take cart: %v", err)
+				return
+			}
+			if len(cart.Products) > 0 {
+				mutex.Lock()
+				taken++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if taken != 1 {
+		t.Errorf("expected cart to be taken once, got %d", taken)
+	}
+}
+
+type recordingStorage struct {
+	domain.CartStorage
+	stored []domain.Cart
+}
+
+func (s *recordingStorage) GetByOwner(owner domain.CartOwner) (*domain.Cart, error) {
+	return &domain.Cart{Owner: owner}, nil
+}
+
+func (s *recordingStorage) Store(cart *domain.Cart) error {
+	s.stored = append(s.stored, *cart)
+	return nil
+}
+
+func TestConflictedUpdateIsNotPersisted(t *testing.T) {
+	client := newMemoryClient()
+	persistent := &recordingStorage{}
+	repo := NewPersistentCartStorage(client, domain.ExpirationPolicy{}, persistent)
+	owner := domain.UserCartOwner(uuid.New())
+
+	err := repo.Update(owner, func(cart *domain.Cart) error {
+		cart.SetProduct(domain.ProductQuantity{ID: uuid.New(), Quantity: 1})
+		_ = client.Set("shopping_cart:"+owner.ID.String()+":products", "[]", nil)
+		return nil
+	})
+	if !errors.Is(err, domain.ErrCartUpdateConflict) {
+		t.Fatalf("expected ErrCartUpdateConflict, got %v", err)
+	}
+	if len(persistent.stored) != 0 {
+		t.Errorf("expected conflicted cart not to be persisted, got %d stores", len(persistent.stored))
+	}
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/redis"
+	"time"
+)
+
+const checkoutKeyTTL = 24 * time.Hour
+
+type checkoutKeyStorage struct {
+	client redis.Client
+}
+
+func (s *checkoutKeyStorage) GetOrderID(userID uuid.UUID, idempotenceKey string) (uuid.UUID, error) {
+	orderID, err := s.client.Get(s.getKey(userID, idempotenceKey))
+	if errors.Is(err, redis.ErrKeyDoesNotExist) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(orderID)
+}
+
+func (s *checkoutKeyStorage) Store(userID uuid.UUID, idempotenceKey string, orderID uuid.UUID) error {
+	ttl := checkoutKeyTTL
+	return s.client.Set(s.getKey(userID, idempotenceKey), orderID.String(), &ttl)
+}
+
+func (s *checkoutKeyStorage) getKey(userID uuid.UUID, idempotenceKey string) string {
+	return fmt.Sprintf("cart_checkout:%v:%s", userID, idempotenceKey)
+}
+
+func NewCheckoutKeyStorage(client redis.Client) domain.CheckoutKeyStorage {
+	return &checkoutKeyStorage{client: client}
+}
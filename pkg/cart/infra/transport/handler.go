@@ -6,18 +6,43 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/api"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/transport"
 	"net/http"
+	"time"
 )
 
-const healthEndpoint = "/healthz"
+const (
+	healthEndpoint = "/healthz"
+
+	guestCartCookieName     = "cart_id"
+	guestCartCookieLifetime = time.Hour * 24 * 30
+
+	sharedWishlistPath = "/web/cart/wishlists/shared/"
+)
+
+type wishlistItemJSONSchema struct {
+	ID           uuid.UUID `json:"id"`
+	Title        string    `json:"title"`
+	Price        int       `json:"price"`
+	AddedPrice   int       `json:"added_price"`
+	PriceDropped bool      `json:"price_dropped"`
+}
+
+type wishlistJSONSchema struct {
+	ID       uuid.UUID                `json:"id"`
+	Name     string                   `json:"name"`
+	ShareURL string                   `json:"share_url,omitempty"`
+	Items    []wishlistItemJSONSchema `json:"items"`
+}
 
 type route struct {
 	Name    string
 	Method  string
 	Pattern string
-	Handler func(*service.CartService, http.ResponseWriter, *http.Request)
+	Handler func(*service.CartService, *service.WishlistService, http.ResponseWriter, *http.Request)
 }
 
 func getRoutes() []route {
@@ -40,12 +65,96 @@ func getRoutes() []route {
 			"/web/cart/{productID}",
 			deleteFromCartHandler,
 		},
+		{
+			"getCartDiscount",
+			http.MethodGet,
+			"/web/cart/discount",
+			getCartDiscountHandler,
+		},
+		{
+			"getCartShipping",
+			http.MethodGet,
+			"/web/cart/shipping",
+			getCartShippingHandler,
+		},
+		{
+			"createQuote",
+			http.MethodPost,
+			"/web/cart/quote",
+			createQuoteHandler,
+		},
 		{
 			"checkout",
 			http.MethodPost,
 			"/web/cart/checkout",
 			checkoutHandler,
 		},
+		{
+			"moveToWishlist",
+			http.MethodPost,
+			"/web/cart/{productID}/move-to-wishlist",
+			moveToWishlistHandler,
+		},
+		{
+			"getWishlists",
+			http.MethodGet,
+			"/web/cart/wishlists",
+			getWishlistsHandler,
+		},
+		{
+			"createWishlist",
+			http.MethodPost,
+			"/web/cart/wishlists",
+			createWishlistHandler,
+		},
+		{
+			"getSharedWishlist",
+			http.MethodGet,
+			sharedWishlistPath + "{shareToken}",
+			getSharedWishlistHandler,
+		},
+		{
+			"getWishlist",
+			http.MethodGet,
+			"/web/cart/wishlists/{wishlistID}",
+			getWishlistHandler,
+		},
+		{
+			"deleteWishlist",
+			http.MethodDelete,
+			"/web/cart/wishlists/{wishlistID}",
+			deleteWishlistHandler,
+		},
+		{
+			"shareWishlist",
+			http.MethodPost,
+			"/web/cart/wishlists/{wishlistID}/share",
+			shareWishlistHandler,
+		},
+		{
+			"unshareWishlist",
+			http.MethodDelete,
+			"/web/cart/wishlists/{wishlistID}/share",
+			unshareWishlistHandler,
+		},
+		{
+			"addToWishlist",
+			http.MethodPut,
+			"/web/cart/wishlists/{wishlistID}",
+			addToWishlistHandler,
+		},
+		{
+			"deleteFromWishlist",
+			http.MethodDelete,
+			"/web/cart/wishlists/{wishlistID}/{productID}",
+			deleteFromWishlistHandler,
+		},
+		{
+			"moveToCart",
+			http.MethodPost,
+			"/web/cart/wishlists/{wishlistID}/{productID}/move-to-cart",
+			moveToCartHandler,
+		},
 		{
 			"health",
 			http.MethodGet,
@@ -55,49 +164,109 @@ func getRoutes() []route {
 	}
 }
 
-func getCartHandler(srv *service.CartService, w http.ResponseWriter, r *http.Request) {
-	authUserID, err := parseAuthUserID(r)
+func getCartHandler(srv *service.CartService, _ *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	owner, err := resolveCartOwner(srv, w, r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	cart, err := srv.GetCart(authUserID)
+	preview, err := srv.GetCartPreview(owner)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	type productQuantity struct {
-		ID       uuid.UUID `json:"id"`
-		Quantity int       `json:"quantity"`
+	type itemJSONSchema struct {
+		ID                uuid.UUID         `json:"id"`
+		VariantID         *uuid.UUID        `json:"variant_id,omitempty"`
+		SKU               string            `json:"sku,omitempty"`
+		Options           map[string]string `json:"options,omitempty"`
+		Title             string            `json:"title"`
+		Price             int               `json:"price"`
+		Quantity          int               `json:"quantity"`
+		Total             int               `json:"total"`
+		Available         bool              `json:"available"`
+		AvailableQuantity int               `json:"available_quantity"`
+		MaxQuantity       int               `json:"max_quantity,omitempty"`
+		PriceChanged      bool              `json:"price_changed"`
+	}
+	type warningJSONSchema struct {
+		Code      string     `json:"code"`
+		ProductID uuid.UUID  `json:"product_id"`
+		VariantID *uuid.UUID `json:"variant_id,omitempty"`
+		OldPrice  int        `json:"old_price,omitempty"`
+		NewPrice  int        `json:"new_price,omitempty"`
 	}
 
-	result := make([]productQuantity, 0, len(cart.Products))
-	for _, item := range cart.Products {
-		result = append(result, productQuantity{
-			ID:       item.ID,
-			Quantity: item.Quantity,
+	items := make([]itemJSONSchema, 0, len(preview.Items))
+	warnings := make([]warningJSONSchema, 0)
+	for _, item := range preview.Items {
+		items = append(items, itemJSONSchema{
+			ID:                item.ProductID,
+			VariantID:         nullableUUID(item.VariantID),
+			SKU:               item.SKU,
+			Options:           item.Options,
+			Title:             item.Title,
+			Price:             item.ItemPrice,
+			Quantity:          item.Quantity,
+			Total:             item.LineTotal,
+			Available:         item.Available(),
+			AvailableQuantity: item.AvailableQuantity,
+			MaxQuantity:       item.MaxQuantity,
+			PriceChanged:      item.PriceChanged(),
 		})
+		if !item.Available() {
+			warnings = append(warnings, warningJSONSchema{
+				Code:      "unavailable",
+				ProductID: item.ProductID,
+				VariantID: nullableUUID(item.VariantID),
+			})
+		}
+		if item.QuantityLimitExceeded() {
+			warnings = append(warnings, warningJSONSchema{
+				Code:      "quantity_limit_exceeded",
+				ProductID: item.ProductID,
+				VariantID: nullableUUID(item.VariantID),
+			})
+		}
+		if item.PriceChanged() {
+			warnings = append(warnings, warningJSONSchema{
+				Code:      "price_changed",
+				ProductID: item.ProductID,
+				VariantID: nullableUUID(item.VariantID),
+				OldPrice:  item.AddedPrice,
+				NewPrice:  item.ItemPrice,
+			})
+		}
 	}
 
-	err = json.NewEncoder(w).Encode(result)
+	err = json.NewEncoder(w).Encode(struct {
+		Products    []itemJSONSchema    `json:"products"`
+		TotalAmount int                 `json:"total_amount"`
+		Warnings    []warningJSONSchema `json:"warnings"`
+	}{
+		items,
+		preview.TotalAmount,
+		warnings,
+	})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
 
-func addToCartHandler(srv *service.CartService, w http.ResponseWriter, r *http.Request) {
-	authUserID, err := parseAuthUserID(r)
+func addToCartHandler(srv *service.CartService, _ *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	owner, err := resolveCartOwner(srv, w, r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	var cartBody struct {
-		ID       uuid.UUID `json:"id"`
-		Quantity int       `json:"quantity"`
+		ID        uuid.UUID  `json:"id"`
+		VariantID *uuid.UUID `json:"variant_id"`
+		Quantity  int        `json:"quantity"`
 	}
 
 	err = json.NewDecoder(r.Body).Decode(&cartBody)
@@ -106,9 +275,14 @@ func addToCartHandler(srv *service.CartService, w http.ResponseWriter, r *http.R
 		return
 	}
 
-	err = srv.AddProduct(authUserID, cartBody.ID, cartBody.Quantity)
+	err = srv.AddProduct(owner, cartBody.ID, uuidOrNil(cartBody.VariantID), cartBody.Quantity)
+	if writeCartItemsError(w, err) {
+		return
+	}
 	switch {
-	case errors.Is(err, service.ErrInvalidQuantity) || errors.Is(err, service.ErrInvalidProduct):
+	case errors.Is(err, service.ErrInvalidQuantity) ||
+		errors.Is(err, service.ErrInvalidProduct) ||
+		errors.Is(err, service.ErrInvalidVariant):
 		w.WriteHeader(http.StatusBadRequest)
 	case err != nil:
 		w.WriteHeader(http.StatusInternalServerError)
@@ -117,10 +291,10 @@ func addToCartHandler(srv *service.CartService, w http.ResponseWriter, r *http.R
 	}
 }
 
-func deleteFromCartHandler(srv *service.CartService, w http.ResponseWriter, r *http.Request) {
-	authUserID, err := parseAuthUserID(r)
+func deleteFromCartHandler(srv *service.CartService, _ *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	owner, err := resolveCartOwner(srv, w, r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
@@ -129,8 +303,13 @@ func deleteFromCartHandler(srv *service.CartService, w http.ResponseWriter, r *h
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	variantID, err := parseVariantIDParam(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 
-	err = srv.DeleteProduct(authUserID, productID)
+	err = srv.DeleteProduct(owner, productID, variantID)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -138,27 +317,287 @@ func deleteFromCartHandler(srv *service.CartService, w http.ResponseWriter, r *h
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func checkoutHandler(srv *service.CartService, w http.ResponseWriter, r *http.Request) {
+func getCartDiscountHandler(srv *service.CartService, _ *service.WishlistService, w http.ResponseWriter, r *http.Request) {
 	authUserID, err := parseAuthUserID(r)
 	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
+	err = mergeGuestCart(srv, w, r, authUserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	promoCode := r.URL.Query().Get("promo_code")
+	if promoCode == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	preview, err := srv.GetDiscountPreview(authUserID, promoCode)
+	if writePromoCodeRejectedError(w, err) {
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type lineJSONSchema struct {
+		ID        uuid.UUID  `json:"id"`
+		VariantID *uuid.UUID `json:"variant_id,omitempty"`
+		Price     int        `json:"price"`
+		Quantity  int        `json:"quantity"`
+		Discount  int        `json:"discount"`
+	}
+
+	lines := make([]lineJSONSchema, 0, len(preview.Lines))
+	for _, line := range preview.Lines {
+		lines = append(lines, lineJSONSchema{
+			ID:        line.ProductID,
+			VariantID: nullableUUID(line.VariantID),
+			Price:     line.ItemPrice,
+			Quantity:  line.Quantity,
+			Discount:  line.Discount,
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		PromoCode   string           `json:"promo_code"`
+		Products    []lineJSONSchema `json:"products"`
+		Amount      int              `json:"amount"`
+		Discount    int              `json:"discount"`
+		TotalAmount int              `json:"total_amount"`
+	}{
+		promoCode,
+		lines,
+		preview.Amount,
+		preview.Discount,
+		preview.TotalAmount,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getCartShippingHandler(srv *service.CartService, _ *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	err = mergeGuestCart(srv, w, r, authUserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var addressID uuid.UUID
+	if addressIDParam := r.URL.Query().Get("address_id"); addressIDParam != "" {
+		addressID, err = uuid.Parse(addressIDParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	var pickupPointID uuid.UUID
+	if pickupPointIDParam := r.URL.Query().Get("pickup_point_id"); pickupPointIDParam != "" {
+		pickupPointID, err = uuid.Parse(pickupPointIDParam)
+		if err != nil || addressID != uuid.Nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	quote, err := srv.GetShippingQuote(authUserID, addressID, pickupPointID)
+	if writeShippingError(w, err) {
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var quoteAddressID, quotePickupPointID *uuid.UUID
+	if quote.PickupPointID != uuid.Nil {
+		quotePickupPointID = &quote.PickupPointID
+	} else {
+		quoteAddressID = &quote.AddressID
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		AddressID     *uuid.UUID `json:"address_id,omitempty"`
+		PickupPointID *uuid.UUID `json:"pickup_point_id,omitempty"`
+		Weight        int        `json:"weight"`
+		Amount        int        `json:"amount"`
+		ShippingFee   int        `json:"shipping_fee"`
+	}{
+		quoteAddressID,
+		quotePickupPointID,
+		quote.Weight,
+		quote.OrderAmount,
+		quote.ShippingFee,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func createQuoteHandler(srv *service.CartService, _ *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	err = mergeGuestCart(srv, w, r, authUserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var quoteBody struct {
+		AddressID     uuid.UUID `json:"address_id"`
+		PickupPointID uuid.UUID `json:"pickup_point_id"`
+		PromoCode     string    `json:"promo_code"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&quoteBody)
+	if err != nil || (quoteBody.AddressID != uuid.Nil && quoteBody.PickupPointID != uuid.Nil) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	quote, token, err := srv.CreateQuote(&service.QuoteData{
+		UserID:        authUserID,
+		AddressID:     quoteBody.AddressID,
+		PickupPointID: quoteBody.PickupPointID,
+		PromoCode:     quoteBody.PromoCode,
+	})
+	if writePromoCodeRejectedError(w, err) || writeShippingError(w, err) || writeCartItemsError(w, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrEmptyCartCheckout):
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type productJSONSchema struct {
+		ID        uuid.UUID  `json:"id"`
+		VariantID *uuid.UUID `json:"variant_id,omitempty"`
+		Price     int        `json:"price"`
+		Quantity  int        `json:"quantity"`
+		Discount  int        `json:"discount"`
+	}
+
+	products := make([]productJSONSchema, 0, len(quote.Products))
+	for _, product := range quote.Products {
+		products = append(products, productJSONSchema{
+			ID:        product.ID,
+			VariantID: nullableUUID(product.VariantID),
+			Price:     product.Price,
+			Quantity:  product.Quantity,
+			Discount:  product.Discount,
+		})
+	}
+
+	var quoteAddressID, quotePickupPointID *uuid.UUID
+	if quote.PickupPointID != uuid.Nil {
+		quotePickupPointID = &quote.PickupPointID
+	} else {
+		quoteAddressID = &quote.AddressID
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		QuoteToken    string              `json:"quote_token"`
+		ExpiresAt     time.Time           `json:"expires_at"`
+		AddressID     *uuid.UUID          `json:"address_id,omitempty"`
+		PickupPointID *uuid.UUID          `json:"pickup_point_id,omitempty"`
+		PromoCode     string              `json:"promo_code,omitempty"`
+		Products      []productJSONSchema `json:"products"`
+		Amount        int                 `json:"amount"`
+		Discount      int                 `json:"discount"`
+		ShippingFee   int                 `json:"shipping_fee"`
+		TotalAmount   int                 `json:"total_amount"`
+	}{
+		token,
+		quote.ExpiresAt,
+		quoteAddressID,
+		quotePickupPointID,
+		quote.PromoCode,
+		products,
+		quote.Amount(),
+		quote.Discount(),
+		quote.ShippingFee,
+		quote.TotalAmount(),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func checkoutHandler(srv *service.CartService, _ *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	idempotenceKey, err := parseIdempotenceKey(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = mergeGuestCart(srv, w, r, authUserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
 	var checkoutBody struct {
-		AddressID uuid.UUID `json:"address_id"`
+		AddressID      uuid.UUID `json:"address_id"`
+		PickupPointID  uuid.UUID `json:"pickup_point_id"`
+		DeliverySlotID uuid.UUID `json:"delivery_slot_id"`
+		PromoCode      string    `json:"promo_code"`
+		QuoteToken     string    `json:"quote_token"`
 	}
 	err = json.NewDecoder(r.Body).Decode(&checkoutBody)
-	if err != nil {
+	if err != nil || (checkoutBody.AddressID != uuid.Nil && checkoutBody.PickupPointID != uuid.Nil) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	orderID, err := srv.Checkout(authUserID, checkoutBody.AddressID)
+	orderID, err := srv.Checkout(&service.CheckoutData{
+		IdempotenceKey:   idempotenceKey,
+		UserID:           authUserID,
+		UserRegisteredAt: parseAuthUserRegisteredAt(r),
+		AddressID:        checkoutBody.AddressID,
+		PickupPointID:    checkoutBody.PickupPointID,
+		DeliverySlotID:   checkoutBody.DeliverySlotID,
+		PromoCode:        checkoutBody.PromoCode,
+		QuoteToken:       checkoutBody.QuoteToken,
+	})
+	if writePromoCodeRejectedError(w, err) ||
+		writeShippingError(w, err) ||
+		writeQuoteError(w, err) ||
+		writeCartItemsError(w, err) {
+		return
+	}
 	switch {
 	case errors.Is(err, service.ErrEmptyCartCheckout):
 		w.WriteHeader(http.StatusNotFound)
 		return
+	case errors.Is(err, service.ErrCheckoutInProgress):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(struct {
+			Error string `json:"error"`
+		}{"checkout_in_progress"})
+		return
 	case err != nil:
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -167,31 +606,526 @@ func checkoutHandler(srv *service.CartService, w http.ResponseWriter, r *http.Re
 	}
 }
 
-func healthCheckHandler(_ *service.CartService, w http.ResponseWriter, _ *http.Request) {
+func moveToWishlistHandler(cartSrv *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		WishlistID uuid.UUID  `json:"wishlist_id"`
+		VariantID  *uuid.UUID `json:"variant_id"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = mergeGuestCart(cartSrv, w, r, authUserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = srv.MoveFromCart(authUserID, body.WishlistID, productID, uuidOrNil(body.VariantID))
+	writeWishlistResult(w, err)
+}
+
+func getWishlistsHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	wishlists, err := srv.GetWishlists(authUserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type wishlistInfoJSONSchema struct {
+		ID         uuid.UUID `json:"id"`
+		Name       string    `json:"name"`
+		ShareURL   string    `json:"share_url,omitempty"`
+		ItemsCount int       `json:"items_count"`
+	}
+
+	result := make([]wishlistInfoJSONSchema, 0, len(wishlists))
+	for _, wishlist := range wishlists {
+		result = append(result, wishlistInfoJSONSchema{
+			ID:         wishlist.ID,
+			Name:       wishlist.Name,
+			ShareURL:   getShareURL(wishlist.ShareToken),
+			ItemsCount: len(wishlist.Items),
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func createWishlistHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var body struct {
+		Name string `json:"name"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	wishlistID, err := srv.CreateWishlist(authUserID, body.Name)
+	if errors.Is(err, service.ErrInvalidWishlistName) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(struct {
+		ID uuid.UUID `json:"id"`
+	}{wishlistID})
+}
+
+func getWishlistHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wishlistID, err := parseUUID(mux.Vars(r)["wishlistID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	preview, err := srv.GetWishlist(authUserID, wishlistID)
+	writeWishlistPreview(w, preview, err)
+}
+
+func getSharedWishlistHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	preview, err := srv.GetSharedWishlist(mux.Vars(r)["shareToken"])
+	writeWishlistPreview(w, preview, err)
+}
+
+func deleteWishlistHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wishlistID, err := parseUUID(mux.Vars(r)["wishlistID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.DeleteWishlist(authUserID, wishlistID)
+	writeWishlistResult(w, err)
+}
+
+func shareWishlistHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wishlistID, err := parseUUID(mux.Vars(r)["wishlistID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	shareToken, err := srv.ShareWishlist(authUserID, wishlistID)
+	if err != nil {
+		writeWishlistResult(w, err)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		ShareURL string `json:"share_url"`
+	}{getShareURL(shareToken)})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func unshareWishlistHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wishlistID, err := parseUUID(mux.Vars(r)["wishlistID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.UnshareWishlist(authUserID, wishlistID)
+	writeWishlistResult(w, err)
+}
+
+func addToWishlistHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wishlistID, err := parseUUID(mux.Vars(r)["wishlistID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		ID uuid.UUID `json:"id"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.AddProduct(authUserID, wishlistID, body.ID)
+	writeWishlistResult(w, err)
+}
+
+func deleteFromWishlistHandler(_ *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wishlistID, err := parseUUID(mux.Vars(r)["wishlistID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.RemoveProduct(authUserID, wishlistID, productID)
+	writeWishlistResult(w, err)
+}
+
+func moveToCartHandler(cartSrv *service.CartService, srv *service.WishlistService, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	wishlistID, err := parseUUID(mux.Vars(r)["wishlistID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	variantID, err := parseVariantIDParam(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = mergeGuestCart(cartSrv, w, r, authUserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = srv.MoveToCart(authUserID, wishlistID, productID, variantID)
+	writeWishlistResult(w, err)
+}
+
+func healthCheckHandler(_ *service.CartService, _ *service.WishlistService, w http.ResponseWriter, _ *http.Request) {
 	_ = json.NewEncoder(w).Encode(struct {
 		Status string `json:"status"`
 	}{"OK"})
 }
 
+func writeShippingError(w http.ResponseWriter, err error) bool {
+	var code string
+	switch {
+	case errors.Is(err, service.ErrInvalidAddress):
+		code = "invalid_address"
+	case errors.Is(err, service.ErrInvalidPickupPoint):
+		code = "invalid_pickup_point"
+	case errors.Is(err, service.ErrShippingUnavailable):
+		code = "shipping_unavailable"
+	default:
+		return false
+	}
+
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{code})
+	return true
+}
+
+func writeQuoteError(w http.ResponseWriter, err error) bool {
+	var code string
+	status := http.StatusConflict
+	switch {
+	case errors.Is(err, service.ErrInvalidQuote):
+		code = "invalid_quote"
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrQuoteExpired):
+		code = "quote_expired"
+	case errors.Is(err, service.ErrCartChanged):
+		code = "cart_changed"
+	case errors.Is(err, service.ErrDiscountChanged):
+		code = "discount_changed"
+	default:
+		return false
+	}
+
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{code})
+	return true
+}
+
+func writeCartItemsError(w http.ResponseWriter, err error) bool {
+	var unavailableErr *service.UnavailableProductsError
+	if errors.As(err, &unavailableErr) {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(struct {
+			Error      string      `json:"error"`
+			ProductIDs []uuid.UUID `json:"product_ids"`
+		}{"items_unavailable", unavailableErr.ProductIDs})
+		return true
+	}
+
+	var code string
+	switch {
+	case errors.Is(err, service.ErrQuantityLimit):
+		code = "quantity_limit_exceeded"
+	case errors.Is(err, service.ErrNotEnoughStock):
+		code = "not_enough_stock"
+	default:
+		return false
+	}
+
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{code})
+	return true
+}
+
+func writePromoCodeRejectedError(w http.ResponseWriter, err error) bool {
+	var rejectedErr *api.PromoCodeRejectedError
+	if !errors.As(err, &rejectedErr) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{rejectedErr.Reason})
+	return true
+}
+
+func writeWishlistResult(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrWishlistNotFound) ||
+		errors.Is(err, service.ErrWishlistItemNotFound) ||
+		errors.Is(err, service.ErrProductNotInCart):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidProduct) || errors.Is(err, service.ErrInvalidVariant):
+		w.WriteHeader(http.StatusBadRequest)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func writeWishlistPreview(w http.ResponseWriter, preview *service.WishlistPreview, err error) {
+	if errors.Is(err, service.ErrWishlistNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]wishlistItemJSONSchema, 0, len(preview.Items))
+	for _, item := range preview.Items {
+		items = append(items, wishlistItemJSONSchema{
+			ID:           item.ProductID,
+			Title:        item.Title,
+			Price:        item.Price,
+			AddedPrice:   item.AddedPrice,
+			PriceDropped: item.PriceDropped(),
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(wishlistJSONSchema{
+		ID:       preview.ID,
+		Name:     preview.Name,
+		ShareURL: getShareURL(preview.ShareToken),
+		Items:    items,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getShareURL(shareToken string) string {
+	if shareToken == "" {
+		return ""
+	}
+	return sharedWishlistPath + shareToken
+}
+
+func resolveCartOwner(srv *service.CartService, w http.ResponseWriter, r *http.Request) (domain.CartOwner, error) {
+	authUserID, err := parseAuthUserID(r)
+	if err == nil {
+		return domain.UserCartOwner(authUserID), mergeGuestCart(srv, w, r, authUserID)
+	}
+
+	guestID, err := parseGuestCartID(r)
+	if err != nil {
+		guestID = uuid.New()
+		http.SetCookie(w, &http.Cookie{
+			Name:     guestCartCookieName,
+			Value:    guestID.String(),
+			Path:     "/web/cart",
+			Expires:  time.Now().Local().Add(guestCartCookieLifetime),
+			HttpOnly: true,
+		})
+	}
+	return domain.GuestCartOwner(guestID), nil
+}
+
+func mergeGuestCart(srv *service.CartService, w http.ResponseWriter, r *http.Request, userID uuid.UUID) error {
+	guestID, err := parseGuestCartID(r)
+	if err != nil {
+		return nil
+	}
+
+	err = srv.MergeGuestCart(guestID, userID)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     guestCartCookieName,
+		Path:     "/web/cart",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+	return nil
+}
+
+func parseGuestCartID(r *http.Request) (uuid.UUID, error) {
+	cookie, err := r.Cookie(guestCartCookieName)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	guestID, err := uuid.Parse(cookie.Value)
+	if err == nil && guestID == uuid.Nil {
+		return uuid.Nil, errors.New("empty guest cart id")
+	}
+	return guestID, err
+}
+
+func parseIdempotenceKey(r *http.Request) (string, error) {
+	key := r.Header.Get("X-Idempotence-Key")
+	if key == "" {
+		return "", errors.New("idempotence key not found")
+	}
+	return key, nil
+}
+
 func parseAuthUserID(r *http.Request) (uuid.UUID, error) {
 	id := r.Header.Get("X-Auth-User-ID")
 	return uuid.Parse(id)
 }
 
+func parseAuthUserRegisteredAt(r *http.Request) time.Time {
+	registeredAt, err := time.Parse(time.RFC3339, r.Header.Get("X-Auth-User-Registered-At"))
+	if err != nil {
+		return time.Time{}
+	}
+	return registeredAt
+}
+
 func parseUUID(str string) (uuid.UUID, error) {
 	return uuid.Parse(str)
 }
 
+func parseVariantIDParam(r *http.Request) (uuid.UUID, error) {
+	variantIDParam := r.URL.Query().Get("variant_id")
+	if variantIDParam == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(variantIDParam)
+}
+
+func uuidOrNil(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
+
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
 func getHandlerFunc(
 	cartService *service.CartService,
-	f func(*service.CartService, http.ResponseWriter, *http.Request),
+	wishlistService *service.WishlistService,
+	f func(*service.CartService, *service.WishlistService, http.ResponseWriter, *http.Request),
 ) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		f(cartService, w, r)
+		f(cartService, wishlistService, w, r)
 	}
 }
 
-func NewHTTPHandler(cartService *service.CartService, logger log.Logger) (http.Handler, error) {
+func NewHTTPHandler(
+	cartService *service.CartService,
+	wishlistService *service.WishlistService,
+	logger log.Logger,
+) (http.Handler, error) {
 	router := mux.NewRouter()
 
 	for _, route := range getRoutes() {
@@ -199,7 +1133,7 @@ func NewHTTPHandler(cartService *service.CartService, logger log.Logger) (http.H
 			Methods(route.Method).
 			Path(route.Pattern).
 			Name(route.Name).
-			HandlerFunc(getHandlerFunc(cartService, route.Handler))
+			HandlerFunc(getHandlerFunc(cartService, wishlistService, route.Handler))
 	}
 
 	router.Use(transport.NewLoggingMiddleware(logger, []string{healthEndpoint}))
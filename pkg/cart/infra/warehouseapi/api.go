@@ -0,0 +1,63 @@
+package warehouseapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/api"
+	"net/http"
+)
+
+type apiClient struct {
+	client     *http.Client
+	serviceURL string
+}
+
+func (c *apiClient) GetAvailableQuantity(itemIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	itemsJSON, err := json.Marshal(itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode items for request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/warehouse/items/available", c.serviceURL)
+	req, err := http.NewRequest(http.MethodGet, url, bytes.NewBuffer(itemsJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, api.ErrItemsNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to getAvailableItemsQuantity, httpCode: %v", resp.StatusCode)
+	}
+
+	var itemsQuantity []struct {
+		ItemID   uuid.UUID `json:"item_id"`
+		Quantity int       `json:"quantity"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&itemsQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode getAvailableItemsQuantity response: %w", err)
+	}
+
+	result := make(map[uuid.UUID]int, len(itemsQuantity))
+	for _, item := range itemsQuantity {
+		result[item.ItemID] = item.Quantity
+	}
+	return result, nil
+}
+
+func New(serviceURL string) api.WarehouseAPI {
+	return &apiClient{
+		client:     &http.Client{},
+		serviceURL: serviceURL,
+	}
+}
@@ -0,0 +1,56 @@
+package wishlisteventapi
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/cart/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
+)
+
+const wishlistEventTopicName = "wishlist_event"
+
+type api struct {
+	eventDispatcher event.Dispatcher
+}
+
+func (a *api) NotifyPriceDropped(userID, productID uuid.UUID, oldPrice, newPrice int) error {
+	body, err := json.Marshal(struct {
+		UserID    uuid.UUID `json:"user_id"`
+		ProductID uuid.UUID `json:"product_id"`
+		OldPrice  int       `json:"old_price"`
+		NewPrice  int       `json:"new_price"`
+	}{userID, productID, oldPrice, newPrice})
+	if err != nil {
+		return errors.New("failed to encode wishlist event")
+	}
+	return a.dispatch("wishlist_price_dropped", userID, body)
+}
+
+func (a *api) NotifyBackInStock(userID, productID uuid.UUID) error {
+	body, err := json.Marshal(struct {
+		UserID    uuid.UUID `json:"user_id"`
+		ProductID uuid.UUID `json:"product_id"`
+	}{userID, productID})
+	if err != nil {
+		return errors.New("failed to encode wishlist event")
+	}
+	return a.dispatch("wishlist_back_in_stock", userID, body)
+}
+
+func (a *api) dispatch(eventType string, userID uuid.UUID, body []byte) error {
+	err := a.eventDispatcher.Dispatch(&event.Event{
+		Type:      eventType,
+		TopicName: wishlistEventTopicName,
+		Key:       userID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func New(eventDispatcher event.Dispatcher) async.WishlistEventAPI {
+	return &api{eventDispatcher: eventDispatcher}
+}
@@ -0,0 +1,41 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+)
+
+type itemBackInStockHandler struct {
+	service *service.ProductService
+}
+
+func (h *itemBackInStockHandler) TopicName() string {
+	return stockEventTopicName
+}
+
+func (h *itemBackInStockHandler) Type() string {
+	return "item_back_in_stock"
+}
+
+func (h *itemBackInStockHandler) Handle(msg *message.Message) error {
+	var body struct {
+		ItemID uuid.UUID `json:"item_id"`
+	}
+	err := json.Unmarshal(msg.Body, &body)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.SetItemInStock(body.ItemID, true)
+	if err != nil {
+		return fmt.Errorf("failed to mark item back in stock: %w", err)
+	}
+	return nil
+}
+
+func NewItemBackInStockHandler(service *service.ProductService) message.Handler {
+	return &itemBackInStockHandler{service: service}
+}
@@ -0,0 +1,41 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+)
+
+type itemOutOfStockHandler struct {
+	service *service.ProductService
+}
+
+func (h *itemOutOfStockHandler) TopicName() string {
+	return stockEventTopicName
+}
+
+func (h *itemOutOfStockHandler) Type() string {
+	return "item_out_of_stock"
+}
+
+func (h *itemOutOfStockHandler) Handle(msg *message.Message) error {
+	var body struct {
+		ItemID uuid.UUID `json:"item_id"`
+	}
+	err := json.Unmarshal(msg.Body, &body)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.SetItemInStock(body.ItemID, false)
+	if err != nil {
+		return fmt.Errorf("failed to mark item out of stock: %w", err)
+	}
+	return nil
+}
+
+func NewItemOutOfStockHandler(service *service.ProductService) message.Handler {
+	return &itemOutOfStockHandler{service: service}
+}
@@ -0,0 +1,42 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+)
+
+type itemStockSyncedHandler struct {
+	service *service.ProductService
+}
+
+func (h *itemStockSyncedHandler) TopicName() string {
+	return stockEventTopicName
+}
+
+func (h *itemStockSyncedHandler) Type() string {
+	return "item_stock_synced"
+}
+
+func (h *itemStockSyncedHandler) Handle(msg *message.Message) error {
+	var body struct {
+		ItemID   uuid.UUID `json:"item_id"`
+		Quantity int       `json:"quantity"`
+	}
+	err := json.Unmarshal(msg.Body, &body)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.SyncItemInStock(body.ItemID, body.Quantity > 0)
+	if err != nil {
+		return fmt.Errorf("failed to sync item stock availability: %w", err)
+	}
+	return nil
+}
+
+func NewItemStockSyncedHandler(service *service.ProductService) message.Handler {
+	return &itemStockSyncedHandler{service: service}
+}
@@ -0,0 +1,3 @@
+package message
+
+const stockEventTopicName = "stock_event"
@@ -1,9 +1,14 @@
 package persistence
 
-import "github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
+import (
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
+)
 
 type PersistentProvider interface {
 	ProductRepository() domain.ProductRepository
+	CategoryRepository() domain.CategoryRepository
+	ProductEventAPI() async.ProductEventAPI
 }
 
 type UnitOfWork interface {
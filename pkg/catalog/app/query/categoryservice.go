@@ -0,0 +1,22 @@
+package query
+
+import (
+	"errors"
+	"github.com/google/uuid"
+)
+
+type CategoryData struct {
+	ID       uuid.UUID
+	ParentID uuid.UUID
+	Slug     string
+	Title    string
+	Position int
+}
+
+var ErrCategoryBySlugNotFound = errors.New("category by slug is not found")
+
+type CategoryService interface {
+	ListAll() ([]CategoryData, error)
+	GetSubtreeIDsBySlug(slug string) ([]uuid.UUID, error)
+	GetProductsBySlug(slug string) ([]ProductData, error)
+}
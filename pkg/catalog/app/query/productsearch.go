@@ -0,0 +1,123 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+)
+
+type ProductSearchSort string
+
+const (
+	ProductSearchSortRelevance ProductSearchSort = "relevance"
+	ProductSearchSortPriceAsc  ProductSearchSort = "price_asc"
+	ProductSearchSortPriceDesc ProductSearchSort = "price_desc"
+	ProductSearchSortTitle     ProductSearchSort = "title"
+)
+
+var (
+	ErrInvalidSearchCursor = errors.New("invalid search cursor")
+	ErrUnknownSearchSort   = errors.New("unknown search sort")
+)
+
+var ProductSearchPriceRanges = []PriceRange{
+	{From: 0, To: 1000},
+	{From: 1000, To: 5000},
+	{From: 5000, To: 10000},
+	{From: 10000, To: 50000},
+	{From: 50000},
+}
+
+type PriceRange struct {
+	From int
+	To   int // zero means unbounded
+}
+
+func (r PriceRange) Contains(price int) bool {
+	return price >= r.From && (r.To == 0 || price < r.To)
+}
+
+type ProductSearchParams struct {
+	Text        string
+	MinPrice    int
+	MaxPrice    int // zero means unbounded
+	CategoryIDs []uuid.UUID
+	InStockOnly bool
+	Sort        ProductSearchSort
+	Cursor      string
+	Limit       int
+}
+
+func (p *ProductSearchParams) EffectiveSort() (ProductSearchSort, error) {
+	switch p.Sort {
+	case "":
+		if p.Text == "" {
+			return ProductSearchSortTitle, nil
+		}
+		return ProductSearchSortRelevance, nil
+	case ProductSearchSortRelevance:
+		if p.Text == "" {
+			return ProductSearchSortTitle, nil
+		}
+		return p.Sort, nil
+	case ProductSearchSortPriceAsc, ProductSearchSortPriceDesc, ProductSearchSortTitle:
+		return p.Sort, nil
+	default:
+		return "", ErrUnknownSearchSort
+	}
+}
+
+type ProductSearchCursor struct {
+	Sort ProductSearchSort `json:"sort"`
+	Key  string            `json:"key"`
+	ID   uuid.UUID         `json:"id"`
+}
+
+func EncodeProductSearchCursor(cursor ProductSearchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeProductSearchCursor(str string, sort ProductSearchSort) (*ProductSearchCursor, error) {
+	if str == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return nil, ErrInvalidSearchCursor
+	}
+	var cursor ProductSearchCursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.Sort != sort {
+		return nil, ErrInvalidSearchCursor
+	}
+	return &cursor, nil
+}
+
+type CategoryFacet struct {
+	CategoryID uuid.UUID
+	Count      int
+}
+
+type PriceRangeFacet struct {
+	PriceRange
+	Count int
+}
+
+type ProductSearchFacets struct {
+	Categories  []CategoryFacet
+	PriceRanges []PriceRangeFacet
+	InStock     int
+}
+
+type ProductSearchResult struct {
+	Products   []ProductData
+	Total      int
+	NextCursor string
+	Facets     ProductSearchFacets
+}
+
+type ProductSearchIndex interface {
+	Search(params ProductSearchParams) (*ProductSearchResult, error)
+}
@@ -5,11 +5,38 @@ import (
 	"github.com/google/uuid"
 )
 
+type ProductOptionData struct {
+	Name   string
+	Values []string
+}
+
+type ProductVariantData struct {
+	ID              uuid.UUID
+	SKU             string
+	OptionValues    []string
+	Price           int
+	WarehouseItemID uuid.UUID
+	InStock         bool
+}
+
+type ProductImageData struct {
+	ID            uuid.UUID
+	URL           string
+	ThumbnailURLs map[string]string
+}
+
 type ProductData struct {
 	ID          uuid.UUID
 	Title       string
 	Description string
 	Price       int
+	Weight      int
+	MaxQuantity int
+	CategoryIDs []uuid.UUID
+	InStock     bool
+	Options     []ProductOptionData
+	Variants    []ProductVariantData
+	Images      []ProductImageData
 }
 
 var ErrProductByIDNotFound = errors.New("product by id is not found")
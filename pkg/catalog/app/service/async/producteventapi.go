@@ -0,0 +1,8 @@
+package async
+
+import "github.com/google/uuid"
+
+type ProductEventAPI interface {
+	NotifyPriceChanged(productID uuid.UUID, oldPrice, newPrice int) error
+	NotifyBackInStock(productID uuid.UUID) error
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+)
+
+var (
+	ErrCategoryNotExists     = errors.New("category not exists")
+	ErrCategorySlugExists    = errors.New("category slug already exists")
+	ErrCategoryHasChildren   = errors.New("category has children")
+	ErrInvalidCategoryParent = errors.New("invalid category parent")
+)
+
+type CategoryService struct {
+	ufw    persistence.UnitOfWork
+	logger log.Logger
+}
+
+func (s *CategoryService) Add(parentID uuid.UUID, slug, title string, position int) (uuid.UUID, error) {
+	err := s.validateCategoryProperties(slug, title)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var categoryID uuid.UUID
+	err = s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		categoryID = p.CategoryRepository().NextID()
+		category := &domain.Category{
+			ID:       categoryID,
+			ParentID: parentID,
+			Slug:     slug,
+			Title:    title,
+			Position: position,
+		}
+
+		err := s.checkCategory(p, category)
+		if err != nil {
+			return err
+		}
+		return p.CategoryRepository().Store(category)
+	})
+	if err != nil && !isCategoryError(err) {
+		s.logger.WithError(err).With(log.Fields{"slug": slug}).Error("failed to add category")
+	}
+	return categoryID, err
+}
+
+func (s *CategoryService) Update(id, parentID uuid.UUID, slug, title string, position int) error {
+	err := s.validateCategoryProperties(slug, title)
+	if err != nil {
+		return err
+	}
+
+	err = s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		category, err := p.CategoryRepository().GetByID(id)
+		if errors.Is(err, domain.ErrCategoryNotExists) {
+			return ErrCategoryNotExists
+		}
+		if err != nil {
+			return err
+		}
+
+		category.ParentID = parentID
+		category.Slug = slug
+		category.Title = title
+		category.Position = position
+
+		err = s.checkCategory(p, category)
+		if err != nil {
+			return err
+		}
+		return p.CategoryRepository().Store(category)
+	})
+	if err != nil && !isCategoryError(err) {
+		s.logger.WithError(err).With(log.Fields{"id": id}).Error("failed to update category")
+	}
+	return err
+}
+
+func (s *CategoryService) Delete(id uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		categories, err := p.CategoryRepository().GetAll()
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, category := range categories {
+			if category.ParentID == id {
+				return ErrCategoryHasChildren
+			}
+			found = found || category.ID == id
+		}
+		if !found {
+			return ErrCategoryNotExists
+		}
+		return p.CategoryRepository().Delete(id)
+	})
+	if err != nil && !isCategoryError(err) {
+		s.logger.WithError(err).With(log.Fields{"id": id}).Error("failed to delete category")
+	}
+	return err
+}
+
+func (s *CategoryService) checkCategory(p persistence.PersistentProvider, category *domain.Category) error {
+	existing, err := p.CategoryRepository().GetBySlug(category.Slug)
+	if err == nil && existing.ID != category.ID {
+		return ErrCategorySlugExists
+	}
+	if err != nil && !errors.Is(err, domain.ErrCategoryNotExists) {
+		return err
+	}
+
+	if category.ParentID == uuid.Nil {
+		return nil
+	}
+
+	categories, err := p.CategoryRepository().GetAll()
+	if err != nil {
+		return err
+	}
+	parentByID := make(map[uuid.UUID]uuid.UUID, len(categories))
+	for _, c := range categories {
+		parentByID[c.ID] = c.ParentID
+	}
+
+	// parent must exist and the category must not become an ancestor of itself
+	for parentID := category.ParentID; parentID != uuid.Nil; parentID = parentByID[parentID] {
+		if _, ok := parentByID[parentID]; !ok || parentID == category.ID {
+			return ErrInvalidCategoryParent
+		}
+	}
+	return nil
+}
+
+func (s *CategoryService) validateCategoryProperties(slug, title string) error {
+	if !domain.IsValidSlug(slug) {
+		return fmt.Errorf("%w slug: %s", ErrInvalidProperty, slug)
+	}
+	if title == "" {
+		return fmt.Errorf("%w title: %s", ErrInvalidProperty, title)
+	}
+	return nil
+}
+
+func isCategoryError(err error) bool {
+	return errors.Is(err, ErrCategoryNotExists) ||
+		errors.Is(err, ErrCategorySlugExists) ||
+		errors.Is(err, ErrCategoryHasChildren) ||
+		errors.Is(err, ErrInvalidCategoryParent)
+}
+
+func NewCategoryService(ufw persistence.UnitOfWork, logger log.Logger) *CategoryService {
+	return &CategoryService{ufw: ufw, logger: logger}
+}
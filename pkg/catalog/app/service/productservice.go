@@ -5,22 +5,32 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/storage"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"strings"
 )
 
+const MaxImageSize = 10 << 20 // bytes
+
 var (
-	ErrInvalidProperty  = errors.New("invalid property")
-	ErrProductNotExists = errors.New("product not exists")
+	ErrInvalidProperty       = errors.New("invalid property")
+	ErrProductNotExists      = errors.New("product not exists")
+	ErrSKUExists             = errors.New("variant with sku already exists")
+	ErrProductImageNotExists = errors.New("product image not exists")
+	ErrUnsupportedImageType  = errors.New("unsupported image type")
+	ErrImageTooLarge         = errors.New("image is too large")
+	ErrInvalidImage          = errors.New("invalid image")
 )
 
 type ProductService struct {
 	ufw    persistence.UnitOfWork
+	images storage.BlobStorage
 	logger log.Logger
 }
 
-func (s *ProductService) Add(title, description string, price int) (uuid.UUID, error) {
-	err := s.validateProductProperties(title, price)
+func (s *ProductService) Add(title, description string, price, weight, maxQuantity int) (uuid.UUID, error) {
+	err := s.validateProductProperties(title, price, weight, maxQuantity)
 	if err != nil {
 		return uuid.UUID{}, err
 	}
@@ -33,6 +43,8 @@ func (s *ProductService) Add(title, description string, price int) (uuid.UUID, e
 			Title:       title,
 			Description: description,
 			Price:       price,
+			Weight:      weight,
+			MaxQuantity: maxQuantity,
 		}
 
 		return p.ProductRepository().Store(product)
@@ -43,8 +55,8 @@ func (s *ProductService) Add(title, description string, price int) (uuid.UUID, e
 	return productID, err
 }
 
-func (s *ProductService) Update(id uuid.UUID, title, description string, price int) error {
-	err := s.validateProductProperties(title, price)
+func (s *ProductService) Update(id uuid.UUID, title, description string, price, weight, maxQuantity int) error {
+	err := s.validateProductProperties(title, price, weight, maxQuantity)
 	if err != nil {
 		return err
 	}
@@ -55,11 +67,23 @@ func (s *ProductService) Update(id uuid.UUID, title, description string, price i
 			return ErrProductNotExists
 		}
 
+		oldPrice := product.Price
 		product.Title = title
 		product.Description = description
 		product.Price = price
+		product.Weight = weight
+		product.MaxQuantity = maxQuantity
 
-		return p.ProductRepository().Store(product)
+		err = p.ProductRepository().Store(product)
+		if err != nil || oldPrice == price {
+			return err
+		}
+
+		err = p.ProductEventAPI().NotifyPriceChanged(product.ID, oldPrice, price)
+		if err != nil {
+			return fmt.Errorf("failed to notify price changed: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
 		s.logger.WithError(err).With(log.Fields{"id": id}).Error("failed to update product")
@@ -67,16 +91,348 @@ func (s *ProductService) Update(id uuid.UUID, title, description string, price i
 	return err
 }
 
-func (s *ProductService) validateProductProperties(title string, price int) error {
+func (s *ProductService) SetCategories(id uuid.UUID, categoryIDs []uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		product, err := p.ProductRepository().GetByID(id)
+		if errors.Is(err, domain.ErrProductNotExists) {
+			return ErrProductNotExists
+		}
+		if err != nil {
+			return err
+		}
+
+		product.CategoryIDs = make([]uuid.UUID, 0, len(categoryIDs))
+		added := make(map[uuid.UUID]bool, len(categoryIDs))
+		for _, categoryID := range categoryIDs {
+			if added[categoryID] {
+				continue
+			}
+			_, err = p.CategoryRepository().GetByID(categoryID)
+			if errors.Is(err, domain.ErrCategoryNotExists) {
+				return ErrCategoryNotExists
+			}
+			if err != nil {
+				return err
+			}
+			product.CategoryIDs = append(product.CategoryIDs, categoryID)
+			added[categoryID] = true
+		}
+
+		return p.ProductRepository().Store(product)
+	})
+	if err != nil && !errors.Is(err, ErrProductNotExists) && !errors.Is(err, ErrCategoryNotExists) {
+		s.logger.WithError(err).With(log.Fields{"id": id}).Error("failed to set product categories")
+	}
+	return err
+}
+
+func (s *ProductService) SetVariants(id uuid.UUID, options []domain.ProductOption, variants []domain.ProductVariant) error {
+	err := s.validateVariants(options, variants)
+	if err != nil {
+		return err
+	}
+
+	err = s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		product, err := p.ProductRepository().GetByID(id)
+		if errors.Is(err, domain.ErrProductNotExists) {
+			return ErrProductNotExists
+		}
+		if err != nil {
+			return err
+		}
+
+		for i := range variants {
+			variant := &variants[i]
+			if variant.ID == uuid.Nil {
+				variant.ID = p.ProductRepository().NextID()
+			} else if _, ok := product.GetVariant(variant.ID); !ok {
+				return fmt.Errorf("%w variant id: %v", ErrInvalidProperty, variant.ID)
+			}
+			if variant.WarehouseItemID == uuid.Nil {
+				variant.WarehouseItemID = variant.ID
+			}
+
+			productID, err := p.ProductRepository().GetIDBySKU(variant.SKU)
+			if err == nil && productID != id {
+				return fmt.Errorf("%w: %s", ErrSKUExists, variant.SKU)
+			}
+			if err != nil && !errors.Is(err, domain.ErrProductNotExists) {
+				return err
+			}
+		}
+
+		product.Options = options
+		product.Variants = variants
+		return p.ProductRepository().Store(product)
+	})
+	if err != nil && !errors.Is(err, ErrProductNotExists) && !errors.Is(err, ErrInvalidProperty) && !errors.Is(err, ErrSKUExists) {
+		s.logger.WithError(err).With(log.Fields{"id": id}).Error("failed to set product variants")
+	}
+	return err
+}
+
+func (s *ProductService) AddImage(id uuid.UUID, contentType string, data []byte) (uuid.UUID, error) {
+	if !domain.IsSupportedImageType(contentType) {
+		return uuid.Nil, ErrUnsupportedImageType
+	}
+	if len(data) > MaxImageSize {
+		return uuid.Nil, ErrImageTooLarge
+	}
+	thumbnails, err := getImageThumbnails(contentType, data)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var image domain.ProductImage
+	var imageStored bool
+	err = s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		product, err := p.ProductRepository().GetByID(id)
+		if errors.Is(err, domain.ErrProductNotExists) {
+			return ErrProductNotExists
+		}
+		if err != nil {
+			return err
+		}
+
+		image = domain.ProductImage{ID: p.ProductRepository().NextID(), ContentType: contentType}
+		err = s.putImageBlobs(id, image, data, thumbnails)
+		if err != nil {
+			return fmt.Errorf("failed to store image: %w", err)
+		}
+
+		product.Images = append(product.Images, image)
+		err = p.ProductRepository().Store(product)
+		if err != nil {
+			return err
+		}
+		imageStored = true
+		return nil
+	})
+	// a failed commit might still be applied, so the blobs are deleted only if the image is not referenced
+	if err != nil && image.ID != uuid.Nil && (!imageStored || !s.hasImage(id, image.ID)) {
+		s.deleteImageBlobs(id, image)
+	}
+	if err != nil && !errors.Is(err, ErrProductNotExists) {
+		s.logger.WithError(err).With(log.Fields{"id": id}).Error("failed to add product image")
+	}
+	return image.ID, err
+}
+
+func (s *ProductService) SetImageOrder(id uuid.UUID, imageIDs []uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		product, err := p.ProductRepository().GetByID(id)
+		if errors.Is(err, domain.ErrProductNotExists) {
+			return ErrProductNotExists
+		}
+		if err != nil {
+			return err
+		}
+		if len(imageIDs) != len(product.Images) {
+			return fmt.Errorf("%w images: expected %d ids", ErrInvalidProperty, len(product.Images))
+		}
+
+		imageByID := make(map[uuid.UUID]domain.ProductImage, len(product.Images))
+		for _, image := range product.Images {
+			imageByID[image.ID] = image
+		}
+		images := make([]domain.ProductImage, 0, len(imageIDs))
+		for _, imageID := range imageIDs {
+			image, ok := imageByID[imageID]
+			if !ok {
+				return fmt.Errorf("%w image id: %v", ErrInvalidProperty, imageID)
+			}
+			images = append(images, image)
+			delete(imageByID, imageID)
+		}
+
+		product.Images = images
+		return p.ProductRepository().Store(product)
+	})
+	if err != nil && !errors.Is(err, ErrProductNotExists) && !errors.Is(err, ErrInvalidProperty) {
+		s.logger.WithError(err).With(log.Fields{"id": id}).Error("failed to set product image order")
+	}
+	return err
+}
+
+func (s *ProductService) RemoveImage(id, imageID uuid.UUID) error {
+	var image domain.ProductImage
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		product, err := p.ProductRepository().GetByID(id)
+		if errors.Is(err, domain.ErrProductNotExists) {
+			return ErrProductNotExists
+		}
+		if err != nil {
+			return err
+		}
+
+		var ok bool
+		image, ok = product.RemoveImage(imageID)
+		if !ok {
+			return ErrProductImageNotExists
+		}
+		return p.ProductRepository().Store(product)
+	})
+	if err == nil {
+		s.deleteImageBlobs(id, image)
+	}
+	if err != nil && !errors.Is(err, ErrProductNotExists) && !errors.Is(err, ErrProductImageNotExists) {
+		s.logger.WithError(err).With(log.Fields{"id": id, "imageID": imageID}).Error("failed to remove product image")
+	}
+	return err
+}
+
+func (s *ProductService) GetImageBlob(key string) (*storage.Blob, error) {
+	blob, err := s.images.Get(key)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return nil, ErrProductImageNotExists
+	}
+	return blob, err
+}
+
+func (s *ProductService) putImageBlobs(productID uuid.UUID, image domain.ProductImage, original []byte, thumbnails map[domain.ImageSize][]byte) error {
+	err := s.images.Put(image.Key(productID, domain.ImageSizeOriginal), image.ContentType, original)
+	if err != nil {
+		return err
+	}
+	for size, data := range thumbnails {
+		err = s.images.Put(image.Key(productID, size), image.ContentType, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ProductService) hasImage(productID, imageID uuid.UUID) bool {
+	var found bool
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		product, err := p.ProductRepository().GetByID(productID)
+		if err != nil {
+			return err
+		}
+		for _, image := range product.Images {
+			if image.ID == imageID {
+				found = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"id": productID, "imageID": imageID}).Error("failed to check product image")
+		return true
+	}
+	return found
+}
+
+func (s *ProductService) deleteImageBlobs(productID uuid.UUID, image domain.ProductImage) {
+	for _, key := range image.Keys(productID) {
+		err := s.images.Delete(key)
+		if err != nil {
+			s.logger.WithError(err).With(log.Fields{"key": key}).Error("failed to delete image blob")
+		}
+	}
+}
+
+func (s *ProductService) SetItemInStock(itemID uuid.UUID, inStock bool) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		productIDs, err := p.ProductRepository().SetItemInStock(itemID, inStock)
+		if err != nil || !inStock {
+			return err
+		}
+
+		for _, productID := range productIDs {
+			err = p.ProductEventAPI().NotifyBackInStock(productID)
+			if err != nil {
+				return fmt.Errorf("failed to notify product back in stock: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"itemID": itemID, "inStock": inStock}).Error("failed to set item stock availability")
+	}
+	return err
+}
+
+func (s *ProductService) SyncItemInStock(itemID uuid.UUID, inStock bool) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		_, err := p.ProductRepository().SetItemInStock(itemID, inStock)
+		return err
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"itemID": itemID, "inStock": inStock}).Error("failed to sync item stock availability")
+	}
+	return err
+}
+
+func (s *ProductService) validateProductProperties(title string, price, weight, maxQuantity int) error {
 	if title == "" {
 		return fmt.Errorf("%w title: %s", ErrInvalidProperty, title)
 	}
 	if price <= 0 {
 		return fmt.Errorf("%w price: %d", ErrInvalidProperty, price)
 	}
+	if weight < 0 {
+		return fmt.Errorf("%w weight: %d", ErrInvalidProperty, weight)
+	}
+	if maxQuantity < 0 {
+		return fmt.Errorf("%w max quantity: %d", ErrInvalidProperty, maxQuantity)
+	}
+	return nil
+}
+
+func (s *ProductService) validateVariants(options []domain.ProductOption, variants []domain.ProductVariant) error {
+	if len(options) == 0 && len(variants) > 0 {
+		return fmt.Errorf("%w variants: product has no options", ErrInvalidProperty)
+	}
+
+	optionNames := make(map[string]bool, len(options))
+	optionValues := make([]map[string]bool, 0, len(options))
+	for _, option := range options {
+		if option.Name == "" || optionNames[option.Name] || len(option.Values) == 0 {
+			return fmt.Errorf("%w option: %s", ErrInvalidProperty, option.Name)
+		}
+		optionNames[option.Name] = true
+
+		values := make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			if value == "" || values[value] {
+				return fmt.Errorf("%w option %s value: %s", ErrInvalidProperty, option.Name, value)
+			}
+			values[value] = true
+		}
+		optionValues = append(optionValues, values)
+	}
+
+	skus := make(map[string]bool, len(variants))
+	combinations := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		if variant.SKU == "" || skus[variant.SKU] {
+			return fmt.Errorf("%w variant sku: %s", ErrInvalidProperty, variant.SKU)
+		}
+		skus[variant.SKU] = true
+
+		if variant.Price < 0 {
+			return fmt.Errorf("%w variant %s price: %d", ErrInvalidProperty, variant.SKU, variant.Price)
+		}
+		if len(variant.OptionValues) != len(options) {
+			return fmt.Errorf("%w variant %s options", ErrInvalidProperty, variant.SKU)
+		}
+		for i, value := range variant.OptionValues {
+			if !optionValues[i][value] {
+				return fmt.Errorf("%w variant %s option %s: %s", ErrInvalidProperty, variant.SKU, options[i].Name, value)
+			}
+		}
+
+		combination := strings.Join(variant.OptionValues, "\x00")
+		if combinations[combination] {
+			return fmt.Errorf("%w variant %s: duplicate options", ErrInvalidProperty, variant.SKU)
+		}
+		combinations[combination] = true
+	}
 	return nil
 }
 
-func NewProductService(ufw persistence.UnitOfWork, logger log.Logger) *ProductService {
-	return &ProductService{ufw: ufw, logger: logger}
+func NewProductService(ufw persistence.UnitOfWork, images storage.BlobStorage, logger log.Logger) *ProductService {
+	return &ProductService{ufw: ufw, images: images, logger: logger}
 }
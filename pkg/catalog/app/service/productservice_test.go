@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/storage"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"image"
+	"image/png"
+	"testing"
+)
+
+type testProductRepository struct {
+	domain.ProductRepository
+	products map[uuid.UUID]domain.Product
+	storeErr error
+}
+
+func (r *testProductRepository) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *testProductRepository) GetByID(id uuid.UUID) (*domain.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, domain.ErrProductNotExists
+	}
+	product.Images = append([]domain.ProductImage(nil), product.Images...)
+	return &product, nil
+}
+
+func (r *testProductRepository) Store(product *domain.Product) error {
+	if r.storeErr != nil {
+		return r.storeErr
+	}
+	r.products[product.ID] = *product
+	return nil
+}
+
+type testPersistentProvider struct {
+	repo *testProductRepository
+}
+
+func (p *testPersistentProvider) ProductRepository() domain.ProductRepository {
+	return p.repo
+}
+
+func (p *testPersistentProvider) CategoryRepository() domain.CategoryRepository {
+	return nil
+}
+
+func (p *testPersistentProvider) ProductEventAPI() async.ProductEventAPI {
+	return nil
+}
+
+type testUnitOfWork struct {
+	repo          *testProductRepository
+	commitErr     error
+	commitApplied bool
+}
+
+func (u *testUnitOfWork) Execute(f func(p persistence.PersistentProvider) error) error {
+	tx := &testProductRepository{products: make(map[uuid.UUID]domain.Product), storeErr: u.repo.storeErr}
+	for id, product := range u.repo.products {
+		tx.products[id] = product
+	}
+	err := f(&testPersistentProvider{repo: tx})
+	if err != nil {
+		return err
+	}
+	if u.commitErr != nil {
+		err, u.commitErr = u.commitErr, nil
+		if !u.commitApplied {
+			return err
+		}
+	}
+	u.repo.products = tx.products
+	return err
+}
+
+type testBlobStorage struct {
+	blobs map[string][]byte
+}
+
+func (s *testBlobStorage) Put(key, _ string, data []byte) error {
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *testBlobStorage) Get(string) (*storage.Blob, error) {
+	return nil, storage.ErrBlobNotFound
+}
+
+func (s *testBlobStorage) Delete(key string) error {
+	delete(s.blobs, key)
+	return nil
+}
+
+func (s *testBlobStorage) PublicURL(key string) string {
+	return key
+}
+
+type testLogger struct{}
+
+func (l testLogger) With(log.Fields) log.Logger { return l }
+func (l testLogger) WithError(error) log.Logger { return l }
+func (l testLogger) Debug(...interface{})       {}
+func (l testLogger) Error(...interface{})       {}
+func (l testLogger) Warn(...interface{})        {}
+func (l testLogger) Info(...interface{})        {}
+func (l testLogger) Fatal(...interface{})       {}
+
+func newTestProductService() (*ProductService, *testUnitOfWork, *testBlobStorage, uuid.UUID) {
+	productID := uuid.New()
+	ufw := &testUnitOfWork{repo: &testProductRepository{products: map[uuid.UUID]domain.Product{productID: {ID: productID}}}}
+	blobs := &testBlobStorage{blobs: make(map[string][]byte)}
+	return NewProductService(ufw, blobs, testLogger{}), ufw, blobs, productID
+}
+
+func newTestImage(t *testing.T) []byte {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProductService_AddImageStoreFailedDeletesBlobs(t *testing.T) {
+	s, ufw, blobs, productID := newTestProductService()
+	ufw.repo.storeErr = errors.New("store failed")
+
+	_, err := s.AddImage(productID, "image/png", newTestImage(t))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(blobs.blobs) != 0 {
+		t.Errorf("expected image blobs to be deleted, got %d", len(blobs.blobs))
+	}
+}
+
+func TestProductService_AddImageCommitFailedDeletesBlobs(t *testing.T) {
+	s, ufw, blobs, productID := newTestProductService()
+	ufw.commitErr = errors.New("commit failed")
+
+	_, err := s.AddImage(productID, "image/png", newTestImage(t))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(blobs.blobs) != 0 {
+		t.Errorf("expected image blobs to be deleted, got %d", len(blobs.blobs))
+	}
+}
+
+func TestProductService_AddImageAppliedCommitKeepsBlobs(t *testing.T) {
+	s, ufw, blobs, productID := newTestProductService()
+	ufw.commitErr = errors.New("connection lost after commit")
+	ufw.commitApplied = true
+
+	imageID, err := s.AddImage(productID, "image/png", newTestImage(t))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	product := ufw.repo.products[productID]
+	if len(product.Images) != 1 || product.Images[0].ID != imageID {
+		t.Fatalf("expected image to be stored, got %+v", product.Images)
+	}
+	for _, key := range product.Images[0].Keys(productID) {
+		if _, ok := blobs.blobs[key]; !ok {
+			t.Errorf("expected image blob %q to be kept", key)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"bytes"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+const (
+	maxImagePixels = 4096 * 4096
+	jpegQuality    = 85
+)
+
+var thumbnailMaxDimensions = map[domain.ImageSize]int{
+	domain.ImageSizeLarge:  1200,
+	domain.ImageSizeMedium: 480,
+	domain.ImageSizeSmall:  160,
+}
+
+func getImageThumbnails(contentType string, data []byte) (map[domain.ImageSize][]byte, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || "image/"+format != contentType || config.Width*config.Height > maxImagePixels {
+		return nil, ErrInvalidImage
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	// sizes go from the largest one, so every thumbnail is downscaled from the previous
+	result := make(map[domain.ImageSize][]byte, len(domain.ImageThumbnailSizes))
+	for _, size := range domain.ImageThumbnailSizes {
+		img = resizeImage(img, thumbnailMaxDimensions[size])
+		result[size], err = encodeImage(contentType, img)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func encodeImage(contentType string, img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	return buf.Bytes(), err
+}
+
+func resizeImage(src image.Image, maxDimension int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxDimension && height <= maxDimension {
+		return src
+	}
+
+	dstWidth, dstHeight := maxDimension, maxDimension
+	if width > height {
+		dstHeight = height * maxDimension / width
+	} else {
+		dstWidth = width * maxDimension / height
+	}
+	if dstWidth == 0 {
+		dstWidth = 1
+	}
+	if dstHeight == 0 {
+		dstHeight = 1
+	}
+
+	rgba, ok := src.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+
+	// box filter: every destination pixel is the average of the source pixels it covers
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		srcY0, srcY1 := y*height/dstHeight, (y+1)*height/dstHeight
+		for x := 0; x < dstWidth; x++ {
+			srcX0, srcX1 := x*width/dstWidth, (x+1)*width/dstWidth
+
+			var sum [4]int
+			for srcY := srcY0; srcY < srcY1; srcY++ {
+				offset := srcY*rgba.Stride + srcX0*4
+				for srcX := srcX0; srcX < srcX1; srcX++ {
+					for c := range sum {
+						sum[c] += int(rgba.Pix[offset+c])
+					}
+					offset += 4
+				}
+			}
+
+			count := (srcY1 - srcY0) * (srcX1 - srcX0)
+			offset := y*dst.Stride + x*4
+			for c := range sum {
+				dst.Pix[offset+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+type Blob struct {
+	io.ReadCloser
+	ContentType string
+}
+
+type BlobStorage interface {
+	Put(key, contentType string, data []byte) error
+	Get(key string) (*Blob, error)
+	Delete(key string) error
+	PublicURL(key string) string
+}
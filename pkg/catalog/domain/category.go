@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"regexp"
+)
+
+var (
+	ErrCategoryNotExists = errors.New("category is not exists")
+
+	slugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+type Category struct {
+	ID       uuid.UUID
+	ParentID uuid.UUID
+	Slug     string
+	Title    string
+	Position int
+}
+
+func IsValidSlug(slug string) bool {
+	return slugRegexp.MatchString(slug)
+}
+
+type CategoryRepository interface {
+	NextID() uuid.UUID
+	GetByID(id uuid.UUID) (*Category, error)
+	GetBySlug(slug string) (*Category, error)
+	GetAll() ([]Category, error)
+	Store(category *Category) error
+	Delete(id uuid.UUID) error
+}
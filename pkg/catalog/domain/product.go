@@ -2,14 +2,91 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 )
 
+type ImageSize string
+
+const (
+	ImageSizeOriginal ImageSize = "original"
+	ImageSizeLarge    ImageSize = "large"
+	ImageSizeMedium   ImageSize = "medium"
+	ImageSizeSmall    ImageSize = "small"
+)
+
+var ImageThumbnailSizes = []ImageSize{ImageSizeLarge, ImageSizeMedium, ImageSizeSmall}
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+func IsSupportedImageType(contentType string) bool {
+	_, ok := imageExtensions[contentType]
+	return ok
+}
+
+type ProductImage struct {
+	ID          uuid.UUID
+	ContentType string
+}
+
+func (i ProductImage) Key(productID uuid.UUID, size ImageSize) string {
+	return fmt.Sprintf("%v/%v/%s%s", productID, i.ID, size, imageExtensions[i.ContentType])
+}
+
+func (i ProductImage) Keys(productID uuid.UUID) []string {
+	result := []string{i.Key(productID, ImageSizeOriginal)}
+	for _, size := range ImageThumbnailSizes {
+		result = append(result, i.Key(productID, size))
+	}
+	return result
+}
+
+type ProductOption struct {
+	Name   string
+	Values []string
+}
+
+type ProductVariant struct {
+	ID              uuid.UUID
+	SKU             string
+	OptionValues    []string
+	Price           int
+	WarehouseItemID uuid.UUID
+}
+
 type Product struct {
 	ID          uuid.UUID
 	Title       string
 	Description string
 	Price       int
+	Weight      int
+	MaxQuantity int
+	CategoryIDs []uuid.UUID
+	Options     []ProductOption
+	Variants    []ProductVariant
+	Images      []ProductImage
+}
+
+func (p *Product) GetVariant(id uuid.UUID) (*ProductVariant, bool) {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+func (p *Product) RemoveImage(id uuid.UUID) (ProductImage, bool) {
+	for i, image := range p.Images {
+		if image.ID == id {
+			p.Images = append(p.Images[:i], p.Images[i+1:]...)
+			return image, true
+		}
+	}
+	return ProductImage{}, false
 }
 
 var (
@@ -19,5 +96,7 @@ var (
 type ProductRepository interface {
 	NextID() uuid.UUID
 	GetByID(id uuid.UUID) (*Product, error)
+	GetIDBySKU(sku string) (uuid.UUID, error)
 	Store(product *Product) error
+	SetItemInStock(itemID uuid.UUID, inStock bool) (changedProductIDs []uuid.UUID, err error)
 }
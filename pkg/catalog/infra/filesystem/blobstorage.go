@@ -0,0 +1,77 @@
+package filesystem
+
+import (
+	"errors"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/storage"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type blobStorage struct {
+	rootDir   string
+	publicURL string
+}
+
+func (s *blobStorage) Put(key, _ string, data []byte) error {
+	filePath := s.getFilePath(key)
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filePath)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
+
+func (s *blobStorage) Get(key string) (*storage.Blob, error) {
+	file, err := os.Open(s.getFilePath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, storage.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &storage.Blob{ReadCloser: file, ContentType: contentType}, nil
+}
+
+func (s *blobStorage) Delete(key string) error {
+	err := os.Remove(s.getFilePath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *blobStorage) PublicURL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+func (s *blobStorage) getFilePath(key string) string {
+	// cleaning the key as an absolute path keeps it inside the root directory
+	return filepath.Join(s.rootDir, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func NewBlobStorage(rootDir, publicURL string) storage.BlobStorage {
+	return &blobStorage{rootDir: rootDir, publicURL: strings.TrimSuffix(publicURL, "/")}
+}
@@ -0,0 +1,61 @@
+package filesystem
+
+import (
+	"errors"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/storage"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlobStorage_PutGetDelete(t *testing.T) {
+	blobs := NewBlobStorage(t.TempDir(), "http://example.com/images/")
+
+	err := blobs.Put("product/image/original.png", "image/png", []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blob, err := blobs.Get("product/image/original.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(blob)
+	_ = blob.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "data" || blob.ContentType != "image/png" {
+		t.Errorf("unexpected blob %q with content type %q", data, blob.ContentType)
+	}
+	if url := blobs.PublicURL("product/image/original.png"); url != "http://example.com/images/product/image/original.png" {
+		t.Errorf("unexpected public url %q", url)
+	}
+
+	err = blobs.Delete("product/image/original.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = blobs.Get("product/image/original.png")
+	if !errors.Is(err, storage.ErrBlobNotFound) {
+		t.Errorf("expected deleted blob to be not found, got %v", err)
+	}
+}
+
+func TestBlobStorage_KeyStaysInsideRootDir(t *testing.T) {
+	dir := t.TempDir()
+	rootDir := filepath.Join(dir, "root")
+	blobs := NewBlobStorage(rootDir, "")
+
+	err := blobs.Put("../outside.jpg", "image/jpeg", []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "outside.jpg")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("blob is written outside of the root directory")
+	}
+	if _, err = os.Stat(filepath.Join(rootDir, "outside.jpg")); err != nil {
+		t.Errorf("expected blob inside the root directory: %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package memory
+
+import (
+	"bytes"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/query"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+type ProductSearchIndex struct {
+	mutex    sync.RWMutex
+	products map[uuid.UUID]query.ProductData
+}
+
+type scoredProduct struct {
+	query.ProductData
+	score float64
+}
+
+func (i *ProductSearchIndex) Index(product query.ProductData) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.products[product.ID] = product
+}
+
+func (i *ProductSearchIndex) Remove(productID uuid.UUID) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	delete(i.products, productID)
+}
+
+func (i *ProductSearchIndex) Search(params query.ProductSearchParams) (*query.ProductSearchResult, error) {
+	searchSort, err := params.EffectiveSort()
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := query.DecodeProductSearchCursor(params.Cursor, searchSort)
+	if err != nil {
+		return nil, err
+	}
+	var cursorScore float64
+	var cursorPrice int
+	if cursor != nil && searchSort == query.ProductSearchSortRelevance {
+		cursorScore, err = strconv.ParseFloat(cursor.Key, 64)
+	}
+	if cursor != nil && (searchSort == query.ProductSearchSortPriceAsc || searchSort == query.ProductSearchSortPriceDesc) {
+		cursorPrice, err = strconv.Atoi(cursor.Key)
+	}
+	if err != nil {
+		return nil, query.ErrInvalidSearchCursor
+	}
+
+	matched := i.match(params)
+	less := func(a, b scoredProduct) bool {
+		switch searchSort {
+		case query.ProductSearchSortRelevance:
+			if a.score != b.score {
+				return a.score > b.score
+			}
+		case query.ProductSearchSortPriceAsc:
+			if a.Price != b.Price {
+				return a.Price < b.Price
+			}
+		case query.ProductSearchSortPriceDesc:
+			if a.Price != b.Price {
+				return a.Price > b.Price
+			}
+		default:
+			if titleA, titleB := strings.ToLower(a.Title), strings.ToLower(b.Title); titleA != titleB {
+				return titleA < titleB
+			}
+		}
+		return bytes.Compare(a.ID[:], b.ID[:]) < 0
+	}
+	sort.Slice(matched, func(a, b int) bool {
+		return less(matched[a], matched[b])
+	})
+
+	start := 0
+	if cursor != nil {
+		after := scoredProduct{
+			ProductData: query.ProductData{ID: cursor.ID, Title: cursor.Key, Price: cursorPrice},
+			score:       cursorScore,
+		}
+		start = sort.Search(len(matched), func(n int) bool {
+			return less(after, matched[n])
+		})
+	}
+
+	result := &query.ProductSearchResult{
+		Total:  len(matched),
+		Facets: getFacets(matched),
+	}
+	end := start + params.Limit
+	if end >= len(matched) {
+		end = len(matched)
+	} else {
+		last := matched[end-1]
+		result.NextCursor = query.EncodeProductSearchCursor(query.ProductSearchCursor{
+			Sort: searchSort,
+			Key:  getCursorKey(searchSort, last),
+			ID:   last.ID,
+		})
+	}
+
+	result.Products = make([]query.ProductData, 0, end-start)
+	for _, product := range matched[start:end] {
+		result.Products = append(result.Products, product.ProductData)
+	}
+	return result, nil
+}
+
+func (i *ProductSearchIndex) match(params query.ProductSearchParams) []scoredProduct {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	terms := tokenize(params.Text)
+	categoryIDs := make(map[uuid.UUID]bool, len(params.CategoryIDs))
+	for _, categoryID := range params.CategoryIDs {
+		categoryIDs[categoryID] = true
+	}
+
+	result := make([]scoredProduct, 0, len(i.products))
+	for _, product := range i.products {
+		if params.MinPrice > 0 && product.Price < params.MinPrice {
+			continue
+		}
+		if params.MaxPrice > 0 && product.Price > params.MaxPrice {
+			continue
+		}
+		if params.InStockOnly && !product.InStock {
+			continue
+		}
+		if len(categoryIDs) > 0 && !hasAnyCategory(product.CategoryIDs, categoryIDs) {
+			continue
+		}
+
+		score := getScore(terms, product)
+		if len(terms) > 0 && score == 0 {
+			continue
+		}
+		result = append(result, scoredProduct{ProductData: product, score: score})
+	}
+	return result
+}
+
+func getScore(terms []string, product query.ProductData) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	const titleWeight = 2
+	counts := make(map[string]float64)
+	for _, word := range tokenize(product.Title) {
+		counts[word] += titleWeight
+	}
+	for _, word := range tokenize(product.Description) {
+		counts[word]++
+	}
+
+	var score float64
+	for _, term := range terms {
+		score += counts[term]
+	}
+	return score
+}
+
+func getFacets(products []scoredProduct) query.ProductSearchFacets {
+	facets := query.ProductSearchFacets{
+		PriceRanges: make([]query.PriceRangeFacet, 0, len(query.ProductSearchPriceRanges)),
+	}
+	for _, priceRange := range query.ProductSearchPriceRanges {
+		facets.PriceRanges = append(facets.PriceRanges, query.PriceRangeFacet{PriceRange: priceRange})
+	}
+
+	categoryCounts := make(map[uuid.UUID]int)
+	for _, product := range products {
+		if product.InStock {
+			facets.InStock++
+		}
+		for n := range facets.PriceRanges {
+			if facets.PriceRanges[n].Contains(product.Price) {
+				facets.PriceRanges[n].Count++
+				break
+			}
+		}
+		for _, categoryID := range product.CategoryIDs {
+			categoryCounts[categoryID]++
+		}
+	}
+
+	facets.Categories = make([]query.CategoryFacet, 0, len(categoryCounts))
+	for categoryID, count := range categoryCounts {
+		facets.Categories = append(facets.Categories, query.CategoryFacet{CategoryID: categoryID, Count: count})
+	}
+	sort.Slice(facets.Categories, func(a, b int) bool {
+		if facets.Categories[a].Count != facets.Categories[b].Count {
+			return facets.Categories[a].Count > facets.Categories[b].Count
+		}
+		return bytes.Compare(facets.Categories[a].CategoryID[:], facets.Categories[b].CategoryID[:]) < 0
+	})
+	return facets
+}
+
+func hasAnyCategory(productCategoryIDs []uuid.UUID, categoryIDs map[uuid.UUID]bool) bool {
+	for _, categoryID := range productCategoryIDs {
+		if categoryIDs[categoryID] {
+			return true
+		}
+	}
+	return false
+}
+
+func getCursorKey(searchSort query.ProductSearchSort, product scoredProduct) string {
+	switch searchSort {
+	case query.ProductSearchSortRelevance:
+		return strconv.FormatFloat(product.score, 'g', -1, 64)
+	case query.ProductSearchSortPriceAsc, query.ProductSearchSortPriceDesc:
+		return strconv.Itoa(product.Price)
+	default:
+		return product.Title
+	}
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func NewProductSearchIndex() *ProductSearchIndex {
+	return &ProductSearchIndex{products: make(map[uuid.UUID]query.ProductData)}
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/query"
+	"testing"
+)
+
+func newTestIndex() (*ProductSearchIndex, uuid.UUID) {
+	phones := uuid.New()
+	index := NewProductSearchIndex()
+	index.Index(query.ProductData{ID: uuid.New(), Title: "Red phone", Description: "Phone case included", Price: 3000, CategoryIDs: []uuid.UUID{phones}, InStock: true})
+	index.Index(query.ProductData{ID: uuid.New(), Title: "Blue phone", Description: "Compact", Price: 7000, CategoryIDs: []uuid.UUID{phones}})
+	index.Index(query.ProductData{ID: uuid.New(), Title: "Phone holder", Description: "Car mount", Price: 500, InStock: true})
+	index.Index(query.ProductData{ID: uuid.New(), Title: "Kettle", Description: "Steel", Price: 2000, InStock: true})
+	return index, phones
+}
+
+func TestProductSearchIndex_TextSearchOrdersByRelevance(t *testing.T) {
+	index, _ := newTestIndex()
+
+	result, err := index.Search(query.ProductSearchParams{Text: "phone case", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 3 {
+		t.Fatalf("expected 3 matches, got %d", result.Total)
+	}
+	if result.Products[0].Title != "Red phone" {
+		t.Errorf("expected most relevant product first, got %q", result.Products[0].Title)
+	}
+}
+
+func TestProductSearchIndex_Filters(t *testing.T) {
+	index, phones := newTestIndex()
+
+	result, err := index.Search(query.ProductSearchParams{
+		MinPrice:    1000,
+		MaxPrice:    5000,
+		CategoryIDs: []uuid.UUID{phones},
+		InStockOnly: true,
+		Limit:       10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 1 || result.Products[0].Title != "Red phone" {
+		t.Fatalf("unexpected filter result: %+v", result.Products)
+	}
+}
+
+func TestProductSearchIndex_CursorPagination(t *testing.T) {
+	index, _ := newTestIndex()
+
+	var prices []int
+	params := query.ProductSearchParams{Sort: query.ProductSearchSortPriceAsc, Limit: 3}
+	for page := 0; ; page++ {
+		result, err := index.Search(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, product := range result.Products {
+			prices = append(prices, product.Price)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		if page > 2 {
+			t.Fatal("pagination does not terminate")
+		}
+		params.Cursor = result.NextCursor
+	}
+
+	expected := []int{500, 2000, 3000, 7000}
+	if len(prices) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, prices)
+	}
+	for n := range expected {
+		if prices[n] != expected[n] {
+			t.Fatalf("expected %v, got %v", expected, prices)
+		}
+	}
+
+	params.Sort = query.ProductSearchSortTitle
+	_, err := index.Search(params)
+	if !errors.Is(err, query.ErrInvalidSearchCursor) {
+		t.Errorf("expected cursor of another sort to be rejected, got %v", err)
+	}
+}
+
+func TestProductSearchIndex_Facets(t *testing.T) {
+	index, phones := newTestIndex()
+
+	result, err := index.Search(query.ProductSearchParams{Text: "phone", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Facets.InStock != 2 {
+		t.Errorf("expected 2 products in stock, got %d", result.Facets.InStock)
+	}
+	if len(result.Facets.Categories) != 1 || result.Facets.Categories[0].CategoryID != phones || result.Facets.Categories[0].Count != 2 {
+		t.Errorf("unexpected category facets: %+v", result.Facets.Categories)
+	}
+
+	counts := make([]int, 0, len(result.Facets.PriceRanges))
+	for _, priceRange := range result.Facets.PriceRanges {
+		counts = append(counts, priceRange.Count)
+	}
+	expected := []int{1, 1, 1, 0, 0}
+	for n := range expected {
+		if counts[n] != expected[n] {
+			t.Fatalf("expected price range counts %v, got %v", expected, counts)
+		}
+	}
+}
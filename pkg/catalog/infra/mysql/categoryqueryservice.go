@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/query"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/storage"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+)
+
+type categoryQueryService struct {
+	client mysql.Client
+	images storage.BlobStorage
+}
+
+func (s *categoryQueryService) ListAll() ([]query.CategoryData, error) {
+	categories, err := NewCategoryRepository(s.client).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]query.CategoryData, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, query.CategoryData{
+			ID:       category.ID,
+			ParentID: category.ParentID,
+			Slug:     category.Slug,
+			Title:    category.Title,
+			Position: category.Position,
+		})
+	}
+	return result, nil
+}
+
+func (s *categoryQueryService) GetSubtreeIDsBySlug(slug string) ([]uuid.UUID, error) {
+	categories, err := s.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var rootID uuid.UUID
+	childIDs := make(map[uuid.UUID][]uuid.UUID, len(categories))
+	for _, category := range categories {
+		if category.Slug == slug {
+			rootID = category.ID
+		}
+		childIDs[category.ParentID] = append(childIDs[category.ParentID], category.ID)
+	}
+	if rootID == uuid.Nil {
+		return nil, query.ErrCategoryBySlugNotFound
+	}
+
+	categoryIDs := []uuid.UUID{rootID}
+	for i := 0; i < len(categoryIDs); i++ {
+		categoryIDs = append(categoryIDs, childIDs[categoryIDs[i]]...)
+	}
+	return categoryIDs, nil
+}
+
+func (s *categoryQueryService) GetProductsBySlug(slug string) ([]query.ProductData, error) {
+	categoryIDs, err := s.GetSubtreeIDsBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	binaryCategoryIDs, err := marshalUUIDs(categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	selectQuery, args, err := sqlx.In(`
+		SELECT `+productFields+`
+		FROM product
+		WHERE id IN (SELECT product_id FROM product_category WHERE category_id IN (?))
+		ORDER BY title
+	`, binaryCategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var productsSqlx []sqlxProduct
+	err = s.client.Select(&productsSqlx, selectQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return getProductsData(s.client, s.images, productsSqlx)
+}
+
+func NewCategoryQueryService(client mysql.Client, images storage.BlobStorage) query.CategoryService {
+	return &categoryQueryService{client: client, images: images}
+}
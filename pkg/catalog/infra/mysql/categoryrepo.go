@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+)
+
+const categoryFields = `id, parent_id, slug, title, position`
+
+type categoryRepo struct {
+	client mysql.Client
+}
+
+func (r *categoryRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *categoryRepo) GetByID(id uuid.UUID) (*domain.Category, error) {
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return r.getCategory(`SELECT `+categoryFields+` FROM category WHERE id = ?`, binaryID)
+}
+
+func (r *categoryRepo) GetBySlug(slug string) (*domain.Category, error) {
+	return r.getCategory(`SELECT `+categoryFields+` FROM category WHERE slug = ?`, slug)
+}
+
+func (r *categoryRepo) GetAll() ([]domain.Category, error) {
+	var categoriesSqlx []sqlxCategory
+	err := r.client.Select(&categoriesSqlx, `SELECT `+categoryFields+` FROM category ORDER BY position, title`)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Category, 0, len(categoriesSqlx))
+	for _, categorySqlx := range categoriesSqlx {
+		result = append(result, categorySqlx.toDomain())
+	}
+	return result, nil
+}
+
+func (r *categoryRepo) Store(category *domain.Category) error {
+	const query = `
+		INSERT INTO category (id, parent_id, slug, title, position, created_at)
+		VALUES (?, ?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			parent_id = VALUES(parent_id), slug = VALUES(slug), title = VALUES(title), position = VALUES(position),
+			updated_at = NOW()
+	`
+
+	binaryID, err := category.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	binaryParentID, err := marshalNullableUUID(category.ParentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(query, binaryID, binaryParentID, category.Slug, category.Title, category.Position)
+	return err
+}
+
+func (r *categoryRepo) Delete(id uuid.UUID) error {
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`DELETE FROM category WHERE id = ?`, binaryID)
+	return err
+}
+
+func (r *categoryRepo) getCategory(query string, args ...any) (*domain.Category, error) {
+	var categorySqlx sqlxCategory
+	err := r.client.Get(&categorySqlx, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCategoryNotExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	category := categorySqlx.toDomain()
+	return &category, nil
+}
+
+func marshalNullableUUID(id uuid.UUID) ([]byte, error) {
+	if id == uuid.Nil {
+		return nil, nil
+	}
+	return id.MarshalBinary()
+}
+
+func NewCategoryRepository(client mysql.Client) domain.CategoryRepository {
+	return &categoryRepo{client: client}
+}
+
+type sqlxCategory struct {
+	ID       uuid.UUID     `db:"id"`
+	ParentID uuid.NullUUID `db:"parent_id"`
+	Slug     string        `db:"slug"`
+	Title    string        `db:"title"`
+	Position int           `db:"position"`
+}
+
+func (c *sqlxCategory) toDomain() domain.Category {
+	return domain.Category{
+		ID:       c.ID,
+		ParentID: c.ParentID.UUID,
+		Slug:     c.Slug,
+		Title:    c.Title,
+		Position: c.Position,
+	}
+}
@@ -4,15 +4,20 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/query"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/storage"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
 )
 
+const productFields = `id, title, description, price, weight, max_quantity, in_stock`
+
 type productQueryService struct {
 	client mysql.Client
+	images storage.BlobStorage
 }
 
 func (s *productQueryService) ListAll() ([]query.ProductData, error) {
-	const selectQuery = `SELECT id, title, description, price FROM product`
+	const selectQuery = `SELECT ` + productFields + ` FROM product`
 
 	var productsSqlx []sqlxProduct
 	err := s.client.Select(&productsSqlx, selectQuery)
@@ -20,17 +25,7 @@ func (s *productQueryService) ListAll() ([]query.ProductData, error) {
 		return nil, err
 	}
 
-	result := make([]query.ProductData, 0, len(productsSqlx))
-	for _, item := range productsSqlx {
-		result = append(result, query.ProductData{
-			ID:          item.ID,
-			Title:       item.Title,
-			Description: item.Description,
-			Price:       item.Price,
-		})
-	}
-
-	return result, nil
+	return getProductsData(s.client, s.images, productsSqlx)
 }
 
 func (s *productQueryService) GetByIDs(ids []uuid.UUID) ([]query.ProductData, error) {
@@ -38,16 +33,12 @@ func (s *productQueryService) GetByIDs(ids []uuid.UUID) ([]query.ProductData, er
 		return nil, nil
 	}
 
-	binaryIDs := make([][]byte, 0, len(ids))
-	for _, id := range ids {
-		binaryID, err := id.MarshalBinary()
-		if err != nil {
-			return nil, err
-		}
-		binaryIDs = append(binaryIDs, binaryID)
+	binaryIDs, err := marshalUUIDs(ids)
+	if err != nil {
+		return nil, err
 	}
 
-	selectQuery, args, err := sqlx.In(`SELECT id, title, description, price FROM product WHERE id IN (?)`, binaryIDs)
+	selectQuery, args, err := sqlx.In(`SELECT `+productFields+` FROM product WHERE id IN (?)`, binaryIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -62,19 +53,175 @@ func (s *productQueryService) GetByIDs(ids []uuid.UUID) ([]query.ProductData, er
 		return nil, query.ErrProductByIDNotFound
 	}
 
+	return getProductsData(s.client, s.images, productsSqlx)
+}
+
+func getProductsData(client mysql.Client, images storage.BlobStorage, productsSqlx []sqlxProduct) ([]query.ProductData, error) {
+	if len(productsSqlx) == 0 {
+		return []query.ProductData{}, nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(productsSqlx))
+	for _, item := range productsSqlx {
+		productIDs = append(productIDs, item.ID)
+	}
+	binaryIDs, err := marshalUUIDs(productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	categoriesQuery, args, err := sqlx.In(`SELECT product_id, category_id FROM product_category WHERE product_id IN (?)`, binaryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var productCategories []struct {
+		ProductID  uuid.UUID `db:"product_id"`
+		CategoryID uuid.UUID `db:"category_id"`
+	}
+	err = client.Select(&productCategories, categoriesQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := make(map[uuid.UUID][]uuid.UUID)
+	for _, productCategory := range productCategories {
+		categoryIDs[productCategory.ProductID] = append(categoryIDs[productCategory.ProductID], productCategory.CategoryID)
+	}
+
+	options, err := getProductsOptions(client, binaryIDs)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := getProductsVariants(client, binaryIDs)
+	if err != nil {
+		return nil, err
+	}
+	productImages, err := getProductsImages(client, images, binaryIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]query.ProductData, 0, len(productsSqlx))
 	for _, item := range productsSqlx {
+		for i := range variants[item.ID] {
+			if variants[item.ID][i].Price == 0 {
+				variants[item.ID][i].Price = item.Price
+			}
+		}
 		result = append(result, query.ProductData{
 			ID:          item.ID,
 			Title:       item.Title,
 			Description: item.Description,
 			Price:       item.Price,
+			Weight:      item.Weight,
+			MaxQuantity: item.MaxQuantity,
+			CategoryIDs: categoryIDs[item.ID],
+			InStock:     item.InStock,
+			Options:     options[item.ID],
+			Variants:    variants[item.ID],
+			Images:      productImages[item.ID],
 		})
 	}
+	return result, nil
+}
 
+func getProductsOptions(client mysql.Client, binaryProductIDs [][]byte) (map[uuid.UUID][]query.ProductOptionData, error) {
+	optionsQuery, args, err := sqlx.In(`SELECT product_id, name, option_values FROM product_option WHERE product_id IN (?) ORDER BY position`, binaryProductIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var optionsSqlx []sqlxProductOption
+	err = client.Select(&optionsSqlx, optionsQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID][]query.ProductOptionData)
+	for _, optionSqlx := range optionsSqlx {
+		values, err := decodeOptionValues(optionSqlx.Values)
+		if err != nil {
+			return nil, err
+		}
+		result[optionSqlx.ProductID] = append(result[optionSqlx.ProductID], query.ProductOptionData{
+			Name:   optionSqlx.Name,
+			Values: values,
+		})
+	}
+	return result, nil
+}
+
+func getProductsVariants(client mysql.Client, binaryProductIDs [][]byte) (map[uuid.UUID][]query.ProductVariantData, error) {
+	variantsQuery, args, err := sqlx.In(`SELECT `+variantFields+` FROM product_variant WHERE product_id IN (?) ORDER BY position`, binaryProductIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var variantsSqlx []sqlxProductVariant
+	err = client.Select(&variantsSqlx, variantsQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID][]query.ProductVariantData)
+	for _, variantSqlx := range variantsSqlx {
+		values, err := decodeOptionValues(variantSqlx.OptionValues)
+		if err != nil {
+			return nil, err
+		}
+		result[variantSqlx.ProductID] = append(result[variantSqlx.ProductID], query.ProductVariantData{
+			ID:              variantSqlx.ID,
+			SKU:             variantSqlx.SKU,
+			OptionValues:    values,
+			Price:           variantSqlx.Price,
+			WarehouseItemID: variantSqlx.WarehouseItemID,
+			InStock:         variantSqlx.InStock,
+		})
+	}
+	return result, nil
+}
+
+func getProductsImages(client mysql.Client, images storage.BlobStorage, binaryProductIDs [][]byte) (map[uuid.UUID][]query.ProductImageData, error) {
+	imagesQuery, args, err := sqlx.In(`SELECT id, product_id, content_type FROM product_image WHERE product_id IN (?) ORDER BY position`, binaryProductIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var imagesSqlx []sqlxProductImage
+	err = client.Select(&imagesSqlx, imagesQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID][]query.ProductImageData)
+	for _, imageSqlx := range imagesSqlx {
+		image := domain.ProductImage{ID: imageSqlx.ID, ContentType: imageSqlx.ContentType}
+		thumbnailURLs := make(map[string]string, len(domain.ImageThumbnailSizes))
+		for _, size := range domain.ImageThumbnailSizes {
+			thumbnailURLs[string(size)] = images.PublicURL(image.Key(imageSqlx.ProductID, size))
+		}
+		result[imageSqlx.ProductID] = append(result[imageSqlx.ProductID], query.ProductImageData{
+			ID:            image.ID,
+			URL:           images.PublicURL(image.Key(imageSqlx.ProductID, domain.ImageSizeOriginal)),
+			ThumbnailURLs: thumbnailURLs,
+		})
+	}
+	return result, nil
+}
+
+func marshalUUIDs(ids []uuid.UUID) ([][]byte, error) {
+	result := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		binaryID, err := id.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, binaryID)
+	}
 	return result, nil
 }
 
-func NewProductQueryService(client mysql.Client) query.ProductService {
-	return &productQueryService{client: client}
+func NewProductQueryService(client mysql.Client, images storage.BlobStorage) query.ProductService {
+	return &productQueryService{client: client, images: images}
 }
@@ -2,10 +2,14 @@ package mysql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"strings"
 )
 
 type productRepo struct {
@@ -17,7 +21,7 @@ func (r *productRepo) NextID() uuid.UUID {
 }
 
 func (r *productRepo) GetByID(id uuid.UUID) (*domain.Product, error) {
-	const query = `SELECT id, title, description, price FROM product WHERE id = ?`
+	const query = `SELECT id, title, description, price, weight, max_quantity FROM product WHERE id = ?`
 
 	binaryID, err := id.MarshalBinary()
 	if err != nil {
@@ -33,20 +37,55 @@ func (r *productRepo) GetByID(id uuid.UUID) (*domain.Product, error) {
 		return nil, err
 	}
 
+	var categoryIDs []uuid.UUID
+	err = r.client.Select(&categoryIDs, `SELECT category_id FROM product_category WHERE product_id = ?`, binaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := r.getOptions(binaryID)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := r.getVariants(binaryID)
+	if err != nil {
+		return nil, err
+	}
+	images, err := r.getImages(binaryID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &domain.Product{
 		ID:          productSqlx.ID,
 		Title:       productSqlx.Title,
 		Description: productSqlx.Description,
 		Price:       productSqlx.Price,
+		Weight:      productSqlx.Weight,
+		MaxQuantity: productSqlx.MaxQuantity,
+		CategoryIDs: categoryIDs,
+		Options:     options,
+		Variants:    variants,
+		Images:      images,
 	}, nil
 }
 
+func (r *productRepo) GetIDBySKU(sku string) (uuid.UUID, error) {
+	var productID uuid.UUID
+	err := r.client.Get(&productID, `SELECT product_id FROM product_variant WHERE sku = ?`, sku)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, domain.ErrProductNotExists
+	}
+	return productID, err
+}
+
 func (r *productRepo) Store(product *domain.Product) error {
 	const query = `
-		INSERT INTO product (id, title, description, price, created_at)
-		VALUES (?, ?, ?, ?, NOW())
+		INSERT INTO product (id, title, description, price, weight, max_quantity, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, NOW())
 		ON DUPLICATE KEY UPDATE
-			title = VALUES(title), description = VALUES(description), price = VALUES(price), updated_at = NOW()
+			title = VALUES(title), description = VALUES(description), price = VALUES(price), weight = VALUES(weight),
+			max_quantity = VALUES(max_quantity), updated_at = NOW()
 	`
 
 	binaryID, err := product.ID.MarshalBinary()
@@ -54,7 +93,277 @@ func (r *productRepo) Store(product *domain.Product) error {
 		return err
 	}
 
-	_, err = r.client.Exec(query, binaryID, product.Title, product.Description, product.Price)
+	_, err = r.client.Exec(query, binaryID, product.Title, product.Description, product.Price, product.Weight, product.MaxQuantity)
+	if err != nil {
+		return err
+	}
+
+	err = r.storeCategories(binaryID, product.CategoryIDs)
+	if err != nil {
+		return err
+	}
+	err = r.storeOptions(binaryID, product.Options)
+	if err != nil {
+		return err
+	}
+	err = r.storeVariants(binaryID, product.Variants)
+	if err != nil {
+		return err
+	}
+	return r.storeImages(binaryID, product.Images)
+}
+
+func (r *productRepo) SetItemInStock(itemID uuid.UUID, inStock bool) ([]uuid.UUID, error) {
+	binaryItemID, err := itemID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var productIDs []uuid.UUID
+	err = r.client.Select(&productIDs, `
+		SELECT id FROM product
+		WHERE in_stock <> ? AND (
+			(id = ? AND NOT EXISTS (SELECT 1 FROM product_variant WHERE product_id = product.id)) OR
+			id IN (SELECT product_id FROM product_variant WHERE warehouse_item_id = ?)
+		)
+		FOR UPDATE
+	`, inStock, binaryItemID, binaryItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.updateItemInStock(binaryItemID, inStock)
+	if err != nil || len(productIDs) == 0 {
+		return nil, err
+	}
+
+	binaryProductIDs := make([]any, 0, len(productIDs))
+	for _, productID := range productIDs {
+		binaryProductID, err := productID.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		binaryProductIDs = append(binaryProductIDs, binaryProductID)
+	}
+
+	query, args, err := sqlx.In(`SELECT id FROM product WHERE id IN (?) AND in_stock = ?`, binaryProductIDs, inStock)
+	if err != nil {
+		return nil, err
+	}
+
+	var changedProductIDs []uuid.UUID
+	err = r.client.Select(&changedProductIDs, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return changedProductIDs, nil
+}
+
+func (r *productRepo) updateItemInStock(binaryItemID []byte, inStock bool) error {
+	_, err := r.client.Exec(`UPDATE product_variant SET in_stock = ? WHERE warehouse_item_id = ?`, inStock, binaryItemID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`
+		UPDATE product SET in_stock = ?
+		WHERE id = ? AND NOT EXISTS (SELECT 1 FROM product_variant WHERE product_id = product.id)
+	`, inStock, binaryItemID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`
+		UPDATE product
+		SET in_stock = EXISTS (SELECT 1 FROM product_variant WHERE product_id = product.id AND in_stock = 1)
+		WHERE id IN (SELECT product_id FROM product_variant WHERE warehouse_item_id = ?)
+	`, binaryItemID)
+	return err
+}
+
+func (r *productRepo) getOptions(binaryProductID []byte) ([]domain.ProductOption, error) {
+	var optionsSqlx []sqlxProductOption
+	err := r.client.Select(&optionsSqlx, `SELECT product_id, name, option_values FROM product_option WHERE product_id = ? ORDER BY position`, binaryProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ProductOption, 0, len(optionsSqlx))
+	for _, optionSqlx := range optionsSqlx {
+		values, err := decodeOptionValues(optionSqlx.Values)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, domain.ProductOption{Name: optionSqlx.Name, Values: values})
+	}
+	return result, nil
+}
+
+func (r *productRepo) getVariants(binaryProductID []byte) ([]domain.ProductVariant, error) {
+	var variantsSqlx []sqlxProductVariant
+	err := r.client.Select(&variantsSqlx, `SELECT `+variantFields+` FROM product_variant WHERE product_id = ? ORDER BY position`, binaryProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ProductVariant, 0, len(variantsSqlx))
+	for _, variantSqlx := range variantsSqlx {
+		values, err := decodeOptionValues(variantSqlx.OptionValues)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, domain.ProductVariant{
+			ID:              variantSqlx.ID,
+			SKU:             variantSqlx.SKU,
+			OptionValues:    values,
+			Price:           variantSqlx.Price,
+			WarehouseItemID: variantSqlx.WarehouseItemID,
+		})
+	}
+	return result, nil
+}
+
+func (r *productRepo) getImages(binaryProductID []byte) ([]domain.ProductImage, error) {
+	var imagesSqlx []sqlxProductImage
+	err := r.client.Select(&imagesSqlx, `SELECT id, product_id, content_type FROM product_image WHERE product_id = ? ORDER BY position`, binaryProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ProductImage, 0, len(imagesSqlx))
+	for _, imageSqlx := range imagesSqlx {
+		result = append(result, domain.ProductImage{ID: imageSqlx.ID, ContentType: imageSqlx.ContentType})
+	}
+	return result, nil
+}
+
+func (r *productRepo) storeOptions(binaryProductID []byte, options []domain.ProductOption) error {
+	_, err := r.client.Exec(`DELETE FROM product_option WHERE product_id = ?`, binaryProductID)
+	if err != nil || len(options) == 0 {
+		return err
+	}
+
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO product_option (product_id, position, name, option_values) VALUES %s%s`,
+		"(?, ?, ?, ?)",
+		strings.Repeat(", (?, ?, ?, ?)", len(options)-1),
+	)
+	args := make([]any, 0, len(options)*4) // arguments count
+	for i, option := range options {
+		values, err := json.Marshal(option.Values)
+		if err != nil {
+			return err
+		}
+		args = append(args, binaryProductID, i, option.Name, string(values))
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func (r *productRepo) storeVariants(binaryProductID []byte, variants []domain.ProductVariant) error {
+	const upsertQuery = `
+		INSERT INTO product_variant (id, product_id, position, sku, option_values, price, warehouse_item_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			position = VALUES(position), sku = VALUES(sku), option_values = VALUES(option_values), price = VALUES(price),
+			in_stock = IF(warehouse_item_id = VALUES(warehouse_item_id), in_stock, 0),
+			warehouse_item_id = VALUES(warehouse_item_id)
+	`
+
+	binaryIDs := make([][]byte, 0, len(variants))
+	for _, variant := range variants {
+		binaryID, err := variant.ID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		binaryIDs = append(binaryIDs, binaryID)
+	}
+
+	deleteQuery, args := `DELETE FROM product_variant WHERE product_id = ?`, []any{binaryProductID}
+	if len(binaryIDs) > 0 {
+		var err error
+		deleteQuery, args, err = sqlx.In(deleteQuery+` AND id NOT IN (?)`, binaryProductID, binaryIDs)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := r.client.Exec(deleteQuery, args...)
+	if err != nil {
+		return err
+	}
+
+	for i, variant := range variants {
+		values, err := json.Marshal(variant.OptionValues)
+		if err != nil {
+			return err
+		}
+		binaryItemID, err := variant.WarehouseItemID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_, err = r.client.Exec(upsertQuery, binaryIDs[i], binaryProductID, i, variant.SKU, string(values), variant.Price, binaryItemID)
+		if err != nil {
+			return err
+		}
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+
+	_, err = r.client.Exec(`
+		UPDATE product
+		SET in_stock = EXISTS (SELECT 1 FROM product_variant WHERE product_id = product.id AND in_stock = 1)
+		WHERE id = ?
+	`, binaryProductID)
+	return err
+}
+
+func (r *productRepo) storeImages(binaryProductID []byte, images []domain.ProductImage) error {
+	_, err := r.client.Exec(`DELETE FROM product_image WHERE product_id = ?`, binaryProductID)
+	if err != nil || len(images) == 0 {
+		return err
+	}
+
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO product_image (id, product_id, position, content_type) VALUES %s%s`,
+		"(?, ?, ?, ?)",
+		strings.Repeat(", (?, ?, ?, ?)", len(images)-1),
+	)
+	args := make([]any, 0, len(images)*4) // arguments count
+	for i, image := range images {
+		binaryID, err := image.ID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		args = append(args, binaryID, binaryProductID, i, image.ContentType)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func (r *productRepo) storeCategories(binaryID []byte, categoryIDs []uuid.UUID) error {
+	_, err := r.client.Exec(`DELETE FROM product_category WHERE product_id = ?`, binaryID)
+	if err != nil || len(categoryIDs) == 0 {
+		return err
+	}
+
+	insertQuery := fmt.Sprintf(
+		`INSERT INTO product_category (product_id, category_id) VALUES %s%s`,
+		"(?, ?)",
+		strings.Repeat(", (?, ?)", len(categoryIDs)-1),
+	)
+	args := make([]any, 0, len(categoryIDs)*2) // arguments count
+	for _, categoryID := range categoryIDs {
+		binaryCategoryID, err := categoryID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		args = append(args, binaryID, binaryCategoryID)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
 	return err
 }
 
@@ -67,4 +376,40 @@ type sqlxProduct struct {
 	Title       string    `db:"title"`
 	Description string    `db:"description"`
 	Price       int       `db:"price"`
+	Weight      int       `db:"weight"`
+	MaxQuantity int       `db:"max_quantity"`
+	InStock     bool      `db:"in_stock"`
+}
+
+const variantFields = `id, product_id, sku, option_values, price, warehouse_item_id, in_stock`
+
+type sqlxProductOption struct {
+	ProductID uuid.UUID `db:"product_id"`
+	Name      string    `db:"name"`
+	Values    string    `db:"option_values"`
+}
+
+type sqlxProductVariant struct {
+	ID              uuid.UUID `db:"id"`
+	ProductID       uuid.UUID `db:"product_id"`
+	SKU             string    `db:"sku"`
+	OptionValues    string    `db:"option_values"`
+	Price           int       `db:"price"`
+	WarehouseItemID uuid.UUID `db:"warehouse_item_id"`
+	InStock         bool      `db:"in_stock"`
+}
+
+type sqlxProductImage struct {
+	ID          uuid.UUID `db:"id"`
+	ProductID   uuid.UUID `db:"product_id"`
+	ContentType string    `db:"content_type"`
+}
+
+func decodeOptionValues(str string) ([]string, error) {
+	var values []string
+	err := json.Unmarshal([]byte(str), &values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode option values: %w", err)
+	}
+	return values, nil
 }
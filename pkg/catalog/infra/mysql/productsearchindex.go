@@ -0,0 +1,282 @@
+package mysql
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/query"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/storage"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"strconv"
+	"strings"
+)
+
+const (
+	matchExpression = `MATCH (title, description) AGAINST (? IN NATURAL LANGUAGE MODE)`
+	scoreExpression = `CAST(` + matchExpression + ` AS DECIMAL(20, 10))`
+)
+
+type productSearchIndex struct {
+	client mysql.Client
+	images storage.BlobStorage
+}
+
+type sqlxSearchProduct struct {
+	sqlxProduct
+	Score string `db:"score"`
+}
+
+func (i *productSearchIndex) Search(params query.ProductSearchParams) (*query.ProductSearchResult, error) {
+	sort, err := params.EffectiveSort()
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := query.DecodeProductSearchCursor(params.Cursor, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args, err := getProductSearchConditions(params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := i.getProducts(params, sort, cursor, where, args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.fillFacets(result, where, args)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (i *productSearchIndex) getProducts(
+	params query.ProductSearchParams,
+	sort query.ProductSearchSort,
+	cursor *query.ProductSearchCursor,
+	where string,
+	whereArgs []any,
+) (*query.ProductSearchResult, error) {
+	score := `0`
+	var args []any
+	if params.Text != "" {
+		score = scoreExpression
+		args = append(args, params.Text)
+	}
+	args = append(args, whereArgs...)
+
+	var order, keyset string
+	switch sort {
+	case query.ProductSearchSortRelevance:
+		order = `score DESC, id`
+		keyset = `(score < CAST(? AS DECIMAL(20, 10)) OR (score = CAST(? AS DECIMAL(20, 10)) AND id > ?))`
+	case query.ProductSearchSortPriceAsc:
+		order = `price, id`
+		keyset = `(price > ? OR (price = ? AND id > ?))`
+	case query.ProductSearchSortPriceDesc:
+		order = `price DESC, id`
+		keyset = `(price < ? OR (price = ? AND id > ?))`
+	default:
+		order = `title, id`
+		keyset = `(title > ? OR (title = ? AND id > ?))`
+	}
+
+	if cursor == nil {
+		keyset = `TRUE`
+	} else {
+		key, err := parseCursorKey(sort, cursor.Key)
+		if err != nil {
+			return nil, err
+		}
+		binaryID, err := cursor.ID.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, key, key, binaryID)
+	}
+	args = append(args, params.Limit+1)
+
+	selectQuery, args, err := sqlx.In(fmt.Sprintf(`
+		SELECT * FROM (
+			SELECT %s, %s AS score FROM product WHERE %s
+		) p
+		WHERE %s
+		ORDER BY %s
+		LIMIT ?
+	`, productFields, score, where, keyset, order), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var productsSqlx []sqlxSearchProduct
+	err = i.client.Select(&productsSqlx, selectQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &query.ProductSearchResult{}
+	if len(productsSqlx) > params.Limit {
+		productsSqlx = productsSqlx[:params.Limit]
+		last := productsSqlx[len(productsSqlx)-1]
+		result.NextCursor = query.EncodeProductSearchCursor(query.ProductSearchCursor{
+			Sort: sort,
+			Key:  getCursorKey(sort, last),
+			ID:   last.ID,
+		})
+	}
+
+	products := make([]sqlxProduct, 0, len(productsSqlx))
+	for _, product := range productsSqlx {
+		products = append(products, product.sqlxProduct)
+	}
+	result.Products, err = getProductsData(i.client, i.images, products)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (i *productSearchIndex) fillFacets(result *query.ProductSearchResult, where string, args []any) error {
+	totalsQuery, totalsArgs, err := sqlx.In(`SELECT COUNT(*) AS total, COALESCE(SUM(in_stock), 0) AS in_stock FROM product WHERE `+where, args...)
+	if err != nil {
+		return err
+	}
+	var totals struct {
+		Total   int `db:"total"`
+		InStock int `db:"in_stock"`
+	}
+	err = i.client.Get(&totals, totalsQuery, totalsArgs...)
+	if err != nil {
+		return err
+	}
+	result.Total = totals.Total
+	result.Facets.InStock = totals.InStock
+
+	buckets := make([]string, 0, len(query.ProductSearchPriceRanges))
+	for n, priceRange := range query.ProductSearchPriceRanges {
+		if priceRange.To == 0 {
+			buckets = append(buckets, fmt.Sprintf(`WHEN price >= %d THEN %d`, priceRange.From, n))
+			continue
+		}
+		buckets = append(buckets, fmt.Sprintf(`WHEN price >= %d AND price < %d THEN %d`, priceRange.From, priceRange.To, n))
+	}
+	pricesQuery, pricesArgs, err := sqlx.In(fmt.Sprintf(`
+		SELECT CASE %s ELSE -1 END AS bucket, COUNT(*) AS count
+		FROM product
+		WHERE %s
+		GROUP BY bucket
+	`, strings.Join(buckets, " "), where), args...)
+	if err != nil {
+		return err
+	}
+	var priceCounts []struct {
+		Bucket int `db:"bucket"`
+		Count  int `db:"count"`
+	}
+	err = i.client.Select(&priceCounts, pricesQuery, pricesArgs...)
+	if err != nil {
+		return err
+	}
+	result.Facets.PriceRanges = make([]query.PriceRangeFacet, 0, len(query.ProductSearchPriceRanges))
+	for _, priceRange := range query.ProductSearchPriceRanges {
+		result.Facets.PriceRanges = append(result.Facets.PriceRanges, query.PriceRangeFacet{PriceRange: priceRange})
+	}
+	for _, priceCount := range priceCounts {
+		if priceCount.Bucket >= 0 {
+			result.Facets.PriceRanges[priceCount.Bucket].Count = priceCount.Count
+		}
+	}
+
+	categoriesQuery, categoriesArgs, err := sqlx.In(`
+		SELECT category_id, COUNT(*) AS count
+		FROM product_category
+		WHERE product_id IN (SELECT id FROM product WHERE `+where+`)
+		GROUP BY category_id
+		ORDER BY count DESC
+	`, args...)
+	if err != nil {
+		return err
+	}
+	var categoryCounts []struct {
+		CategoryID uuid.UUID `db:"category_id"`
+		Count      int       `db:"count"`
+	}
+	err = i.client.Select(&categoryCounts, categoriesQuery, categoriesArgs...)
+	if err != nil {
+		return err
+	}
+	result.Facets.Categories = make([]query.CategoryFacet, 0, len(categoryCounts))
+	for _, categoryCount := range categoryCounts {
+		result.Facets.Categories = append(result.Facets.Categories, query.CategoryFacet{
+			CategoryID: categoryCount.CategoryID,
+			Count:      categoryCount.Count,
+		})
+	}
+	return nil
+}
+
+func getProductSearchConditions(params query.ProductSearchParams) (string, []any, error) {
+	conditions := []string{`TRUE`}
+	var args []any
+	if params.Text != "" {
+		conditions = append(conditions, matchExpression)
+		args = append(args, params.Text)
+	}
+	if params.MinPrice > 0 {
+		conditions = append(conditions, `price >= ?`)
+		args = append(args, params.MinPrice)
+	}
+	if params.MaxPrice > 0 {
+		conditions = append(conditions, `price <= ?`)
+		args = append(args, params.MaxPrice)
+	}
+	if len(params.CategoryIDs) > 0 {
+		binaryCategoryIDs, err := marshalUUIDs(params.CategoryIDs)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, `id IN (SELECT product_id FROM product_category WHERE category_id IN (?))`)
+		args = append(args, binaryCategoryIDs)
+	}
+	if params.InStockOnly {
+		conditions = append(conditions, `in_stock = 1`)
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+func parseCursorKey(sort query.ProductSearchSort, key string) (any, error) {
+	switch sort {
+	case query.ProductSearchSortRelevance:
+		_, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return nil, query.ErrInvalidSearchCursor
+		}
+		return key, nil
+	case query.ProductSearchSortPriceAsc, query.ProductSearchSortPriceDesc:
+		price, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, query.ErrInvalidSearchCursor
+		}
+		return price, nil
+	default:
+		return key, nil
+	}
+}
+
+func getCursorKey(sort query.ProductSearchSort, product sqlxSearchProduct) string {
+	switch sort {
+	case query.ProductSearchSortRelevance:
+		return product.Score
+	case query.ProductSearchSortPriceAsc, query.ProductSearchSortPriceDesc:
+		return strconv.Itoa(product.Price)
+	default:
+		return product.Title
+	}
+}
+
+func NewProductSearchIndex(client mysql.Client, images storage.BlobStorage) query.ProductSearchIndex {
+	return &productSearchIndex{client: client, images: images}
+}
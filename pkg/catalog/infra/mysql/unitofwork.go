@@ -3,7 +3,10 @@ package mysql
 import (
 	"fmt"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service/async"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/infra/producteventapi"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
 )
 
@@ -15,6 +18,14 @@ func (p *persistentProvider) ProductRepository() domain.ProductRepository {
 	return NewProductRepository(p.db)
 }
 
+func (p *persistentProvider) CategoryRepository() domain.CategoryRepository {
+	return NewCategoryRepository(p.db)
+}
+
+func (p *persistentProvider) ProductEventAPI() async.ProductEventAPI {
+	return producteventapi.New(event.NewDispatcher(mysql.NewMessageStore(p.db)))
+}
+
 type unitOfWork struct {
 	client mysql.TransactionalClient
 }
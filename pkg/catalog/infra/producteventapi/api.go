@@ -0,0 +1,61 @@
+package producteventapi
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
+)
+
+const productEventTopicName = "product_event"
+
+type api struct {
+	eventDispatcher event.Dispatcher
+}
+
+func (a *api) NotifyPriceChanged(productID uuid.UUID, oldPrice, newPrice int) error {
+	body, err := json.Marshal(struct {
+		ProductID uuid.UUID `json:"product_id"`
+		OldPrice  int       `json:"old_price"`
+		NewPrice  int       `json:"new_price"`
+	}{productID, oldPrice, newPrice})
+	if err != nil {
+		return errors.New("failed to encode product event")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "product_price_changed",
+		TopicName: productEventTopicName,
+		Key:       productID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func (a *api) NotifyBackInStock(productID uuid.UUID) error {
+	body, err := json.Marshal(struct {
+		ProductID uuid.UUID `json:"product_id"`
+	}{productID})
+	if err != nil {
+		return errors.New("failed to encode product event")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "product_back_in_stock",
+		TopicName: productEventTopicName,
+		Key:       productID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func New(eventDispatcher event.Dispatcher) async.ProductEventAPI {
+	return &api{eventDispatcher: eventDispatcher}
+}
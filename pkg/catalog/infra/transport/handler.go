@@ -7,9 +7,14 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/query"
 	"github.com/klwxsrx/arch-course-project/pkg/catalog/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/catalog/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/transport"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 const healthEndpoint = "/healthz"
@@ -18,17 +23,35 @@ type route struct {
 	Name    string
 	Method  string
 	Pattern string
-	Handler func(*service.ProductService, query.ProductService, http.ResponseWriter, *http.Request)
+	Handler func(*service.ProductService, *service.CategoryService, query.ProductService, query.CategoryService, query.ProductSearchIndex, http.ResponseWriter, *http.Request)
 }
 
 func getRoutes() []route {
 	return []route{
+		{
+			"searchProductsWeb",
+			http.MethodGet,
+			"/web/products/search",
+			searchProductsHandler,
+		},
+		{
+			"getProductWeb",
+			http.MethodGet,
+			"/web/products/{productID}",
+			getProductHandler,
+		},
 		{
 			"getProductsWeb",
 			http.MethodGet,
 			"/web/products",
 			getProductsHandler,
 		},
+		{
+			"getProductImageWeb",
+			http.MethodGet,
+			"/web/product/images/{key:.+}",
+			getProductImageHandler,
+		},
 		{
 			"getProductsByIDs",
 			http.MethodGet,
@@ -47,6 +70,72 @@ func getRoutes() []route {
 			"/product/{productID}",
 			updateProductHandler,
 		},
+		{
+			"setProductVariants",
+			http.MethodPut,
+			"/product/{productID}/variants",
+			setProductVariantsHandler,
+		},
+		{
+			"setProductCategories",
+			http.MethodPut,
+			"/product/{productID}/categories",
+			setProductCategoriesHandler,
+		},
+		{
+			"addProductImage",
+			http.MethodPost,
+			"/product/{productID}/images",
+			addProductImageHandler,
+		},
+		{
+			"setProductImageOrder",
+			http.MethodPut,
+			"/product/{productID}/images",
+			setProductImageOrderHandler,
+		},
+		{
+			"deleteProductImage",
+			http.MethodDelete,
+			"/product/{productID}/image/{imageID}",
+			deleteProductImageHandler,
+		},
+		{
+			"getCategoriesWeb",
+			http.MethodGet,
+			"/web/categories",
+			getCategoryTreeHandler,
+		},
+		{
+			"getCategoryProductsWeb",
+			http.MethodGet,
+			"/web/categories/{slug}/products",
+			getCategoryProductsHandler,
+		},
+		{
+			"getCategories",
+			http.MethodGet,
+			"/categories",
+			getCategoriesHandler,
+		},
+		{
+			"addCategory",
+			http.MethodPut,
+			"/categories",
+			addCategoryHandler,
+		},
+		{
+			"updateCategory",
+			http.MethodPatch,
+			"/category/{categoryID}",
+			updateCategoryHandler,
+		},
+		{
+			"deleteCategory",
+			http.MethodDelete,
+			"/category/{categoryID}",
+			deleteCategoryHandler,
+		},
 		{
 			"health",
 			http.MethodGet,
@@ -60,16 +149,104 @@ type productJSONSchema struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Price       int    `json:"price"`
+	Weight      int    `json:"weight"`
+	MaxQuantity int    `json:"max_quantity"`
 }
 
 type productWithIDJSONSchema struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Price       int       `json:"price"`
+	ID          uuid.UUID                  `json:"id"`
+	Title       string                     `json:"title"`
+	Description string                     `json:"description"`
+	Price       int                        `json:"price"`
+	Weight      int                        `json:"weight"`
+	MaxQuantity int                        `json:"max_quantity"`
+	CategoryIDs []uuid.UUID                `json:"category_ids"`
+	Options     []productOptionJSONSchema  `json:"options"`
+	Variants    []productVariantJSONSchema `json:"variants"`
+	Images      []productImageJSONSchema   `json:"images"`
 }
 
-func getProductsHandler(_ *service.ProductService, service query.ProductService, w http.ResponseWriter, _ *http.Request) {
+type productImageJSONSchema struct {
+	ID         uuid.UUID         `json:"id"`
+	URL        string            `json:"url"`
+	Thumbnails map[string]string `json:"thumbnails"`
+}
+
+type productOptionJSONSchema struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type productVariantJSONSchema struct {
+	ID              uuid.UUID         `json:"id"`
+	SKU             string            `json:"sku"`
+	Options         map[string]string `json:"options"`
+	Price           int               `json:"price"`
+	WarehouseItemID uuid.UUID         `json:"warehouse_item_id"`
+	InStock         bool              `json:"in_stock"`
+}
+
+type productVariantsJSONSchema struct {
+	Options  []productOptionJSONSchema `json:"options"`
+	Variants []struct {
+		ID              *uuid.UUID        `json:"id"`
+		SKU             string            `json:"sku"`
+		Options         map[string]string `json:"options"`
+		Price           int               `json:"price"`
+		WarehouseItemID *uuid.UUID        `json:"warehouse_item_id"`
+	} `json:"variants"`
+}
+
+type categoryJSONSchema struct {
+	ParentID *uuid.UUID `json:"parent_id"`
+	Slug     string     `json:"slug"`
+	Title    string     `json:"title"`
+	Position int        `json:"position"`
+}
+
+type categoryWithIDJSONSchema struct {
+	ID       uuid.UUID  `json:"id"`
+	ParentID *uuid.UUID `json:"parent_id"`
+	Slug     string     `json:"slug"`
+	Title    string     `json:"title"`
+	Position int        `json:"position"`
+}
+
+type searchProductJSONSchema struct {
+	productWithIDJSONSchema
+	InStock bool `json:"in_stock"`
+}
+
+type categoryFacetJSONSchema struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Count      int       `json:"count"`
+}
+
+type priceRangeFacetJSONSchema struct {
+	From  int  `json:"from"`
+	To    *int `json:"to"`
+	Count int  `json:"count"`
+}
+
+type searchResultJSONSchema struct {
+	Products   []searchProductJSONSchema `json:"products"`
+	Total      int                       `json:"total"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+	Facets     struct {
+		Categories  []categoryFacetJSONSchema   `json:"categories"`
+		PriceRanges []priceRangeFacetJSONSchema `json:"price_ranges"`
+		InStock     int                         `json:"in_stock"`
+	} `json:"facets"`
+}
+
+type categoryTreeJSONSchema struct {
+	ID       uuid.UUID                `json:"id"`
+	Slug     string                   `json:"slug"`
+	Title    string                   `json:"title"`
+	Children []categoryTreeJSONSchema `json:"children"`
+}
+
+func getProductsHandler(_ *service.ProductService, _ *service.CategoryService, service query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, _ *http.Request) {
 	products, err := service.ListAll()
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -78,13 +255,102 @@ func getProductsHandler(_ *service.ProductService, service query.ProductService,
 
 	result := make([]productWithIDJSONSchema, 0, len(products))
 	for _, product := range products {
-		result = append(result, productWithIDJSONSchema{
-			ID:          product.ID,
-			Title:       product.Title,
-			Description: product.Description,
-			Price:       product.Price,
+		result = append(result, getProductJSON(product))
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func searchProductsHandler(_ *service.ProductService, _ *service.CategoryService, _ query.ProductService, categoryQuery query.CategoryService, searchIndex query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	const (
+		defaultSearchLimit = 20
+		maxSearchLimit     = 100
+	)
+
+	values := r.URL.Query()
+	params := query.ProductSearchParams{
+		Text:   strings.TrimSpace(values.Get("q")),
+		Sort:   query.ProductSearchSort(values.Get("sort")),
+		Cursor: values.Get("cursor"),
+		Limit:  defaultSearchLimit,
+	}
+
+	var err error
+	for param, value := range map[string]*int{"min_price": &params.MinPrice, "max_price": &params.MaxPrice, "limit": &params.Limit} {
+		if values.Get(param) == "" {
+			continue
+		}
+		*value, err = strconv.Atoi(values.Get(param))
+		if err != nil || *value < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	if params.Limit <= 0 || params.Limit > maxSearchLimit {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if inStockParam := values.Get("in_stock"); inStockParam != "" {
+		params.InStockOnly, err = strconv.ParseBool(inStockParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	if slug := values.Get("category"); slug != "" {
+		params.CategoryIDs, err = categoryQuery.GetSubtreeIDsBySlug(slug)
+		if errors.Is(err, query.ErrCategoryBySlugNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	searchResult, err := searchIndex.Search(params)
+	if errors.Is(err, query.ErrUnknownSearchSort) || errors.Is(err, query.ErrInvalidSearchCursor) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := searchResultJSONSchema{
+		Products:   make([]searchProductJSONSchema, 0, len(searchResult.Products)),
+		Total:      searchResult.Total,
+		NextCursor: searchResult.NextCursor,
+	}
+	for _, product := range searchResult.Products {
+		result.Products = append(result.Products, searchProductJSONSchema{
+			productWithIDJSONSchema: getProductJSON(product),
+			InStock:                 product.InStock,
 		})
 	}
+	result.Facets.Categories = make([]categoryFacetJSONSchema, 0, len(searchResult.Facets.Categories))
+	for _, facet := range searchResult.Facets.Categories {
+		result.Facets.Categories = append(result.Facets.Categories, categoryFacetJSONSchema{
+			CategoryID: facet.CategoryID,
+			Count:      facet.Count,
+		})
+	}
+	result.Facets.PriceRanges = make([]priceRangeFacetJSONSchema, 0, len(searchResult.Facets.PriceRanges))
+	for _, facet := range searchResult.Facets.PriceRanges {
+		priceRange := priceRangeFacetJSONSchema{From: facet.From, Count: facet.Count}
+		if facet.To != 0 {
+			to := facet.To
+			priceRange.To = &to
+		}
+		result.Facets.PriceRanges = append(result.Facets.PriceRanges, priceRange)
+	}
+	result.Facets.InStock = searchResult.Facets.InStock
 
 	err = json.NewEncoder(w).Encode(result)
 	if err != nil {
@@ -93,7 +359,7 @@ func getProductsHandler(_ *service.ProductService, service query.ProductService,
 	}
 }
 
-func getProductsByIDsHandler(_ *service.ProductService, service query.ProductService, w http.ResponseWriter, r *http.Request) {
+func getProductsByIDsHandler(_ *service.ProductService, _ *service.CategoryService, service query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
 	var productIDs []uuid.UUID
 	err := json.NewDecoder(r.Body).Decode(&productIDs)
 	if err != nil {
@@ -113,12 +379,7 @@ func getProductsByIDsHandler(_ *service.ProductService, service query.ProductSer
 
 	result := make([]productWithIDJSONSchema, 0, len(products))
 	for _, product := range products {
-		result = append(result, productWithIDJSONSchema{
-			ID:          product.ID,
-			Title:       product.Title,
-			Description: product.Description,
-			Price:       product.Price,
-		})
+		result = append(result, getProductJSON(product))
 	}
 
 	err = json.NewEncoder(w).Encode(result)
@@ -128,7 +389,7 @@ func getProductsByIDsHandler(_ *service.ProductService, service query.ProductSer
 	}
 }
 
-func addProductHandler(srv *service.ProductService, _ query.ProductService, w http.ResponseWriter, r *http.Request) {
+func addProductHandler(srv *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
 	var productBody productJSONSchema
 	err := json.NewDecoder(r.Body).Decode(&productBody)
 	if err != nil {
@@ -136,7 +397,7 @@ func addProductHandler(srv *service.ProductService, _ query.ProductService, w ht
 		return
 	}
 
-	productID, err := srv.Add(productBody.Title, productBody.Description, productBody.Price)
+	productID, err := srv.Add(productBody.Title, productBody.Description, productBody.Price, productBody.Weight, productBody.MaxQuantity)
 	switch err {
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
@@ -147,7 +408,7 @@ func addProductHandler(srv *service.ProductService, _ query.ProductService, w ht
 	}
 }
 
-func updateProductHandler(srv *service.ProductService, _ query.ProductService, w http.ResponseWriter, r *http.Request) {
+func updateProductHandler(srv *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
 	productID, err := parseUUID(mux.Vars(r)["productID"])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -161,7 +422,7 @@ func updateProductHandler(srv *service.ProductService, _ query.ProductService, w
 		return
 	}
 
-	err = srv.Update(productID, productBody.Title, productBody.Description, productBody.Price)
+	err = srv.Update(productID, productBody.Title, productBody.Description, productBody.Price, productBody.Weight, productBody.MaxQuantity)
 	switch err {
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
@@ -174,7 +435,378 @@ func updateProductHandler(srv *service.ProductService, _ query.ProductService, w
 	}
 }
 
-func healthCheckHandler(_ *service.ProductService, _ query.ProductService, w http.ResponseWriter, _ *http.Request) {
+func getProductHandler(_ *service.ProductService, _ *service.CategoryService, productQuery query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	products, err := productQuery.GetByIDs([]uuid.UUID{productID})
+	if errors.Is(err, query.ErrProductByIDNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(searchProductJSONSchema{
+		productWithIDJSONSchema: getProductJSON(products[0]),
+		InStock:                 products[0].InStock,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func setProductVariantsHandler(srv *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var body productVariantsJSONSchema
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	options := make([]domain.ProductOption, 0, len(body.Options))
+	for _, option := range body.Options {
+		options = append(options, domain.ProductOption{Name: option.Name, Values: option.Values})
+	}
+	variants := make([]domain.ProductVariant, 0, len(body.Variants))
+	for _, variant := range body.Variants {
+		if len(variant.Options) != len(options) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		optionValues := make([]string, 0, len(options))
+		for _, option := range options {
+			optionValues = append(optionValues, variant.Options[option.Name])
+		}
+		variants = append(variants, domain.ProductVariant{
+			ID:              uuidOrNil(variant.ID),
+			SKU:             variant.SKU,
+			OptionValues:    optionValues,
+			Price:           variant.Price,
+			WarehouseItemID: uuidOrNil(variant.WarehouseItemID),
+		})
+	}
+
+	err = srv.SetVariants(productID, options, variants)
+	switch {
+	case errors.Is(err, service.ErrProductNotExists):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidProperty):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrSKUExists):
+		w.WriteHeader(http.StatusConflict)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func addProductImageHandler(srv *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.ContentLength > service.MaxImageSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, service.MaxImageSize+1))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	imageID, err := srv.AddImage(productID, contentType, data)
+	switch {
+	case errors.Is(err, service.ErrProductNotExists):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrUnsupportedImageType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Is(err, service.ErrImageTooLarge):
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrInvalidImage):
+		w.WriteHeader(http.StatusBadRequest)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		_ = json.NewEncoder(w).Encode(imageID)
+	}
+}
+
+func setProductImageOrderHandler(srv *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var imageIDs []uuid.UUID
+	err = json.NewDecoder(r.Body).Decode(&imageIDs)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.SetImageOrder(productID, imageIDs)
+	switch {
+	case errors.Is(err, service.ErrProductNotExists):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidProperty):
+		w.WriteHeader(http.StatusBadRequest)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func deleteProductImageHandler(srv *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	imageID, err := parseUUID(mux.Vars(r)["imageID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.RemoveImage(productID, imageID)
+	switch {
+	case errors.Is(err, service.ErrProductNotExists) || errors.Is(err, service.ErrProductImageNotExists):
+		w.WriteHeader(http.StatusNotFound)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getProductImageHandler(srv *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	blob, err := srv.GetImageBlob(mux.Vars(r)["key"])
+	if errors.Is(err, service.ErrProductImageNotExists) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer blob.Close()
+
+	// image keys are never reused, so the content can be cached forever
+	w.Header().Set("Content-Type", blob.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	_, _ = io.Copy(w, blob)
+}
+
+func setProductCategoriesHandler(srv *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	productID, err := parseUUID(mux.Vars(r)["productID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var categoryIDs []uuid.UUID
+	err = json.NewDecoder(r.Body).Decode(&categoryIDs)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.SetCategories(productID, categoryIDs)
+	switch {
+	case errors.Is(err, service.ErrProductNotExists):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrCategoryNotExists):
+		w.WriteHeader(http.StatusBadRequest)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getCategoryTreeHandler(_ *service.ProductService, _ *service.CategoryService, _ query.ProductService, categoryQuery query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, _ *http.Request) {
+	categories, err := categoryQuery.ListAll()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	childrenByParentID := make(map[uuid.UUID][]query.CategoryData, len(categories))
+	for _, category := range categories {
+		childrenByParentID[category.ParentID] = append(childrenByParentID[category.ParentID], category)
+	}
+
+	var buildTree func(parentID uuid.UUID) []categoryTreeJSONSchema
+	buildTree = func(parentID uuid.UUID) []categoryTreeJSONSchema {
+		result := make([]categoryTreeJSONSchema, 0, len(childrenByParentID[parentID]))
+		for _, category := range childrenByParentID[parentID] {
+			result = append(result, categoryTreeJSONSchema{
+				ID:       category.ID,
+				Slug:     category.Slug,
+				Title:    category.Title,
+				Children: buildTree(category.ID),
+			})
+		}
+		return result
+	}
+
+	err = json.NewEncoder(w).Encode(buildTree(uuid.Nil))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getCategoryProductsHandler(_ *service.ProductService, _ *service.CategoryService, _ query.ProductService, categoryQuery query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	products, err := categoryQuery.GetProductsBySlug(mux.Vars(r)["slug"])
+	if errors.Is(err, query.ErrCategoryBySlugNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]productWithIDJSONSchema, 0, len(products))
+	for _, product := range products {
+		result = append(result, getProductJSON(product))
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getCategoriesHandler(_ *service.ProductService, _ *service.CategoryService, _ query.ProductService, categoryQuery query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, _ *http.Request) {
+	categories, err := categoryQuery.ListAll()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]categoryWithIDJSONSchema, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, categoryWithIDJSONSchema{
+			ID:       category.ID,
+			ParentID: nullableUUID(category.ParentID),
+			Slug:     category.Slug,
+			Title:    category.Title,
+			Position: category.Position,
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func addCategoryHandler(_ *service.ProductService, srv *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	var categoryBody categoryJSONSchema
+	err := json.NewDecoder(r.Body).Decode(&categoryBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	categoryID, err := srv.Add(
+		uuidOrNil(categoryBody.ParentID),
+		categoryBody.Slug,
+		categoryBody.Title,
+		categoryBody.Position,
+	)
+	if writeCategoryError(w, err) {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(categoryID)
+}
+
+func updateCategoryHandler(_ *service.ProductService, srv *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	categoryID, err := parseUUID(mux.Vars(r)["categoryID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var categoryBody categoryJSONSchema
+	err = json.NewDecoder(r.Body).Decode(&categoryBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.Update(
+		categoryID,
+		uuidOrNil(categoryBody.ParentID),
+		categoryBody.Slug,
+		categoryBody.Title,
+		categoryBody.Position,
+	)
+	if writeCategoryError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func deleteCategoryHandler(_ *service.ProductService, srv *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, r *http.Request) {
+	categoryID, err := parseUUID(mux.Vars(r)["categoryID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.Delete(categoryID)
+	if writeCategoryError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCategoryError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrCategoryNotExists):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidProperty) || errors.Is(err, service.ErrInvalidCategoryParent):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrCategorySlugExists) || errors.Is(err, service.ErrCategoryHasChildren):
+		w.WriteHeader(http.StatusConflict)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	return true
+}
+
+func healthCheckHandler(_ *service.ProductService, _ *service.CategoryService, _ query.ProductService, _ query.CategoryService, _ query.ProductSearchIndex, w http.ResponseWriter, _ *http.Request) {
 	_ = json.NewEncoder(w).Encode(struct {
 		Status string `json:"status"`
 	}{"OK"})
@@ -184,17 +816,90 @@ func parseUUID(str string) (uuid.UUID, error) {
 	return uuid.Parse(str)
 }
 
+func getProductJSON(product query.ProductData) productWithIDJSONSchema {
+	result := productWithIDJSONSchema{
+		ID:          product.ID,
+		Title:       product.Title,
+		Description: product.Description,
+		Price:       product.Price,
+		Weight:      product.Weight,
+		MaxQuantity: product.MaxQuantity,
+		CategoryIDs: nonNilUUIDs(product.CategoryIDs),
+		Options:     make([]productOptionJSONSchema, 0, len(product.Options)),
+		Variants:    make([]productVariantJSONSchema, 0, len(product.Variants)),
+		Images:      make([]productImageJSONSchema, 0, len(product.Images)),
+	}
+	for _, option := range product.Options {
+		result.Options = append(result.Options, productOptionJSONSchema{Name: option.Name, Values: option.Values})
+	}
+	for _, variant := range product.Variants {
+		options := make(map[string]string, len(variant.OptionValues))
+		for i, value := range variant.OptionValues {
+			if i < len(product.Options) {
+				options[product.Options[i].Name] = value
+			}
+		}
+		result.Variants = append(result.Variants, productVariantJSONSchema{
+			ID:              variant.ID,
+			SKU:             variant.SKU,
+			Options:         options,
+			Price:           variant.Price,
+			WarehouseItemID: variant.WarehouseItemID,
+			InStock:         variant.InStock,
+		})
+	}
+	for _, image := range product.Images {
+		result.Images = append(result.Images, productImageJSONSchema{
+			ID:         image.ID,
+			URL:        image.URL,
+			Thumbnails: image.ThumbnailURLs,
+		})
+	}
+	return result
+}
+
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+func uuidOrNil(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
+
+func nonNilUUIDs(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return []uuid.UUID{}
+	}
+	return ids
+}
+
 func getHandlerFunc(
-	service *service.ProductService,
-	query query.ProductService,
-	f func(*service.ProductService, query.ProductService, http.ResponseWriter, *http.Request),
+	productService *service.ProductService,
+	categoryService *service.CategoryService,
+	productQuery query.ProductService,
+	categoryQuery query.CategoryService,
+	searchIndex query.ProductSearchIndex,
+	f func(*service.ProductService, *service.CategoryService, query.ProductService, query.CategoryService, query.ProductSearchIndex, http.ResponseWriter, *http.Request),
 ) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		f(service, query, w, r)
+		f(productService, categoryService, productQuery, categoryQuery, searchIndex, w, r)
 	}
 }
 
-func NewHTTPHandler(service *service.ProductService, query query.ProductService, logger log.Logger) (http.Handler, error) {
+func NewHTTPHandler(
+	productService *service.ProductService,
+	categoryService *service.CategoryService,
+	productQuery query.ProductService,
+	categoryQuery query.CategoryService,
+	searchIndex query.ProductSearchIndex,
+	logger log.Logger,
+) (http.Handler, error) {
 	router := mux.NewRouter()
 
 	for _, route := range getRoutes() {
@@ -202,7 +907,7 @@ func NewHTTPHandler(service *service.ProductService, query query.ProductService,
 			Methods(route.Method).
 			Path(route.Pattern).
 			Name(route.Name).
-			HandlerFunc(getHandlerFunc(service, query, route.Handler))
+			HandlerFunc(getHandlerFunc(productService, categoryService, productQuery, categoryQuery, searchIndex, route.Handler))
 	}
 
 	router.Use(transport.NewLoggingMiddleware(logger, []string{healthEndpoint}))
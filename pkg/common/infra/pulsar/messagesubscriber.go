@@ -90,6 +90,7 @@ func (s *MessageSubscriber) processMessage(msg *pulsar.ConsumerMessage) {
 
 	handler, ok := s.handlers[subscription{Topic: msg.Topic(), Type: typ}]
 	if !ok {
+		msg.Consumer.Ack(msg)
 		return
 	}
 
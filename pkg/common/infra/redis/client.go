@@ -5,11 +5,21 @@ import (
 	"time"
 )
 
-var ErrKeyDoesNotExist = errors.New("key does not exist")
+var (
+	ErrKeyDoesNotExist = errors.New("key does not exist")
+	ErrTxConflict      = errors.New("watched key has been modified")
+)
+
+type Tx interface {
+	Get(key string) (string, error)
+	Set(key, value string, ttl *time.Duration)
+	Del(key string)
+}
 
 type Client interface {
 	Set(key, value string, ttl *time.Duration) error
 	Get(key string) (string, error)
 	Del(key string) error
+	Watch(key string, f func(tx Tx) error) error
 	Close()
 }
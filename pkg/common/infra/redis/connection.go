@@ -39,10 +39,61 @@ func (c *connection) Del(key string) error {
 	return c.client.Del(context.Background(), key).Err()
 }
 
+func (c *connection) Watch(key string, f func(tx Tx) error) error {
+	err := c.client.Watch(context.Background(), func(redisTx *redis.Tx) error {
+		t := &transaction{tx: redisTx}
+		err := f(t)
+		if err != nil || len(t.commands) == 0 {
+			return err
+		}
+
+		_, err = redisTx.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+			for _, command := range t.commands {
+				command(pipe)
+			}
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrTxConflict
+	}
+	return err
+}
+
 func (c *connection) Close() {
 	_ = c.client.Close()
 }
 
+type transaction struct {
+	tx       *redis.Tx
+	commands []func(pipe redis.Pipeliner)
+}
+
+func (t *transaction) Get(key string) (string, error) {
+	val, err := t.tx.Get(context.Background(), key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrKeyDoesNotExist
+	}
+	return val, err
+}
+
+func (t *transaction) Set(key, value string, ttl *time.Duration) {
+	var expiration time.Duration
+	if ttl != nil {
+		expiration = *ttl
+	}
+	t.commands = append(t.commands, func(pipe redis.Pipeliner) {
+		pipe.Set(context.Background(), key, value, expiration)
+	})
+}
+
+func (t *transaction) Del(key string) {
+	t.commands = append(t.commands, func(pipe redis.Pipeliner) {
+		pipe.Del(context.Background(), key)
+	})
+}
+
 func newOpenConnectionBackoff(connTimeout time.Duration) *backoff.ExponentialBackOff {
 	b := backoff.NewExponentialBackOff()
 	b.MaxElapsedTime = connTimeout
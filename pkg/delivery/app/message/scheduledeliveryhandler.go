@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/service"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
 )
 
 type scheduleDeliveryHandler struct {
@@ -22,8 +23,15 @@ func (h *scheduleDeliveryHandler) Type() string {
 
 func (h *scheduleDeliveryHandler) Handle(msg *message.Message) error {
 	body := struct {
-		OrderID   uuid.UUID `json:"order_id"`
-		AddressID uuid.UUID `json:"address_id"`
+		OrderID       uuid.UUID `json:"order_id"`
+		UserID        uuid.UUID `json:"user_id"`
+		AddressID     uuid.UUID `json:"address_id"`
+		PickupPointID uuid.UUID `json:"pickup_point_id"`
+		SlotID        uuid.UUID `json:"slot_id"`
+		Items         []struct {
+			ProductID uuid.UUID `json:"product_id"`
+			Quantity  int       `json:"quantity"`
+		} `json:"items"`
 	}{}
 
 	err := json.Unmarshal(msg.Body, &body)
@@ -31,7 +39,22 @@ func (h *scheduleDeliveryHandler) Handle(msg *message.Message) error {
 		return fmt.Errorf("failed to decode message")
 	}
 
-	err = h.service.Schedule(body.OrderID, body.AddressID)
+	items := make([]domain.DeliveryItem, 0, len(body.Items))
+	for _, item := range body.Items {
+		items = append(items, domain.DeliveryItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	err = h.service.Schedule(&service.ScheduleDeliveryData{
+		OrderID:       body.OrderID,
+		UserID:        body.UserID,
+		AddressID:     body.AddressID,
+		PickupPointID: body.PickupPointID,
+		SlotID:        body.SlotID,
+		Items:         items,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to schedule delivery: %w", err)
 	}
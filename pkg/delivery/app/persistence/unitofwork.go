@@ -7,7 +7,16 @@ import (
 
 type PersistentProvider interface {
 	DeliveryRepository() domain.DeliveryRepository
+	DeliveryAttemptRepository() domain.DeliveryAttemptRepository
+	TrackingEventRepository() domain.TrackingEventRepository
+	AddressRepository() domain.AddressRepository
+	ZoneRepository() domain.ZoneRepository
+	SlotRepository() domain.SlotRepository
+	CourierRepository() domain.CourierRepository
+	RouteRepository() domain.RouteRepository
+	PickupPointRepository() domain.PickupPointRepository
 	OrderAPI() async.OrderAPI
+	DeliveryEventAPI() async.DeliveryEventAPI
 }
 
 type UnitOfWork interface {
@@ -4,16 +4,107 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
 )
 
-var ErrDeliveryNotFound = errors.New("delivery not found")
+var (
+	ErrDeliveryNotFound    = errors.New("delivery not found")
+	ErrAddressNotFound     = errors.New("address not found")
+	ErrZoneNotFound        = errors.New("zone not found")
+	ErrCourierNotFound     = errors.New("courier not found")
+	ErrSlotNotFound        = errors.New("slot not found")
+	ErrPickupPointNotFound = errors.New("pickup point not found")
+)
 
 type Delivery struct {
-	OrderID uuid.UUID
-	Status  domain.DeliveryStatus
-	Address string
+	ID               uuid.UUID
+	OrderID          uuid.UUID
+	Items            []domain.DeliveryItem
+	Status           domain.DeliveryStatus
+	SlotID           *uuid.UUID
+	PickupPointID    *uuid.UUID
+	PickupCode       string
+	Address          domain.PostalAddress
+	CourierID        *uuid.UUID
+	FailureReason    string
+	Attempts         int
+	EstimatedArrival *time.Time
+}
+
+type DeliveryAttempt struct {
+	Number      int
+	Reason      string
+	AttemptedAt time.Time
+}
+
+type TrackingEvent struct {
+	ID         int64
+	DeliveryID uuid.UUID
+	Type       domain.TrackingEventType
+	Details    string
+	OccurredAt time.Time
+}
+
+type Address struct {
+	ID        uuid.UUID
+	Address   domain.PostalAddress
+	IsDefault bool
+}
+
+type Zone struct {
+	ID                 uuid.UUID
+	Name               string
+	Cities             []string
+	PostalCodeRanges   []domain.PostalCodeRange
+	Rates              []domain.ShippingRate
+	FreeShippingAmount int
+}
+
+type Slot struct {
+	ID        uuid.UUID
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Available int
+}
+
+type PickupPoint struct {
+	ID           uuid.UUID
+	Name         string
+	Address      domain.PostalAddress
+	OpeningHours []domain.OpeningHours
+	Capacity     int
+	Available    int
+	Active       bool
+}
+
+type Courier struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	Name     string
+	Phone    string
+	ZoneID   uuid.UUID
+	Capacity int
+	Active   bool
 }
 
 type Service interface {
-	GetByID(orderID uuid.UUID) (*Delivery, error)
+	ListOrderDeliveries(orderID uuid.UUID) ([]Delivery, error)
+	ListUserOrderDeliveries(userID, orderID uuid.UUID) ([]Delivery, error)
+	GetUserDelivery(userID, deliveryID uuid.UUID) (*Delivery, error)
+	ListDeliveryAttempts(deliveryID uuid.UUID) ([]DeliveryAttempt, error)
+	ListTrackingEvents(orderID uuid.UUID, afterID int64) ([]TrackingEvent, error)
+	ListUserAddresses(userID uuid.UUID) ([]Address, error)
+	GetUserAddress(userID, addressID uuid.UUID) (*Address, error)
+	GetUserDefaultAddress(userID uuid.UUID) (*Address, error)
+	ListZones() ([]Zone, error)
+	FindZoneByAddress(address *domain.PostalAddress) (*Zone, error)
+	ListAvailableSlots(zoneID uuid.UUID, from time.Time) ([]Slot, error)
+	GetSlot(slotID uuid.UUID) (*Slot, error)
+	ListPickupPoints() ([]PickupPoint, error)
+	ListActivePickupPoints(city string) ([]PickupPoint, error)
+	GetPickupPoint(pickupPointID uuid.UUID) (*PickupPoint, error)
+	ListCouriers() ([]Courier, error)
+	GetCourierByID(courierID uuid.UUID) (*Courier, error)
+	GetCourierByUserID(userID uuid.UUID) (*Courier, error)
+	ListCourierDeliveries(courierID uuid.UUID) ([]Delivery, error)
 }
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+)
+
+var (
+	ErrInvalidAddress  = errors.New("invalid address")
+	ErrAddressNotFound = errors.New("address not found")
+)
+
+type AddressService struct {
+	ufw    persistence.UnitOfWork
+	logger log.Logger
+}
+
+func (s *AddressService) Add(userID uuid.UUID, postalAddress domain.PostalAddress, isDefault bool) (uuid.UUID, error) {
+	if !postalAddress.IsValid() {
+		return uuid.Nil, ErrInvalidAddress
+	}
+
+	var addressID uuid.UUID
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		addresses, err := p.AddressRepository().FindByUserID(userID)
+		if err != nil {
+			return fmt.Errorf("failed to find user addresses: %w", err)
+		}
+
+		address := &domain.Address{
+			ID:        p.AddressRepository().NextID(),
+			UserID:    userID,
+			Address:   postalAddress,
+			IsDefault: isDefault || len(addresses) == 0,
+		}
+		if address.IsDefault {
+			err = resetDefaultAddress(addresses, p.AddressRepository())
+			if err != nil {
+				return err
+			}
+		}
+
+		err = p.AddressRepository().Store(address)
+		if err != nil {
+			return fmt.Errorf("failed to store address: %w", err)
+		}
+
+		addressID = address.ID
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"userID": userID,
+		}).Error("failed to add address")
+		return uuid.Nil, err
+	}
+	return addressID, nil
+}
+
+func (s *AddressService) Update(userID, addressID uuid.UUID, postalAddress domain.PostalAddress, isDefault bool) error {
+	if !postalAddress.IsValid() {
+		return ErrInvalidAddress
+	}
+
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		addresses, err := p.AddressRepository().FindByUserID(userID)
+		if err != nil {
+			return fmt.Errorf("failed to find user addresses: %w", err)
+		}
+
+		address := findAddress(addresses, addressID)
+		if address == nil {
+			return ErrAddressNotFound
+		}
+
+		address.Address = postalAddress
+		if isDefault && !address.IsDefault {
+			err = resetDefaultAddress(addresses, p.AddressRepository())
+			if err != nil {
+				return err
+			}
+			address.IsDefault = true
+		}
+
+		err = p.AddressRepository().Store(address)
+		if err != nil {
+			return fmt.Errorf("failed to store address: %w", err)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrAddressNotFound) {
+		s.logger.WithError(err).With(log.Fields{
+			"userID":    userID,
+			"addressID": addressID,
+		}).Error("failed to update address")
+	}
+	return err
+}
+
+func (s *AddressService) Delete(userID, addressID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		addresses, err := p.AddressRepository().FindByUserID(userID)
+		if err != nil {
+			return fmt.Errorf("failed to find user addresses: %w", err)
+		}
+
+		address := findAddress(addresses, addressID)
+		if address == nil {
+			return ErrAddressNotFound
+		}
+
+		wasDefault := address.IsDefault
+		address.Deleted = true
+		address.IsDefault = false
+		err = p.AddressRepository().Store(address)
+		if err != nil {
+			return fmt.Errorf("failed to store deleted address: %w", err)
+		}
+		if !wasDefault {
+			return nil
+		}
+
+		for i := range addresses {
+			if addresses[i].ID == addressID {
+				continue
+			}
+			addresses[i].IsDefault = true
+			err = p.AddressRepository().Store(&addresses[i])
+			if err != nil {
+				return fmt.Errorf("failed to store default address: %w", err)
+			}
+			break
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrAddressNotFound) {
+		s.logger.WithError(err).With(log.Fields{
+			"userID":    userID,
+			"addressID": addressID,
+		}).Error("failed to delete address")
+	}
+	return err
+}
+
+func findAddress(addresses []domain.Address, addressID uuid.UUID) *domain.Address {
+	for i := range addresses {
+		if addresses[i].ID == addressID {
+			return &addresses[i]
+		}
+	}
+	return nil
+}
+
+func resetDefaultAddress(addresses []domain.Address, repo domain.AddressRepository) error {
+	for i := range addresses {
+		if !addresses[i].IsDefault {
+			continue
+		}
+		addresses[i].IsDefault = false
+		err := repo.Store(&addresses[i])
+		if err != nil {
+			return fmt.Errorf("failed to reset default address: %w", err)
+		}
+	}
+	return nil
+}
+
+func NewAddressService(ufw persistence.UnitOfWork, logger log.Logger) *AddressService {
+	return &AddressService{
+		ufw:    ufw,
+		logger: logger,
+	}
+}
@@ -0,0 +1,28 @@
+package async
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type DeliveryStatusEvent struct {
+	DeliveryID    uuid.UUID
+	OrderID       uuid.UUID
+	UserID        uuid.UUID
+	CourierID     uuid.UUID
+	PickupPointID uuid.UUID
+	PickupCode    string
+	Reason        string
+	Attempt       int
+	OccurredAt    time.Time
+}
+
+type DeliveryEventAPI interface {
+	NotifyDeliveryAssigned(event *DeliveryStatusEvent) error
+	NotifyDeliveryPickedUp(event *DeliveryStatusEvent) error
+	NotifyDeliveryCompleted(event *DeliveryStatusEvent) error
+	NotifyDeliveryAttemptFailed(event *DeliveryStatusEvent) error
+	NotifyDeliveryFailed(event *DeliveryStatusEvent) error
+	NotifyRedeliveryScheduled(event *DeliveryStatusEvent) error
+	NotifyReadyForPickup(event *DeliveryStatusEvent) error
+}
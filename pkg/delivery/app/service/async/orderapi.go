@@ -1,7 +1,15 @@
 package async
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+)
 
 type OrderAPI interface {
 	NotifyDeliveryScheduled(orderID uuid.UUID) error
+	NotifyDeliveryScheduleRejected(orderID uuid.UUID) error
+	NotifyOrderDelivered(orderID uuid.UUID) error
+	NotifyOrderPartiallyDelivered(orderID uuid.UUID) error
+	NotifyDeliveryFailed(orderID uuid.UUID) error
+	NotifyDeliveryPartiallyFailed(orderID uuid.UUID, failedItems []domain.DeliveryItem) error
 }
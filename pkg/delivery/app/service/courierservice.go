@@ -0,0 +1,360 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
+)
+
+var (
+	ErrInvalidCourier          = errors.New("invalid courier")
+	ErrCourierNotFound         = errors.New("courier not found")
+	ErrCourierUserConflict     = errors.New("user is already registered as other courier")
+	ErrDeliveryNotFound        = errors.New("delivery not found")
+	ErrDeliveryNotAssigned     = errors.New("delivery is not assigned to courier")
+	ErrInvalidDeliveryStatus   = errors.New("invalid delivery status")
+	ErrInvalidFailureReason    = errors.New("invalid failure reason")
+	ErrInvalidEstimatedArrival = errors.New("invalid estimated arrival")
+)
+
+type CourierService struct {
+	ufw         persistence.UnitOfWork
+	maxAttempts int
+	logger      log.Logger
+}
+
+func (s *CourierService) StoreCourier(courier *domain.Courier) (uuid.UUID, error) {
+	if courier.UserID == uuid.Nil || courier.Name == "" || courier.Capacity <= 0 {
+		return uuid.Nil, ErrInvalidCourier
+	}
+
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		_, err := p.ZoneRepository().GetByID(courier.ZoneID)
+		if errors.Is(err, domain.ErrZoneNotFound) {
+			return ErrZoneNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get zone: %w", err)
+		}
+
+		userCourier, err := p.CourierRepository().GetByUserID(courier.UserID)
+		if err != nil && !errors.Is(err, domain.ErrCourierNotFound) {
+			return fmt.Errorf("failed to get courier by user: %w", err)
+		}
+		if err == nil && courier.ID == uuid.Nil {
+			courier.ID = userCourier.ID
+		}
+		if err == nil && userCourier.ID != courier.ID {
+			return ErrCourierUserConflict
+		}
+		if courier.ID == uuid.Nil {
+			courier.ID = p.CourierRepository().NextID()
+		}
+
+		err = p.CourierRepository().Store(courier)
+		if err != nil {
+			return fmt.Errorf("failed to store courier: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrZoneNotFound) || errors.Is(err, ErrCourierUserConflict) {
+		return uuid.Nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"courierID": courier.ID,
+		}).Error("failed to store courier")
+		return uuid.Nil, err
+	}
+	return courier.ID, nil
+}
+
+func (s *CourierService) AssignRoutes() ([]domain.Route, error) {
+	var routes []domain.Route
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		couriers, err := p.CourierRepository().LockActive()
+		if err != nil {
+			return fmt.Errorf("failed to get active couriers: %w", err)
+		}
+		if len(couriers) == 0 {
+			return nil
+		}
+
+		loads := make([]domain.CourierLoad, 0, len(couriers))
+		for _, courier := range couriers {
+			assigned, err := p.DeliveryRepository().CountActiveByCourierID(courier.ID)
+			if err != nil {
+				return fmt.Errorf("failed to count courier deliveries: %w", err)
+			}
+			loads = append(loads, domain.CourierLoad{Courier: courier, Assigned: assigned})
+		}
+
+		deliveries, err := p.DeliveryRepository().LockUnassigned()
+		if err != nil {
+			return fmt.Errorf("failed to get unassigned deliveries: %w", err)
+		}
+
+		deliveryByID := make(map[uuid.UUID]*domain.Delivery, len(deliveries))
+		candidates := make([]domain.RouteCandidate, 0, len(deliveries))
+		for i, delivery := range deliveries {
+			zone, err := domain.FindZoneByAddress(&delivery.Address, p.ZoneRepository())
+			if errors.Is(err, domain.ErrZoneNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to find delivery zone: %w", err)
+			}
+
+			deliveryByID[delivery.ID] = &deliveries[i]
+			candidates = append(candidates, domain.RouteCandidate{
+				DeliveryID: delivery.ID,
+				ZoneID:     zone.ID,
+				SlotID:     delivery.SlotID,
+			})
+		}
+
+		now := time.Now()
+		routes = domain.PlanRoutes(candidates, loads, p.RouteRepository().NextID)
+		for i := range routes {
+			route := &routes[i]
+			route.CreatedAt = now
+			err = p.RouteRepository().Store(route)
+			if err != nil {
+				return fmt.Errorf("failed to store route: %w", err)
+			}
+
+			for _, deliveryID := range route.DeliveryIDs {
+				delivery := deliveryByID[deliveryID]
+				delivery.CourierID = route.CourierID
+				delivery.RouteID = route.ID
+				err = p.DeliveryRepository().Store(delivery)
+				if err != nil {
+					return fmt.Errorf("failed to store delivery: %w", err)
+				}
+
+				err = addTrackingEvent(delivery, domain.TrackingEventCourierAssigned, "", p)
+				if err != nil {
+					return err
+				}
+
+				err = p.DeliveryEventAPI().NotifyDeliveryAssigned(&async.DeliveryStatusEvent{
+					DeliveryID: delivery.ID,
+					OrderID:    delivery.OrderID,
+					CourierID:  route.CourierID,
+					OccurredAt: now,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to notify delivery assigned: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to assign routes")
+		return nil, err
+	}
+	return routes, nil
+}
+
+func (s *CourierService) PickUp(courierUserID, deliveryID uuid.UUID, eta time.Time) error {
+	if !eta.IsZero() && eta.Before(time.Now()) {
+		return ErrInvalidEstimatedArrival
+	}
+
+	return s.updateDeliveryStatus(courierUserID, deliveryID, func(delivery *domain.Delivery, p persistence.PersistentProvider) error {
+		if delivery.Status != domain.DeliveryStatusAwaitingDelivery {
+			return ErrInvalidDeliveryStatus
+		}
+
+		delivery.Status = domain.DeliveryStatusProcessing
+		delivery.EstimatedArrival = eta
+		err := p.DeliveryRepository().Store(delivery)
+		if err != nil {
+			return err
+		}
+
+		err = addTrackingEvent(delivery, domain.TrackingEventPickedUp, "", p)
+		if err != nil {
+			return err
+		}
+		return p.DeliveryEventAPI().NotifyDeliveryPickedUp(newDeliveryStatusEvent(delivery))
+	})
+}
+
+func (s *CourierService) Complete(courierUserID, deliveryID uuid.UUID) error {
+	return s.updateDeliveryStatus(courierUserID, deliveryID, func(delivery *domain.Delivery, p persistence.PersistentProvider) error {
+		if delivery.Status != domain.DeliveryStatusProcessing {
+			return ErrInvalidDeliveryStatus
+		}
+		if delivery.PickupPointID != uuid.Nil {
+			return arriveAtPickupPoint(delivery, p)
+		}
+
+		delivery.Status = domain.DeliveryStatusDelivered
+		err := p.DeliveryRepository().Store(delivery)
+		if err != nil {
+			return err
+		}
+
+		err = addTrackingEvent(delivery, domain.TrackingEventDelivered, "", p)
+		if err != nil {
+			return err
+		}
+
+		err = p.DeliveryEventAPI().NotifyDeliveryCompleted(newDeliveryStatusEvent(delivery))
+		if err != nil {
+			return err
+		}
+		return notifyOrderDeliveryProgress(delivery.OrderID, p)
+	})
+}
+
+func (s *CourierService) Fail(courierUserID, deliveryID uuid.UUID, reason string) error {
+	if reason == "" {
+		return ErrInvalidFailureReason
+	}
+
+	return s.updateDeliveryStatus(courierUserID, deliveryID, func(delivery *domain.Delivery, p persistence.PersistentProvider) error {
+		if delivery.Status != domain.DeliveryStatusAwaitingDelivery && delivery.Status != domain.DeliveryStatusProcessing {
+			return ErrInvalidDeliveryStatus
+		}
+
+		delivery.Attempts++
+		delivery.FailureReason = reason
+		delivery.EstimatedArrival = time.Time{}
+		err := p.DeliveryAttemptRepository().Add(&domain.DeliveryAttempt{
+			DeliveryID:  delivery.ID,
+			Number:      delivery.Attempts,
+			CourierID:   delivery.CourierID,
+			Reason:      reason,
+			AttemptedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if delivery.Attempts < s.maxAttempts {
+			delivery.Status = domain.DeliveryStatusAwaitingRedelivery
+			err = p.DeliveryRepository().Store(delivery)
+			if err != nil {
+				return err
+			}
+
+			err = addTrackingEvent(delivery, domain.TrackingEventAttemptFailed, reason, p)
+			if err != nil {
+				return err
+			}
+			return p.DeliveryEventAPI().NotifyDeliveryAttemptFailed(newDeliveryStatusEvent(delivery))
+		}
+
+		delivery.Status = domain.DeliveryStatusFailed
+		err = p.DeliveryRepository().Store(delivery)
+		if err != nil {
+			return err
+		}
+
+		err = addTrackingEvent(delivery, domain.TrackingEventFailed, reason, p)
+		if err != nil {
+			return err
+		}
+
+		err = p.DeliveryEventAPI().NotifyDeliveryFailed(newDeliveryStatusEvent(delivery))
+		if err != nil {
+			return err
+		}
+		return notifyOrderDeliveryProgress(delivery.OrderID, p)
+	})
+}
+
+func (s *CourierService) updateDeliveryStatus(
+	courierUserID uuid.UUID,
+	deliveryID uuid.UUID,
+	update func(delivery *domain.Delivery, p persistence.PersistentProvider) error,
+) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		courier, err := p.CourierRepository().GetByUserID(courierUserID)
+		if errors.Is(err, domain.ErrCourierNotFound) {
+			return ErrCourierNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get courier: %w", err)
+		}
+
+		delivery, err := p.DeliveryRepository().GetByID(deliveryID)
+		if errors.Is(err, domain.ErrItemNotFound) {
+			return ErrDeliveryNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get delivery: %w", err)
+		}
+		if delivery.CourierID != courier.ID {
+			return ErrDeliveryNotAssigned
+		}
+
+		return update(delivery, p)
+	})
+	if errors.Is(err, ErrCourierNotFound) ||
+		errors.Is(err, ErrDeliveryNotFound) ||
+		errors.Is(err, ErrDeliveryNotAssigned) ||
+		errors.Is(err, ErrInvalidDeliveryStatus) {
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"courierUserID": courierUserID,
+			"deliveryID":    deliveryID,
+		}).Error("failed to update delivery status")
+	}
+	return err
+}
+
+func arriveAtPickupPoint(delivery *domain.Delivery, p persistence.PersistentProvider) error {
+	pickupCode, err := domain.NewPickupCode()
+	if err != nil {
+		return fmt.Errorf("failed to generate pickup code: %w", err)
+	}
+
+	delivery.Status = domain.DeliveryStatusReadyForPickup
+	delivery.PickupCode = pickupCode
+	delivery.ArrivedAt = time.Now()
+	err = p.DeliveryRepository().Store(delivery)
+	if err != nil {
+		return err
+	}
+
+	err = addTrackingEvent(delivery, domain.TrackingEventReadyForPickup, "", p)
+	if err != nil {
+		return err
+	}
+
+	event := newDeliveryStatusEvent(delivery)
+	event.PickupCode = delivery.PickupCode
+	return p.DeliveryEventAPI().NotifyReadyForPickup(event)
+}
+
+func newDeliveryStatusEvent(delivery *domain.Delivery) *async.DeliveryStatusEvent {
+	return &async.DeliveryStatusEvent{
+		DeliveryID:    delivery.ID,
+		OrderID:       delivery.OrderID,
+		UserID:        delivery.UserID,
+		CourierID:     delivery.CourierID,
+		PickupPointID: delivery.PickupPointID,
+		Reason:        delivery.FailureReason,
+		Attempt:       delivery.Attempts,
+		OccurredAt:    time.Now(),
+	}
+}
+
+func NewCourierService(ufw persistence.UnitOfWork, maxAttempts int, logger log.Logger) *CourierService {
+	return &CourierService{
+		ufw:         ufw,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+	}
+}
@@ -6,28 +6,93 @@ import (
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/service/async"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
 )
 
+var (
+	ErrRedeliveryUnavailable = errors.New("redelivery is unavailable")
+	ErrSlotUnavailable       = errors.New("slot is unavailable")
+	ErrInvalidParcelItems    = errors.New("invalid parcel items")
+)
+
+type ScheduleDeliveryData struct {
+	OrderID       uuid.UUID
+	UserID        uuid.UUID
+	AddressID     uuid.UUID
+	PickupPointID uuid.UUID
+	SlotID        uuid.UUID
+	Items         []domain.DeliveryItem
+}
+
 type DeliveryService struct {
 	ufw    persistence.UnitOfWork
 	logger log.Logger
 }
 
-func (s *DeliveryService) Schedule(orderID uuid.UUID, addressID uuid.UUID) error {
+func (s *DeliveryService) Schedule(data *ScheduleDeliveryData) error {
 	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
-		_, err := p.DeliveryRepository().GetByID(orderID)
-		if err == nil {
-			return nil // already created
-		}
-		if !errors.Is(err, domain.ErrItemNotFound) {
+		parcels, err := p.DeliveryRepository().LockByOrderID(data.OrderID)
+		if err != nil {
 			return err
 		}
+		if len(parcels) > 0 {
+			return nil // already created
+		}
 
 		delivery := &domain.Delivery{
-			OrderID: orderID,
+			ID:      p.DeliveryRepository().NextID(),
+			OrderID: data.OrderID,
+			Items:   data.Items,
 			Status:  domain.DeliveryStatusScheduled,
-			Address: s.getAddress(addressID),
+			UserID:  data.UserID,
+			SlotID:  data.SlotID,
+		}
+
+		if data.PickupPointID != uuid.Nil {
+			point, err := p.PickupPointRepository().LockByID(data.PickupPointID)
+			if errors.Is(err, domain.ErrPickupPointNotFound) {
+				return rejectSchedule(delivery, p)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get pickup point: %w", err)
+			}
+
+			occupied, err := p.DeliveryRepository().CountActiveByPickupPointID(point.ID)
+			if err != nil {
+				return fmt.Errorf("failed to count pickup point deliveries: %w", err)
+			}
+			if !point.HasFreeCapacity(occupied) {
+				return rejectSchedule(delivery, p)
+			}
+
+			delivery.PickupPointID = point.ID
+			delivery.Address = point.Address
+		} else {
+			address, err := p.AddressRepository().GetByID(data.AddressID)
+			if errors.Is(err, domain.ErrAddressNotFound) {
+				return rejectSchedule(delivery, p)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get delivery address: %w", err)
+			}
+			if address.UserID != data.UserID {
+				return rejectSchedule(delivery, p)
+			}
+
+			delivery.AddressID = address.ID
+			delivery.Address = address.Address
+		}
+
+		if data.SlotID != uuid.Nil {
+			err = reserveSlot(data.SlotID, &delivery.Address, p)
+			if errors.Is(err, domain.ErrSlotUnavailable) {
+				return rejectSchedule(delivery, p)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to reserve slot: %w", err)
+			}
 		}
 
 		err = p.DeliveryRepository().Store(delivery)
@@ -35,7 +100,12 @@ func (s *DeliveryService) Schedule(orderID uuid.UUID, addressID uuid.UUID) error
 			return fmt.Errorf("failed to store scheduled delivery: %w", err)
 		}
 
-		err = p.OrderAPI().NotifyDeliveryScheduled(orderID)
+		err = addTrackingEvent(delivery, domain.TrackingEventScheduled, "", p)
+		if err != nil {
+			return err
+		}
+
+		err = p.OrderAPI().NotifyDeliveryScheduled(data.OrderID)
 		if err != nil {
 			return fmt.Errorf("failed to store scheduled delivery: %w", err)
 		}
@@ -43,8 +113,10 @@ func (s *DeliveryService) Schedule(orderID uuid.UUID, addressID uuid.UUID) error
 	})
 	if err != nil {
 		s.logger.WithError(err).With(log.Fields{
-			"orderID":   orderID,
-			"addressID": addressID,
+			"orderID":       data.OrderID,
+			"addressID":     data.AddressID,
+			"pickupPointID": data.PickupPointID,
+			"slotID":        data.SlotID,
 		}).Error("failed to schedule delivery")
 	}
 	return err
@@ -52,17 +124,34 @@ func (s *DeliveryService) Schedule(orderID uuid.UUID, addressID uuid.UUID) error
 
 func (s *DeliveryService) CancelSchedule(orderID uuid.UUID) error {
 	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
-		delivery, err := p.DeliveryRepository().GetByID(orderID)
+		parcels, err := p.DeliveryRepository().LockByOrderID(orderID)
 		if err != nil {
 			return err
 		}
 
-		if delivery.Status != domain.DeliveryStatusScheduled {
-			return nil
-		}
+		for i := range parcels {
+			delivery := &parcels[i]
+			if delivery.Status != domain.DeliveryStatusScheduled {
+				continue
+			}
+
+			delivery.Status = domain.DeliveryStatusCancelled
+			err = p.DeliveryRepository().Store(delivery)
+			if err != nil {
+				return err
+			}
+
+			err = addTrackingEvent(delivery, domain.TrackingEventCancelled, "", p)
+			if err != nil {
+				return err
+			}
 
-		delivery.Status = domain.DeliveryStatusCancelled
-		return p.DeliveryRepository().Store(delivery)
+			err = releaseSlot(delivery.SlotID, p)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		s.logger.WithError(err).With(log.Fields{
@@ -74,17 +163,29 @@ func (s *DeliveryService) CancelSchedule(orderID uuid.UUID) error {
 
 func (s *DeliveryService) ProcessDelivery(orderID uuid.UUID) error {
 	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
-		delivery, err := p.DeliveryRepository().GetByID(orderID)
+		parcels, err := p.DeliveryRepository().LockByOrderID(orderID)
 		if err != nil {
 			return err
 		}
 
-		if delivery.Status != domain.DeliveryStatusScheduled {
-			return nil
-		}
+		for i := range parcels {
+			delivery := &parcels[i]
+			if delivery.Status != domain.DeliveryStatusScheduled {
+				continue
+			}
 
-		delivery.Status = domain.DeliveryStatusAwaitingDelivery
-		return p.DeliveryRepository().Store(delivery)
+			delivery.Status = domain.DeliveryStatusAwaitingDelivery
+			err = p.DeliveryRepository().Store(delivery)
+			if err != nil {
+				return err
+			}
+
+			err = addTrackingEvent(delivery, domain.TrackingEventAwaitingDelivery, "", p)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		s.logger.WithError(err).With(log.Fields{
@@ -94,8 +195,309 @@ func (s *DeliveryService) ProcessDelivery(orderID uuid.UUID) error {
 	return err
 }
 
-func (s *DeliveryService) getAddress(_ uuid.UUID) string {
-	return "Санкт-Петербург, пр. Тореза, дом 30, подъезд 1, кв. 10" // TODO: store address from database
+func (s *DeliveryService) SplitParcel(deliveryID uuid.UUID, parcelsItems [][]domain.DeliveryItem) ([]uuid.UUID, error) {
+	var parcelIDs []uuid.UUID
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		delivery, err := p.DeliveryRepository().GetByID(deliveryID)
+		if errors.Is(err, domain.ErrItemNotFound) {
+			return ErrDeliveryNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get delivery: %w", err)
+		}
+
+		parcels, err := p.DeliveryRepository().LockByOrderID(delivery.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to lock order deliveries: %w", err)
+		}
+		delivery = findParcel(parcels, deliveryID)
+		if delivery == nil {
+			return ErrDeliveryNotFound
+		}
+		if delivery.Status != domain.DeliveryStatusScheduled && delivery.Status != domain.DeliveryStatusAwaitingDelivery ||
+			delivery.CourierID != uuid.Nil {
+			return ErrInvalidDeliveryStatus
+		}
+
+		newParcels, err := delivery.Split(parcelsItems, p.DeliveryRepository().NextID)
+		if errors.Is(err, domain.ErrInvalidParcelItems) {
+			return ErrInvalidParcelItems
+		}
+		if err != nil {
+			return err
+		}
+
+		err = p.DeliveryRepository().Store(delivery)
+		if err != nil {
+			return fmt.Errorf("failed to store delivery: %w", err)
+		}
+
+		err = addTrackingEvent(delivery, domain.TrackingEventSplit, "", p)
+		if err != nil {
+			return err
+		}
+
+		parcelIDs = append(parcelIDs, delivery.ID)
+		for i := range newParcels {
+			parcel := &newParcels[i]
+			if parcel.SlotID != uuid.Nil {
+				err = reserveSlot(parcel.SlotID, &parcel.Address, p)
+				if errors.Is(err, domain.ErrSlotUnavailable) {
+					return ErrSlotUnavailable
+				}
+				if err != nil {
+					return fmt.Errorf("failed to reserve slot: %w", err)
+				}
+			}
+
+			err = p.DeliveryRepository().Store(parcel)
+			if err != nil {
+				return fmt.Errorf("failed to store parcel: %w", err)
+			}
+
+			err = addTrackingEvent(parcel, domain.TrackingEventSplit, "", p)
+			if err != nil {
+				return err
+			}
+			parcelIDs = append(parcelIDs, parcel.ID)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrDeliveryNotFound) ||
+		errors.Is(err, ErrInvalidDeliveryStatus) ||
+		errors.Is(err, ErrInvalidParcelItems) ||
+		errors.Is(err, ErrSlotUnavailable) {
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"deliveryID": deliveryID,
+		}).Error("failed to split parcel")
+		return nil, err
+	}
+	return parcelIDs, nil
+}
+
+func (s *DeliveryService) ScheduleRedelivery(userID, deliveryID, slotID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		delivery, err := p.DeliveryRepository().GetByID(deliveryID)
+		if errors.Is(err, domain.ErrItemNotFound) {
+			return ErrDeliveryNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get delivery: %w", err)
+		}
+
+		isOwner, err := isDeliveryOwner(delivery, userID, p)
+		if err != nil {
+			return err
+		}
+		if !isOwner {
+			return ErrDeliveryNotFound
+		}
+		if delivery.Status != domain.DeliveryStatusAwaitingRedelivery {
+			return ErrRedeliveryUnavailable
+		}
+
+		if slotID != uuid.Nil {
+			err = reserveSlot(slotID, &delivery.Address, p)
+			if errors.Is(err, domain.ErrSlotUnavailable) {
+				return ErrSlotUnavailable
+			}
+			if err != nil {
+				return fmt.Errorf("failed to reserve slot: %w", err)
+			}
+		}
+
+		delivery.Status = domain.DeliveryStatusAwaitingDelivery
+		delivery.SlotID = slotID
+		delivery.CourierID = uuid.Nil
+		delivery.RouteID = uuid.Nil
+		delivery.EstimatedArrival = time.Time{}
+		err = p.DeliveryRepository().Store(delivery)
+		if err != nil {
+			return fmt.Errorf("failed to store delivery: %w", err)
+		}
+
+		err = addTrackingEvent(delivery, domain.TrackingEventRedeliveryScheduled, "", p)
+		if err != nil {
+			return err
+		}
+
+		err = p.DeliveryEventAPI().NotifyRedeliveryScheduled(&async.DeliveryStatusEvent{
+			DeliveryID: delivery.ID,
+			OrderID:    delivery.OrderID,
+			Attempt:    delivery.Attempts,
+			OccurredAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to notify redelivery scheduled: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrDeliveryNotFound) || errors.Is(err, ErrRedeliveryUnavailable) || errors.Is(err, ErrSlotUnavailable) {
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"deliveryID": deliveryID,
+			"slotID":     slotID,
+		}).Error("failed to schedule redelivery")
+	}
+	return err
+}
+
+func notifyOrderDeliveryProgress(orderID uuid.UUID, p persistence.PersistentProvider) error {
+	parcels, err := p.DeliveryRepository().LockByOrderID(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to lock order deliveries: %w", err)
+	}
+
+	var active, delivered, failed int
+	var failedItems []domain.DeliveryItem
+	for _, parcel := range parcels {
+		switch {
+		case parcel.IsActive():
+			active++
+		case parcel.Status == domain.DeliveryStatusDelivered:
+			delivered++
+		case parcel.Status == domain.DeliveryStatusFailed:
+			failed++
+			failedItems = appendDeliveryItems(failedItems, parcel.Items)
+		}
+	}
+
+	switch {
+	case active == 0 && failed == 0 && delivered > 0:
+		err = p.OrderAPI().NotifyOrderDelivered(orderID)
+	case active == 0 && delivered == 0 && failed > 0:
+		err = p.OrderAPI().NotifyDeliveryFailed(orderID)
+	case delivered > 0:
+		err = p.OrderAPI().NotifyOrderPartiallyDelivered(orderID)
+		if err == nil && active == 0 && failed > 0 {
+			err = p.OrderAPI().NotifyDeliveryPartiallyFailed(orderID, failedItems)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to notify order delivery progress: %w", err)
+	}
+	return nil
+}
+
+func appendDeliveryItems(items []domain.DeliveryItem, added []domain.DeliveryItem) []domain.DeliveryItem {
+	for _, addedItem := range added {
+		found := false
+		for i := range items {
+			if items[i].ProductID == addedItem.ProductID {
+				items[i].Quantity += addedItem.Quantity
+				found = true
+				break
+			}
+		}
+		if !found {
+			items = append(items, addedItem)
+		}
+	}
+	return items
+}
+
+func findParcel(parcels []domain.Delivery, deliveryID uuid.UUID) *domain.Delivery {
+	for i := range parcels {
+		if parcels[i].ID == deliveryID {
+			return &parcels[i]
+		}
+	}
+	return nil
+}
+
+func isDeliveryOwner(delivery *domain.Delivery, userID uuid.UUID, p persistence.PersistentProvider) (bool, error) {
+	if delivery.UserID != uuid.Nil {
+		return delivery.UserID == userID, nil
+	}
+	if delivery.AddressID == uuid.Nil {
+		return false, nil
+	}
+
+	address, err := p.AddressRepository().GetByID(delivery.AddressID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get delivery address: %w", err)
+	}
+	return address.UserID == userID, nil
+}
+
+func reserveSlot(slotID uuid.UUID, address *domain.PostalAddress, p persistence.PersistentProvider) error {
+	slot, err := p.SlotRepository().LockByID(slotID)
+	if errors.Is(err, domain.ErrSlotNotFound) {
+		return domain.ErrSlotUnavailable
+	}
+	if err != nil {
+		return err
+	}
+
+	zone, err := domain.FindZoneByAddress(address, p.ZoneRepository())
+	if errors.Is(err, domain.ErrZoneNotFound) {
+		return domain.ErrSlotUnavailable
+	}
+	if err != nil {
+		return err
+	}
+	if zone.ID != slot.ZoneID {
+		return domain.ErrSlotUnavailable
+	}
+
+	err = slot.Reserve(time.Now())
+	if err != nil {
+		return err
+	}
+	return p.SlotRepository().Store(slot)
+}
+
+func releaseSlot(slotID uuid.UUID, p persistence.PersistentProvider) error {
+	if slotID == uuid.Nil {
+		return nil
+	}
+
+	slot, err := p.SlotRepository().LockByID(slotID)
+	if err != nil {
+		return err
+	}
+
+	slot.Release()
+	return p.SlotRepository().Store(slot)
+}
+
+func rejectSchedule(delivery *domain.Delivery, p persistence.PersistentProvider) error {
+	delivery.Status = domain.DeliveryStatusCancelled
+	delivery.SlotID = uuid.Nil
+	err := p.DeliveryRepository().Store(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to store rejected delivery: %w", err)
+	}
+
+	err = addTrackingEvent(delivery, domain.TrackingEventCancelled, "", p)
+	if err != nil {
+		return err
+	}
+
+	err = p.OrderAPI().NotifyDeliveryScheduleRejected(delivery.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to notify delivery schedule rejected: %w", err)
+	}
+	return nil
+}
+
+func addTrackingEvent(delivery *domain.Delivery, typ domain.TrackingEventType, details string, p persistence.PersistentProvider) error {
+	err := p.TrackingEventRepository().Add(&domain.TrackingEvent{
+		OrderID:    delivery.OrderID,
+		DeliveryID: delivery.ID,
+		Type:       typ,
+		Details:    details,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add tracking event: %w", err)
+	}
+	return nil
 }
 
 func NewDeliveryService(ufw persistence.UnitOfWork, logger log.Logger) *DeliveryService {
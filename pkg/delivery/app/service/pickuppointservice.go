@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
+)
+
+const notCollectedFailureReason = "not_collected"
+
+var (
+	ErrInvalidPickupPoint  = errors.New("invalid pickup point")
+	ErrPickupPointNotFound = errors.New("pickup point not found")
+	ErrInvalidPickupCode   = errors.New("invalid pickup code")
+)
+
+type PickupPointService struct {
+	ufw           persistence.UnitOfWork
+	storagePeriod time.Duration
+	logger        log.Logger
+}
+
+func (s *PickupPointService) StorePickupPoint(point *domain.PickupPoint) (uuid.UUID, error) {
+	if !isPickupPointValid(point) {
+		return uuid.Nil, ErrInvalidPickupPoint
+	}
+
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		if point.ID == uuid.Nil {
+			point.ID = p.PickupPointRepository().NextID()
+		} else {
+			_, err := p.PickupPointRepository().LockByID(point.ID)
+			if errors.Is(err, domain.ErrPickupPointNotFound) {
+				return ErrPickupPointNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get pickup point: %w", err)
+			}
+		}
+
+		err := p.PickupPointRepository().Store(point)
+		if err != nil {
+			return fmt.Errorf("failed to store pickup point: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrPickupPointNotFound) {
+		return uuid.Nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"pickupPointID": point.ID,
+		}).Error("failed to store pickup point")
+		return uuid.Nil, err
+	}
+	return point.ID, nil
+}
+
+func (s *PickupPointService) HandOver(pickupPointID, deliveryID uuid.UUID, pickupCode string) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		delivery, err := p.DeliveryRepository().GetByID(deliveryID)
+		if errors.Is(err, domain.ErrItemNotFound) {
+			return ErrDeliveryNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get delivery: %w", err)
+		}
+		if delivery.PickupPointID != pickupPointID {
+			return ErrDeliveryNotFound
+		}
+		if delivery.Status != domain.DeliveryStatusReadyForPickup {
+			return ErrInvalidDeliveryStatus
+		}
+		if delivery.PickupCode != pickupCode {
+			return ErrInvalidPickupCode
+		}
+
+		delivery.Status = domain.DeliveryStatusDelivered
+		err = p.DeliveryRepository().Store(delivery)
+		if err != nil {
+			return fmt.Errorf("failed to store delivery: %w", err)
+		}
+
+		err = addTrackingEvent(delivery, domain.TrackingEventDelivered, "", p)
+		if err != nil {
+			return err
+		}
+
+		err = p.DeliveryEventAPI().NotifyDeliveryCompleted(newDeliveryStatusEvent(delivery))
+		if err != nil {
+			return fmt.Errorf("failed to notify delivery completed: %w", err)
+		}
+
+		return notifyOrderDeliveryProgress(delivery.OrderID, p)
+	})
+	if errors.Is(err, ErrDeliveryNotFound) || errors.Is(err, ErrInvalidDeliveryStatus) || errors.Is(err, ErrInvalidPickupCode) {
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"pickupPointID": pickupPointID,
+			"deliveryID":    deliveryID,
+		}).Error("failed to hand over delivery")
+	}
+	return err
+}
+
+func (s *PickupPointService) ReturnUncollected() ([]uuid.UUID, error) {
+	var deliveryIDs []uuid.UUID
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		deliveries, err := p.DeliveryRepository().LockUncollected(time.Now().Add(-s.storagePeriod))
+		if err != nil {
+			return fmt.Errorf("failed to get uncollected deliveries: %w", err)
+		}
+
+		for i := range deliveries {
+			delivery := &deliveries[i]
+			delivery.Status = domain.DeliveryStatusFailed
+			delivery.FailureReason = notCollectedFailureReason
+			err = p.DeliveryRepository().Store(delivery)
+			if err != nil {
+				return fmt.Errorf("failed to store delivery: %w", err)
+			}
+
+			err = addTrackingEvent(delivery, domain.TrackingEventFailed, notCollectedFailureReason, p)
+			if err != nil {
+				return err
+			}
+
+			err = p.DeliveryEventAPI().NotifyDeliveryFailed(newDeliveryStatusEvent(delivery))
+			if err != nil {
+				return fmt.Errorf("failed to notify delivery failed: %w", err)
+			}
+
+			err = notifyOrderDeliveryProgress(delivery.OrderID, p)
+			if err != nil {
+				return err
+			}
+			deliveryIDs = append(deliveryIDs, delivery.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to return uncollected deliveries")
+		return nil, err
+	}
+	return deliveryIDs, nil
+}
+
+func isPickupPointValid(point *domain.PickupPoint) bool {
+	if point.Name == "" || !point.Address.IsValid() || point.Capacity <= 0 {
+		return false
+	}
+
+	weekdays := make(map[time.Weekday]struct{}, len(point.OpeningHours))
+	for _, hours := range point.OpeningHours {
+		if !hours.IsValid() {
+			return false
+		}
+		if _, ok := weekdays[hours.Weekday]; ok {
+			return false
+		}
+		weekdays[hours.Weekday] = struct{}{}
+	}
+	return true
+}
+
+func NewPickupPointService(ufw persistence.UnitOfWork, storagePeriod time.Duration, logger log.Logger) *PickupPointService {
+	return &PickupPointService{
+		ufw:           ufw,
+		storagePeriod: storagePeriod,
+		logger:        logger,
+	}
+}
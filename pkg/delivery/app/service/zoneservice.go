@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
+)
+
+var (
+	ErrInvalidZone           = errors.New("invalid zone")
+	ErrInvalidSlot           = errors.New("invalid slot")
+	ErrZoneNotFound          = errors.New("zone not found")
+	ErrCityInOtherZone       = errors.New("city belongs to other zone")
+	ErrPostalCodeInOtherZone = errors.New("postal code belongs to other zone")
+	ErrShippingUnavailable   = errors.New("shipping is unavailable")
+)
+
+type ShippingQuote struct {
+	ZoneID uuid.UUID
+	Price  int
+}
+
+type ZoneService struct {
+	ufw    persistence.UnitOfWork
+	logger log.Logger
+}
+
+func (s *ZoneService) StoreZone(zone *domain.Zone) (uuid.UUID, error) {
+	if !isZoneValid(zone) {
+		return uuid.Nil, ErrInvalidZone
+	}
+
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		if zone.ID == uuid.Nil {
+			zone.ID = p.ZoneRepository().NextID()
+		}
+
+		for _, city := range zone.Cities {
+			cityZone, err := p.ZoneRepository().FindByCity(city)
+			if errors.Is(err, domain.ErrZoneNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to find zone by city: %w", err)
+			}
+			if cityZone.ID != zone.ID {
+				return ErrCityInOtherZone
+			}
+		}
+
+		for _, postalCodeRange := range zone.PostalCodeRanges {
+			for _, postalCode := range []string{postalCodeRange.From, postalCodeRange.To} {
+				postalCodeZone, err := p.ZoneRepository().FindByPostalCode(postalCode)
+				if errors.Is(err, domain.ErrZoneNotFound) {
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to find zone by postal code: %w", err)
+				}
+				if postalCodeZone.ID != zone.ID {
+					return ErrPostalCodeInOtherZone
+				}
+			}
+		}
+
+		err := p.ZoneRepository().Store(zone)
+		if err != nil {
+			return fmt.Errorf("failed to store zone: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrCityInOtherZone) || errors.Is(err, ErrPostalCodeInOtherZone) {
+		return uuid.Nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"zoneID": zone.ID,
+		}).Error("failed to store zone")
+		return uuid.Nil, err
+	}
+	return zone.ID, nil
+}
+
+func (s *ZoneService) AddSlot(zoneID uuid.UUID, startsAt, endsAt time.Time, capacity int) (uuid.UUID, error) {
+	if !endsAt.After(startsAt) || capacity <= 0 {
+		return uuid.Nil, ErrInvalidSlot
+	}
+
+	var slotID uuid.UUID
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		_, err := p.ZoneRepository().GetByID(zoneID)
+		if errors.Is(err, domain.ErrZoneNotFound) {
+			return ErrZoneNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get zone: %w", err)
+		}
+
+		slot := &domain.Slot{
+			ID:       p.SlotRepository().NextID(),
+			ZoneID:   zoneID,
+			StartsAt: startsAt,
+			EndsAt:   endsAt,
+			Capacity: capacity,
+		}
+		err = p.SlotRepository().Store(slot)
+		if err != nil {
+			return fmt.Errorf("failed to store slot: %w", err)
+		}
+
+		slotID = slot.ID
+		return nil
+	})
+	if errors.Is(err, ErrZoneNotFound) {
+		return uuid.Nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"zoneID": zoneID,
+		}).Error("failed to add slot")
+		return uuid.Nil, err
+	}
+	return slotID, nil
+}
+
+func (s *ZoneService) QuoteShipping(userID, addressID uuid.UUID, weight, orderAmount int) (*ShippingQuote, error) {
+	var quote *ShippingQuote
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		address, err := p.AddressRepository().GetByID(addressID)
+		if errors.Is(err, domain.ErrAddressNotFound) {
+			return ErrAddressNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get address: %w", err)
+		}
+		if address.UserID != userID || address.Deleted {
+			return ErrAddressNotFound
+		}
+
+		quote, err = quoteShipping(&address.Address, weight, orderAmount, p)
+		return err
+	})
+	if errors.Is(err, ErrAddressNotFound) || errors.Is(err, ErrShippingUnavailable) {
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"userID":    userID,
+			"addressID": addressID,
+		}).Error("failed to quote shipping")
+		return nil, err
+	}
+	return quote, nil
+}
+
+func (s *ZoneService) QuotePickupShipping(pickupPointID uuid.UUID, weight, orderAmount int) (*ShippingQuote, error) {
+	var quote *ShippingQuote
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		point, err := p.PickupPointRepository().GetByID(pickupPointID)
+		if errors.Is(err, domain.ErrPickupPointNotFound) {
+			return ErrPickupPointNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get pickup point: %w", err)
+		}
+
+		occupied, err := p.DeliveryRepository().CountActiveByPickupPointID(point.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count pickup point deliveries: %w", err)
+		}
+		if !point.HasFreeCapacity(occupied) {
+			return ErrShippingUnavailable
+		}
+
+		quote, err = quoteShipping(&point.Address, weight, orderAmount, p)
+		return err
+	})
+	if errors.Is(err, ErrPickupPointNotFound) || errors.Is(err, ErrShippingUnavailable) {
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"pickupPointID": pickupPointID,
+		}).Error("failed to quote pickup shipping")
+		return nil, err
+	}
+	return quote, nil
+}
+
+func quoteShipping(address *domain.PostalAddress, weight, orderAmount int, p persistence.PersistentProvider) (*ShippingQuote, error) {
+	zone, err := domain.FindZoneByAddress(address, p.ZoneRepository())
+	if errors.Is(err, domain.ErrZoneNotFound) {
+		return nil, ErrShippingUnavailable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find zone: %w", err)
+	}
+
+	price, err := zone.ShippingCost(weight, orderAmount)
+	if errors.Is(err, domain.ErrShippingUnavailable) {
+		return nil, ErrShippingUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShippingQuote{
+		ZoneID: zone.ID,
+		Price:  price,
+	}, nil
+}
+
+func isZoneValid(zone *domain.Zone) bool {
+	if zone.Name == "" || (len(zone.Cities) == 0 && len(zone.PostalCodeRanges) == 0) || zone.FreeShippingAmount < 0 {
+		return false
+	}
+	for _, postalCodeRange := range zone.PostalCodeRanges {
+		if !postalCodeRange.IsValid() {
+			return false
+		}
+	}
+	for _, rate := range zone.Rates {
+		if !rate.IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+func NewZoneService(ufw persistence.UnitOfWork, logger log.Logger) *ZoneService {
+	return &ZoneService{
+		ufw:    ufw,
+		logger: logger,
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+)
+
+type PostalAddress struct {
+	Country    string
+	City       string
+	Street     string
+	Building   string
+	Apartment  string
+	PostalCode string
+	Recipient  string
+	Phone      string
+}
+
+func (a *PostalAddress) IsValid() bool {
+	return a.Country != "" &&
+		a.City != "" &&
+		a.Street != "" &&
+		a.Building != "" &&
+		a.Recipient != "" &&
+		a.Phone != ""
+}
+
+type Address struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Address   PostalAddress
+	IsDefault bool
+	Deleted   bool
+}
+
+var ErrAddressNotFound = errors.New("address not found")
+
+type AddressRepository interface {
+	NextID() uuid.UUID
+	GetByID(id uuid.UUID) (*Address, error)
+	FindByUserID(userID uuid.UUID) ([]Address, error)
+	Store(address *Address) error
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Courier struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	Name     string
+	Phone    string
+	ZoneID   uuid.UUID
+	Capacity int
+	Active   bool
+}
+
+var ErrCourierNotFound = errors.New("courier not found")
+
+type CourierRepository interface {
+	NextID() uuid.UUID
+	GetByID(id uuid.UUID) (*Courier, error)
+	GetByUserID(userID uuid.UUID) (*Courier, error)
+	LockActive() ([]Courier, error)
+	Store(courier *Courier) error
+}
+
+type Route struct {
+	ID          uuid.UUID
+	CourierID   uuid.UUID
+	ZoneID      uuid.UUID
+	SlotID      uuid.UUID
+	DeliveryIDs []uuid.UUID
+	CreatedAt   time.Time
+}
+
+type RouteRepository interface {
+	NextID() uuid.UUID
+	Store(route *Route) error
+}
+
+type RouteCandidate struct {
+	DeliveryID uuid.UUID
+	ZoneID     uuid.UUID
+	SlotID     uuid.UUID
+}
+
+type CourierLoad struct {
+	Courier  Courier
+	Assigned int
+}
+
+func (l *CourierLoad) FreeCapacity() int {
+	return l.Courier.Capacity - l.Assigned
+}
+
+func PlanRoutes(candidates []RouteCandidate, loads []CourierLoad, nextRouteID func() uuid.UUID) []Route {
+	type routeKey struct{ ZoneID, SlotID uuid.UUID }
+
+	var keys []routeKey
+	groups := make(map[routeKey][]uuid.UUID)
+	for _, candidate := range candidates {
+		key := routeKey{candidate.ZoneID, candidate.SlotID}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], candidate.DeliveryID)
+	}
+
+	var routes []Route
+	for _, key := range keys {
+		deliveryIDs := groups[key]
+		for len(deliveryIDs) > 0 {
+			load := findFreestCourier(key.ZoneID, loads)
+			if load == nil {
+				break
+			}
+
+			count := load.FreeCapacity()
+			if count > len(deliveryIDs) {
+				count = len(deliveryIDs)
+			}
+
+			routes = append(routes, Route{
+				ID:          nextRouteID(),
+				CourierID:   load.Courier.ID,
+				ZoneID:      key.ZoneID,
+				SlotID:      key.SlotID,
+				DeliveryIDs: deliveryIDs[:count],
+			})
+			load.Assigned += count
+			deliveryIDs = deliveryIDs[count:]
+		}
+	}
+	return routes
+}
+
+func findFreestCourier(zoneID uuid.UUID, loads []CourierLoad) *CourierLoad {
+	var result *CourierLoad
+	for i := range loads {
+		load := &loads[i]
+		if !load.Courier.Active || load.Courier.ZoneID != zoneID || load.FreeCapacity() <= 0 {
+			continue
+		}
+		if result == nil || load.FreeCapacity() > result.FreeCapacity() {
+			result = load
+		}
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"github.com/google/uuid"
+	"time"
 )
 
 type DeliveryStatus int
@@ -13,17 +14,107 @@ const (
 	DeliveryStatusProcessing
 	DeliveryStatusDelivered
 	DeliveryStatusCancelled
+	DeliveryStatusFailed
+	DeliveryStatusAwaitingRedelivery
+	DeliveryStatusReadyForPickup
 )
 
+type DeliveryItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
 type Delivery struct {
-	OrderID uuid.UUID
-	Status  DeliveryStatus
-	Address string
+	ID               uuid.UUID
+	OrderID          uuid.UUID
+	Items            []DeliveryItem
+	Status           DeliveryStatus
+	UserID           uuid.UUID
+	SlotID           uuid.UUID
+	AddressID        uuid.UUID
+	PickupPointID    uuid.UUID
+	PickupCode       string
+	ArrivedAt        time.Time
+	Address          PostalAddress
+	CourierID        uuid.UUID
+	RouteID          uuid.UUID
+	FailureReason    string
+	Attempts         int
+	EstimatedArrival time.Time
+}
+
+type DeliveryAttempt struct {
+	DeliveryID  uuid.UUID
+	Number      int
+	CourierID   uuid.UUID
+	Reason      string
+	AttemptedAt time.Time
 }
 
-var ErrItemNotFound = errors.New("item not found")
+var (
+	ErrItemNotFound       = errors.New("item not found")
+	ErrInvalidParcelItems = errors.New("invalid parcel items")
+)
+
+func (d *Delivery) IsActive() bool {
+	return d.Status != DeliveryStatusDelivered &&
+		d.Status != DeliveryStatusCancelled &&
+		d.Status != DeliveryStatusFailed
+}
+
+func (d *Delivery) Split(parcelsItems [][]DeliveryItem, nextID func() uuid.UUID) ([]Delivery, error) {
+	if len(parcelsItems) < 2 {
+		return nil, ErrInvalidParcelItems
+	}
+
+	remaining := make(map[uuid.UUID]int, len(d.Items))
+	for _, item := range d.Items {
+		remaining[item.ProductID] += item.Quantity
+	}
+	for _, items := range parcelsItems {
+		if len(items) == 0 {
+			return nil, ErrInvalidParcelItems
+		}
+		seen := make(map[uuid.UUID]struct{}, len(items))
+		for _, item := range items {
+			if _, ok := seen[item.ProductID]; ok {
+				return nil, ErrInvalidParcelItems
+			}
+			if item.Quantity <= 0 || remaining[item.ProductID] < item.Quantity {
+				return nil, ErrInvalidParcelItems
+			}
+			seen[item.ProductID] = struct{}{}
+			remaining[item.ProductID] -= item.Quantity
+		}
+	}
+	for _, quantity := range remaining {
+		if quantity != 0 {
+			return nil, ErrInvalidParcelItems
+		}
+	}
+
+	d.Items = parcelsItems[0]
+	parcels := make([]Delivery, 0, len(parcelsItems)-1)
+	for _, items := range parcelsItems[1:] {
+		parcel := *d
+		parcel.ID = nextID()
+		parcel.Items = items
+		parcels = append(parcels, parcel)
+	}
+	return parcels, nil
+}
 
 type DeliveryRepository interface {
-	GetByID(orderID uuid.UUID) (*Delivery, error)
+	NextID() uuid.UUID
+	GetByID(id uuid.UUID) (*Delivery, error)
+	LockByOrderID(orderID uuid.UUID) ([]Delivery, error)
+	LockUnassigned() ([]Delivery, error)
+	CountActiveByCourierID(courierID uuid.UUID) (int, error)
+	CountActiveByPickupPointID(pickupPointID uuid.UUID) (int, error)
+	LockUncollected(arrivedBefore time.Time) ([]Delivery, error)
 	Store(d *Delivery) error
 }
+
+type DeliveryAttemptRepository interface {
+	Add(attempt *DeliveryAttempt) error
+}
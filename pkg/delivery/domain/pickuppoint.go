@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"math/big"
+	"time"
+)
+
+const (
+	openingHoursLayout = "15:04"
+	pickupCodeDigits   = 6
+)
+
+type OpeningHours struct {
+	Weekday time.Weekday
+	Opens   string
+	Closes  string
+}
+
+func (h *OpeningHours) IsValid() bool {
+	if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+		return false
+	}
+	opens, err := time.Parse(openingHoursLayout, h.Opens)
+	if err != nil {
+		return false
+	}
+	closes, err := time.Parse(openingHoursLayout, h.Closes)
+	if err != nil {
+		return false
+	}
+	return opens.Before(closes)
+}
+
+type PickupPoint struct {
+	ID           uuid.UUID
+	Name         string
+	Address      PostalAddress
+	OpeningHours []OpeningHours
+	Capacity     int
+	Active       bool
+}
+
+var ErrPickupPointNotFound = errors.New("pickup point not found")
+
+func (p *PickupPoint) HasFreeCapacity(occupied int) bool {
+	return p.Active && occupied < p.Capacity
+}
+
+type PickupPointRepository interface {
+	NextID() uuid.UUID
+	GetByID(id uuid.UUID) (*PickupPoint, error)
+	LockByID(id uuid.UUID) (*PickupPoint, error)
+	Store(point *PickupPoint) error
+}
+
+func NewPickupCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < pickupCodeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", pickupCodeDigits, n), nil
+}
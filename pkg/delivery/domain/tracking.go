@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TrackingEventType int
+
+const (
+	TrackingEventScheduled TrackingEventType = iota
+	TrackingEventAwaitingDelivery
+	TrackingEventCourierAssigned
+	TrackingEventPickedUp
+	TrackingEventDelivered
+	TrackingEventAttemptFailed
+	TrackingEventRedeliveryScheduled
+	TrackingEventFailed
+	TrackingEventCancelled
+	TrackingEventReadyForPickup
+	TrackingEventSplit
+)
+
+type TrackingEvent struct {
+	OrderID    uuid.UUID
+	DeliveryID uuid.UUID
+	Type       TrackingEventType
+	Details    string
+	OccurredAt time.Time
+}
+
+type TrackingEventRepository interface {
+	Add(event *TrackingEvent) error
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PostalCodeRange struct {
+	From string
+	To   string
+}
+
+func (r *PostalCodeRange) IsValid() bool {
+	return r.From != "" && len(r.From) == len(r.To) && r.From <= r.To
+}
+
+func (r *PostalCodeRange) Contains(postalCode string) bool {
+	return len(postalCode) == len(r.From) && r.From <= postalCode && postalCode <= r.To
+}
+
+type ShippingRate struct {
+	MaxWeight      int
+	MinOrderAmount int
+	Price          int
+}
+
+func (r *ShippingRate) IsValid() bool {
+	return r.MaxWeight >= 0 && r.MinOrderAmount >= 0 && r.Price >= 0
+}
+
+func (r *ShippingRate) Matches(weight, orderAmount int) bool {
+	return (r.MaxWeight == 0 || weight <= r.MaxWeight) && orderAmount >= r.MinOrderAmount
+}
+
+type Zone struct {
+	ID                 uuid.UUID
+	Name               string
+	Cities             []string
+	PostalCodeRanges   []PostalCodeRange
+	Rates              []ShippingRate
+	FreeShippingAmount int
+}
+
+var (
+	ErrZoneNotFound        = errors.New("zone not found")
+	ErrShippingUnavailable = errors.New("shipping is unavailable")
+)
+
+func (z *Zone) ShippingCost(weight, orderAmount int) (int, error) {
+	if z.FreeShippingAmount > 0 && orderAmount >= z.FreeShippingAmount {
+		return 0, nil
+	}
+
+	cost := -1
+	for _, rate := range z.Rates {
+		if rate.Matches(weight, orderAmount) && (cost < 0 || rate.Price < cost) {
+			cost = rate.Price
+		}
+	}
+	if cost < 0 {
+		return 0, ErrShippingUnavailable
+	}
+	return cost, nil
+}
+
+type ZoneRepository interface {
+	NextID() uuid.UUID
+	GetByID(id uuid.UUID) (*Zone, error)
+	FindByCity(city string) (*Zone, error)
+	FindByPostalCode(postalCode string) (*Zone, error)
+	Store(zone *Zone) error
+}
+
+func FindZoneByAddress(address *PostalAddress, repo ZoneRepository) (*Zone, error) {
+	if address.PostalCode != "" {
+		zone, err := repo.FindByPostalCode(address.PostalCode)
+		if !errors.Is(err, ErrZoneNotFound) {
+			return zone, err
+		}
+	}
+	return repo.FindByCity(address.City)
+}
+
+type Slot struct {
+	ID       uuid.UUID
+	ZoneID   uuid.UUID
+	StartsAt time.Time
+	EndsAt   time.Time
+	Capacity int
+	Reserved int
+}
+
+var (
+	ErrSlotNotFound    = errors.New("slot not found")
+	ErrSlotUnavailable = errors.New("slot is unavailable")
+)
+
+func (s *Slot) Reserve(now time.Time) error {
+	if !s.StartsAt.After(now) || s.Reserved >= s.Capacity {
+		return ErrSlotUnavailable
+	}
+	s.Reserved++
+	return nil
+}
+
+func (s *Slot) Release() {
+	if s.Reserved > 0 {
+		s.Reserved--
+	}
+}
+
+type SlotRepository interface {
+	NextID() uuid.UUID
+	LockByID(id uuid.UUID) (*Slot, error)
+	Store(slot *Slot) error
+}
@@ -0,0 +1,93 @@
+package deliveryeventapi
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/service/async"
+	"time"
+)
+
+const deliveryStatusEventTopicName = "delivery_status_event"
+
+type api struct {
+	eventDispatcher event.Dispatcher
+}
+
+func (a *api) NotifyDeliveryAssigned(e *async.DeliveryStatusEvent) error {
+	return a.dispatch("delivery_assigned", e)
+}
+
+func (a *api) NotifyDeliveryPickedUp(e *async.DeliveryStatusEvent) error {
+	return a.dispatch("delivery_picked_up", e)
+}
+
+func (a *api) NotifyDeliveryCompleted(e *async.DeliveryStatusEvent) error {
+	return a.dispatch("delivery_completed", e)
+}
+
+func (a *api) NotifyDeliveryAttemptFailed(e *async.DeliveryStatusEvent) error {
+	return a.dispatch("delivery_attempt_failed", e)
+}
+
+func (a *api) NotifyRedeliveryScheduled(e *async.DeliveryStatusEvent) error {
+	return a.dispatch("redelivery_scheduled", e)
+}
+
+func (a *api) NotifyDeliveryFailed(e *async.DeliveryStatusEvent) error {
+	return a.dispatch("delivery_failed", e)
+}
+
+func (a *api) NotifyReadyForPickup(e *async.DeliveryStatusEvent) error {
+	return a.dispatch("delivery_ready_for_pickup", e)
+}
+
+func (a *api) dispatch(eventType string, e *async.DeliveryStatusEvent) error {
+	body, err := json.Marshal(struct {
+		DeliveryID    uuid.UUID  `json:"delivery_id"`
+		OrderID       uuid.UUID  `json:"order_id"`
+		UserID        *uuid.UUID `json:"user_id,omitempty"`
+		CourierID     uuid.UUID  `json:"courier_id"`
+		PickupPointID *uuid.UUID `json:"pickup_point_id,omitempty"`
+		PickupCode    string     `json:"pickup_code,omitempty"`
+		Reason        string     `json:"reason,omitempty"`
+		Attempt       int        `json:"attempt,omitempty"`
+		OccurredAt    time.Time  `json:"occurred_at"`
+	}{
+		DeliveryID:    e.DeliveryID,
+		OrderID:       e.OrderID,
+		UserID:        nullableUUID(e.UserID),
+		CourierID:     e.CourierID,
+		PickupPointID: nullableUUID(e.PickupPointID),
+		PickupCode:    e.PickupCode,
+		Reason:        e.Reason,
+		Attempt:       e.Attempt,
+		OccurredAt:    e.OccurredAt,
+	})
+	if err != nil {
+		return errors.New("failed to encode delivery status event")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      eventType,
+		TopicName: deliveryStatusEventTopicName,
+		Key:       e.OrderID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+func New(eventDispatcher event.Dispatcher) async.DeliveryEventAPI {
+	return &api{eventDispatcher: eventDispatcher}
+}
@@ -0,0 +1,155 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+)
+
+type addressRepo struct {
+	client mysql.Client
+}
+
+func (r *addressRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *addressRepo) GetByID(id uuid.UUID) (*domain.Address, error) {
+	const addressQuery = `
+		SELECT id, user_id, country, city, street, building, apartment, postal_code, recipient, phone, is_default, deleted
+		FROM address
+		WHERE id = ?
+	`
+
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var addressSqlx sqlxAddress
+	err = r.client.Get(&addressSqlx, addressQuery, binaryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return getDomainAddress(&addressSqlx), nil
+}
+
+func (r *addressRepo) FindByUserID(userID uuid.UUID) ([]domain.Address, error) {
+	const addressesQuery = `
+		SELECT id, user_id, country, city, street, building, apartment, postal_code, recipient, phone, is_default, deleted
+		FROM address
+		WHERE user_id = ? AND deleted = FALSE
+		ORDER BY created_at
+		FOR UPDATE
+	`
+
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var addressesSqlx []sqlxAddress
+	err = r.client.Select(&addressesSqlx, addressesQuery, binaryUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Address, 0, len(addressesSqlx))
+	for _, addressSqlx := range addressesSqlx {
+		result = append(result, *getDomainAddress(&addressSqlx))
+	}
+	return result, nil
+}
+
+func (r *addressRepo) Store(address *domain.Address) error {
+	const addressQuery = `
+		INSERT INTO address (
+			id, user_id, country, city, street, building, apartment, postal_code, recipient, phone, is_default, deleted,
+			created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			country = VALUES(country), city = VALUES(city), street = VALUES(street), building = VALUES(building),
+			apartment = VALUES(apartment), postal_code = VALUES(postal_code), recipient = VALUES(recipient),
+			phone = VALUES(phone), is_default = VALUES(is_default), deleted = VALUES(deleted), updated_at = NOW()
+	`
+
+	binaryID, err := address.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryUserID, err := address.UserID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		addressQuery,
+		binaryID,
+		binaryUserID,
+		address.Address.Country,
+		address.Address.City,
+		address.Address.Street,
+		address.Address.Building,
+		address.Address.Apartment,
+		address.Address.PostalCode,
+		address.Address.Recipient,
+		address.Address.Phone,
+		address.IsDefault,
+		address.Deleted,
+	)
+	return err
+}
+
+func getDomainAddress(addressSqlx *sqlxAddress) *domain.Address {
+	return &domain.Address{
+		ID:        addressSqlx.ID,
+		UserID:    addressSqlx.UserID,
+		Address:   addressSqlx.getPostalAddress(),
+		IsDefault: addressSqlx.IsDefault,
+		Deleted:   addressSqlx.Deleted,
+	}
+}
+
+func NewAddressRepository(client mysql.Client) domain.AddressRepository {
+	return &addressRepo{client: client}
+}
+
+type sqlxPostalAddress struct {
+	Country    string `db:"country"`
+	City       string `db:"city"`
+	Street     string `db:"street"`
+	Building   string `db:"building"`
+	Apartment  string `db:"apartment"`
+	PostalCode string `db:"postal_code"`
+	Recipient  string `db:"recipient"`
+	Phone      string `db:"phone"`
+}
+
+func (a *sqlxPostalAddress) getPostalAddress() domain.PostalAddress {
+	return domain.PostalAddress{
+		Country:    a.Country,
+		City:       a.City,
+		Street:     a.Street,
+		Building:   a.Building,
+		Apartment:  a.Apartment,
+		PostalCode: a.PostalCode,
+		Recipient:  a.Recipient,
+		Phone:      a.Phone,
+	}
+}
+
+type sqlxAddress struct {
+	sqlxPostalAddress
+	ID        uuid.UUID `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	IsDefault bool      `db:"is_default"`
+	Deleted   bool      `db:"deleted"`
+}
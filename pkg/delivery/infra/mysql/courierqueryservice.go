@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/query"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+)
+
+func (q *queryService) ListCouriers() ([]query.Courier, error) {
+	var couriersSqlx []sqlxCourier
+	err := q.client.Select(&couriersSqlx, `SELECT id, user_id, name, phone, zone_id, capacity, active FROM courier ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]query.Courier, 0, len(couriersSqlx))
+	for _, courierSqlx := range couriersSqlx {
+		result = append(result, getQueryCourier(courierSqlx.getCourier()))
+	}
+	return result, nil
+}
+
+func (q *queryService) GetCourierByID(courierID uuid.UUID) (*query.Courier, error) {
+	courier, err := NewCourierRepository(q.client).GetByID(courierID)
+	if errors.Is(err, domain.ErrCourierNotFound) {
+		return nil, query.ErrCourierNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := getQueryCourier(*courier)
+	return &result, nil
+}
+
+func (q *queryService) GetCourierByUserID(userID uuid.UUID) (*query.Courier, error) {
+	courier, err := NewCourierRepository(q.client).GetByUserID(userID)
+	if errors.Is(err, domain.ErrCourierNotFound) {
+		return nil, query.ErrCourierNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := getQueryCourier(*courier)
+	return &result, nil
+}
+
+func (q *queryService) ListCourierDeliveries(courierID uuid.UUID) ([]query.Delivery, error) {
+	const selectQuery = `
+		SELECT ` + deliveryFields + `
+		FROM delivery
+		WHERE courier_id = ? AND status IN (?, ?)
+		ORDER BY route_id, created_at
+	`
+
+	binaryCourierID, err := courierID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveriesSqlx []sqlxDelivery
+	err = q.client.Select(
+		&deliveriesSqlx,
+		selectQuery,
+		binaryCourierID,
+		int(domain.DeliveryStatusAwaitingDelivery),
+		int(domain.DeliveryStatusProcessing),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.getQueryDeliveries(deliveriesSqlx)
+}
+
+func getQueryCourier(courier domain.Courier) query.Courier {
+	return query.Courier{
+		ID:       courier.ID,
+		UserID:   courier.UserID,
+		Name:     courier.Name,
+		Phone:    courier.Phone,
+		ZoneID:   courier.ZoneID,
+		Capacity: courier.Capacity,
+		Active:   courier.Active,
+	}
+}
@@ -0,0 +1,129 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+)
+
+type courierRepo struct {
+	client mysql.Client
+}
+
+func (r *courierRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *courierRepo) GetByID(id uuid.UUID) (*domain.Courier, error) {
+	return r.getCourier(`SELECT id, user_id, name, phone, zone_id, capacity, active FROM courier WHERE id = ?`, id)
+}
+
+func (r *courierRepo) GetByUserID(userID uuid.UUID) (*domain.Courier, error) {
+	return r.getCourier(`SELECT id, user_id, name, phone, zone_id, capacity, active FROM courier WHERE user_id = ?`, userID)
+}
+
+func (r *courierRepo) LockActive() ([]domain.Courier, error) {
+	const courierQuery = `
+		SELECT id, user_id, name, phone, zone_id, capacity, active
+		FROM courier
+		WHERE active = 1
+		ORDER BY created_at
+		FOR UPDATE
+	`
+
+	var couriersSqlx []sqlxCourier
+	err := r.client.Select(&couriersSqlx, courierQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.Courier, 0, len(couriersSqlx))
+	for _, courierSqlx := range couriersSqlx {
+		result = append(result, courierSqlx.getCourier())
+	}
+	return result, nil
+}
+
+func (r *courierRepo) Store(courier *domain.Courier) error {
+	const courierQuery = `
+		INSERT INTO courier (id, user_id, name, phone, zone_id, capacity, active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			user_id = VALUES(user_id), name = VALUES(name), phone = VALUES(phone), zone_id = VALUES(zone_id),
+			capacity = VALUES(capacity), active = VALUES(active), updated_at = NOW()
+	`
+
+	binaryID, err := courier.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryUserID, err := courier.UserID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryZoneID, err := courier.ZoneID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		courierQuery,
+		binaryID,
+		binaryUserID,
+		courier.Name,
+		courier.Phone,
+		binaryZoneID,
+		courier.Capacity,
+		courier.Active,
+	)
+	return err
+}
+
+func (r *courierRepo) getCourier(courierQuery string, id uuid.UUID) (*domain.Courier, error) {
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var courierSqlx sqlxCourier
+	err = r.client.Get(&courierSqlx, courierQuery, binaryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCourierNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	courier := courierSqlx.getCourier()
+	return &courier, nil
+}
+
+func NewCourierRepository(client mysql.Client) domain.CourierRepository {
+	return &courierRepo{client: client}
+}
+
+type sqlxCourier struct {
+	ID       uuid.UUID `db:"id"`
+	UserID   uuid.UUID `db:"user_id"`
+	Name     string    `db:"name"`
+	Phone    string    `db:"phone"`
+	ZoneID   uuid.UUID `db:"zone_id"`
+	Capacity int       `db:"capacity"`
+	Active   bool      `db:"active"`
+}
+
+func (c *sqlxCourier) getCourier() domain.Courier {
+	return domain.Courier{
+		ID:       c.ID,
+		UserID:   c.UserID,
+		Name:     c.Name,
+		Phone:    c.Phone,
+		ZoneID:   c.ZoneID,
+		Capacity: c.Capacity,
+		Active:   c.Active,
+	}
+}
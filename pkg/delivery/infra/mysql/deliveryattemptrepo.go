@@ -0,0 +1,50 @@
+package mysql
+
+import (
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
+)
+
+type deliveryAttemptRepo struct {
+	client mysql.Client
+}
+
+func (r *deliveryAttemptRepo) Add(attempt *domain.DeliveryAttempt) error {
+	const attemptQuery = `
+		INSERT INTO delivery_attempt (delivery_id, number, courier_id, reason, attempted_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	binaryDeliveryID, err := attempt.DeliveryID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryCourierID, err := marshalNullableUUID(attempt.CourierID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		attemptQuery,
+		binaryDeliveryID,
+		attempt.Number,
+		binaryCourierID,
+		attempt.Reason,
+		attempt.AttemptedAt.UTC(),
+	)
+	return err
+}
+
+func NewDeliveryAttemptRepository(client mysql.Client) domain.DeliveryAttemptRepository {
+	return &deliveryAttemptRepo{client: client}
+}
+
+type sqlxDeliveryAttempt struct {
+	Number      int       `db:"number"`
+	CourierID   uuid.UUID `db:"courier_id"`
+	Reason      string    `db:"reason"`
+	AttemptedAt time.Time `db:"attempted_at"`
+}
@@ -3,20 +3,55 @@ package mysql
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"strings"
+	"time"
 )
 
+const deliveryFields = `
+	id, order_id, status, user_id, slot_id, address_id, pickup_point_id, pickup_code, arrived_at,
+	country, city, street, building, apartment, postal_code, recipient, phone,
+	courier_id, route_id, failure_reason, attempts, estimated_arrival
+`
+
 type deliveryRepo struct {
 	client mysql.Client
 }
 
-func (r *deliveryRepo) GetByID(orderID uuid.UUID) (*domain.Delivery, error) {
+func (r *deliveryRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *deliveryRepo) GetByID(id uuid.UUID) (*domain.Delivery, error) {
+	const deliveryQuery = `SELECT ` + deliveryFields + ` FROM delivery WHERE id = ?`
+
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var deliverySqlx sqlxDelivery
+	err = r.client.Get(&deliverySqlx, deliveryQuery, binaryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getDelivery(&deliverySqlx)
+}
+
+func (r *deliveryRepo) LockByOrderID(orderID uuid.UUID) ([]domain.Delivery, error) {
 	const deliveryQuery = `
-		SELECT order_id, status, address
+		SELECT ` + deliveryFields + `
 		FROM delivery
 		WHERE order_id = ?
+		ORDER BY created_at, id
+		FOR UPDATE
 	`
 
 	binaryOrderID, err := orderID.MarshalBinary()
@@ -24,45 +59,307 @@ func (r *deliveryRepo) GetByID(orderID uuid.UUID) (*domain.Delivery, error) {
 		return nil, err
 	}
 
-	var deliverySqlx sqlxDelivery
-	err = r.client.Get(&deliverySqlx, deliveryQuery, binaryOrderID)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, domain.ErrItemNotFound
+	var deliveriesSqlx []sqlxDelivery
+	err = r.client.Select(&deliveriesSqlx, deliveryQuery, binaryOrderID)
+	if err != nil {
+		return nil, err
 	}
+	return r.getDeliveries(deliveriesSqlx)
+}
+
+func (r *deliveryRepo) LockUnassigned() ([]domain.Delivery, error) {
+	const deliveryQuery = `
+		SELECT ` + deliveryFields + `
+		FROM delivery
+		WHERE status = ? AND courier_id IS NULL
+		ORDER BY created_at
+		FOR UPDATE
+	`
+
+	var deliveriesSqlx []sqlxDelivery
+	err := r.client.Select(&deliveriesSqlx, deliveryQuery, int(domain.DeliveryStatusAwaitingDelivery))
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.Delivery{
-		OrderID: deliverySqlx.OrderID,
-		Status:  domain.DeliveryStatus(deliverySqlx.Status),
-		Address: deliverySqlx.Address,
-	}, nil
+	return r.getDeliveries(deliveriesSqlx)
+}
+
+func (r *deliveryRepo) CountActiveByCourierID(courierID uuid.UUID) (int, error) {
+	const countQuery = `
+		SELECT COUNT(*)
+		FROM delivery
+		WHERE courier_id = ? AND status IN (?, ?)
+	`
+
+	binaryCourierID, err := courierID.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.client.Get(
+		&count,
+		countQuery,
+		binaryCourierID,
+		int(domain.DeliveryStatusAwaitingDelivery),
+		int(domain.DeliveryStatusProcessing),
+	)
+	return count, err
+}
+
+func (r *deliveryRepo) CountActiveByPickupPointID(pickupPointID uuid.UUID) (int, error) {
+	const countQuery = `
+		SELECT COUNT(*)
+		FROM delivery
+		WHERE pickup_point_id = ? AND status IN (?, ?, ?, ?, ?)
+	`
+
+	binaryPickupPointID, err := pickupPointID.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.client.Get(
+		&count,
+		countQuery,
+		binaryPickupPointID,
+		int(domain.DeliveryStatusScheduled),
+		int(domain.DeliveryStatusAwaitingDelivery),
+		int(domain.DeliveryStatusProcessing),
+		int(domain.DeliveryStatusAwaitingRedelivery),
+		int(domain.DeliveryStatusReadyForPickup),
+	)
+	return count, err
+}
+
+func (r *deliveryRepo) LockUncollected(arrivedBefore time.Time) ([]domain.Delivery, error) {
+	const deliveryQuery = `
+		SELECT ` + deliveryFields + `
+		FROM delivery
+		WHERE status = ? AND arrived_at < ?
+		ORDER BY arrived_at
+		FOR UPDATE
+	`
+
+	var deliveriesSqlx []sqlxDelivery
+	err := r.client.Select(&deliveriesSqlx, deliveryQuery, int(domain.DeliveryStatusReadyForPickup), arrivedBefore.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getDeliveries(deliveriesSqlx)
 }
 
 func (r *deliveryRepo) Store(d *domain.Delivery) error {
 	const deliveryQuery = `
-		INSERT INTO delivery (order_id, status, address, created_at)
-		VALUES (?, ?, ?, NOW())
+		INSERT INTO delivery (` + deliveryFields + `, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())
 		ON DUPLICATE KEY UPDATE
-			status = VALUES(status), address = VALUES(address), updated_at = NOW()
+			order_id = VALUES(order_id), status = VALUES(status), user_id = VALUES(user_id), slot_id = VALUES(slot_id), address_id = VALUES(address_id),
+			pickup_point_id = VALUES(pickup_point_id), pickup_code = VALUES(pickup_code), arrived_at = VALUES(arrived_at),
+			country = VALUES(country), city = VALUES(city),
+			street = VALUES(street), building = VALUES(building), apartment = VALUES(apartment),
+			postal_code = VALUES(postal_code), recipient = VALUES(recipient), phone = VALUES(phone),
+			courier_id = VALUES(courier_id), route_id = VALUES(route_id), failure_reason = VALUES(failure_reason),
+			attempts = VALUES(attempts), estimated_arrival = VALUES(estimated_arrival), updated_at = NOW()
 	`
 
+	binaryID, err := d.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
 	binaryOrderID, err := d.OrderID.MarshalBinary()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.client.Exec(deliveryQuery, binaryOrderID, d.Status, d.Address)
+	binaryUserID, err := marshalNullableUUID(d.UserID)
+	if err != nil {
+		return err
+	}
+
+	binarySlotID, err := marshalNullableUUID(d.SlotID)
+	if err != nil {
+		return err
+	}
+
+	binaryAddressID, err := marshalNullableUUID(d.AddressID)
+	if err != nil {
+		return err
+	}
+
+	binaryPickupPointID, err := marshalNullableUUID(d.PickupPointID)
+	if err != nil {
+		return err
+	}
+
+	binaryCourierID, err := marshalNullableUUID(d.CourierID)
+	if err != nil {
+		return err
+	}
+
+	binaryRouteID, err := marshalNullableUUID(d.RouteID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		deliveryQuery,
+		binaryID,
+		binaryOrderID,
+		d.Status,
+		binaryUserID,
+		binarySlotID,
+		binaryAddressID,
+		binaryPickupPointID,
+		sql.NullString{String: d.PickupCode, Valid: d.PickupCode != ""},
+		sql.NullTime{Time: d.ArrivedAt.UTC(), Valid: !d.ArrivedAt.IsZero()},
+		d.Address.Country,
+		d.Address.City,
+		d.Address.Street,
+		d.Address.Building,
+		d.Address.Apartment,
+		d.Address.PostalCode,
+		d.Address.Recipient,
+		d.Address.Phone,
+		binaryCourierID,
+		binaryRouteID,
+		sql.NullString{String: d.FailureReason, Valid: d.FailureReason != ""},
+		d.Attempts,
+		sql.NullTime{Time: d.EstimatedArrival.UTC(), Valid: !d.EstimatedArrival.IsZero()},
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.storeItems(binaryID, d.Items)
+}
+
+func (r *deliveryRepo) storeItems(binaryDeliveryID []byte, items []domain.DeliveryItem) error {
+	_, err := r.client.Exec(`DELETE FROM delivery_item WHERE delivery_id = ?`, binaryDeliveryID)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO delivery_item (delivery_id, product_id, quantity)
+		VALUES %s%s
+	`, "(?, ?, ?)", strings.Repeat(", (?, ?, ?)", len(items)-1))
+	args := make([]any, 0, len(items)*3) // arguments count
+	for _, item := range items {
+		binaryProductID, err := item.ProductID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		args = append(args, binaryDeliveryID, binaryProductID, item.Quantity)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
 	return err
 }
 
+func (r *deliveryRepo) getDeliveries(deliveriesSqlx []sqlxDelivery) ([]domain.Delivery, error) {
+	result := make([]domain.Delivery, 0, len(deliveriesSqlx))
+	for i := range deliveriesSqlx {
+		delivery, err := r.getDelivery(&deliveriesSqlx[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *delivery)
+	}
+	return result, nil
+}
+
+func (r *deliveryRepo) getDelivery(deliverySqlx *sqlxDelivery) (*domain.Delivery, error) {
+	items, err := getDeliveryItems(r.client, deliverySqlx.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := deliverySqlx.getDelivery()
+	delivery.Items = items
+	return delivery, nil
+}
+
+func getDeliveryItems(client mysql.Client, deliveryID uuid.UUID) ([]domain.DeliveryItem, error) {
+	binaryDeliveryID, err := deliveryID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var itemsSqlx []sqlxDeliveryItem
+	err = client.Select(&itemsSqlx, `SELECT product_id, quantity FROM delivery_item WHERE delivery_id = ?`, binaryDeliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.DeliveryItem, 0, len(itemsSqlx))
+	for _, itemSqlx := range itemsSqlx {
+		result = append(result, domain.DeliveryItem{
+			ProductID: itemSqlx.ProductID,
+			Quantity:  itemSqlx.Quantity,
+		})
+	}
+	return result, nil
+}
+
+func marshalNullableUUID(id uuid.UUID) ([]byte, error) {
+	if id == uuid.Nil {
+		return nil, nil
+	}
+	return id.MarshalBinary()
+}
+
 func NewDeliveryRepository(client mysql.Client) domain.DeliveryRepository {
 	return &deliveryRepo{client}
 }
 
+type sqlxDeliveryItem struct {
+	ProductID uuid.UUID `db:"product_id"`
+	Quantity  int       `db:"quantity"`
+}
+
 type sqlxDelivery struct {
-	OrderID uuid.UUID `db:"order_id"`
-	Status  int       `db:"status"`
-	Address string    `db:"address"`
+	sqlxPostalAddress
+	ID               uuid.UUID      `db:"id"`
+	OrderID          uuid.UUID      `db:"order_id"`
+	Status           int            `db:"status"`
+	UserID           uuid.UUID      `db:"user_id"`
+	SlotID           uuid.UUID      `db:"slot_id"`
+	AddressID        uuid.UUID      `db:"address_id"`
+	PickupPointID    uuid.UUID      `db:"pickup_point_id"`
+	PickupCode       sql.NullString `db:"pickup_code"`
+	ArrivedAt        sql.NullTime   `db:"arrived_at"`
+	CourierID        uuid.UUID      `db:"courier_id"`
+	RouteID          uuid.UUID      `db:"route_id"`
+	FailureReason    sql.NullString `db:"failure_reason"`
+	Attempts         int            `db:"attempts"`
+	EstimatedArrival sql.NullTime   `db:"estimated_arrival"`
+}
+
+func (d *sqlxDelivery) getDelivery() *domain.Delivery {
+	return &domain.Delivery{
+		ID:               d.ID,
+		OrderID:          d.OrderID,
+		Status:           domain.DeliveryStatus(d.Status),
+		UserID:           d.UserID,
+		SlotID:           d.SlotID,
+		AddressID:        d.AddressID,
+		PickupPointID:    d.PickupPointID,
+		PickupCode:       d.PickupCode.String,
+		ArrivedAt:        d.ArrivedAt.Time,
+		Address:          d.getPostalAddress(),
+		CourierID:        d.CourierID,
+		RouteID:          d.RouteID,
+		FailureReason:    d.FailureReason.String,
+		Attempts:         d.Attempts,
+		EstimatedArrival: d.EstimatedArrival.Time,
+	}
 }
@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/query"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+)
+
+func (q *queryService) ListPickupPoints() ([]query.PickupPoint, error) {
+	var pointsSqlx []sqlxPickupPoint
+	err := q.client.Select(&pointsSqlx, `SELECT `+pickupPointFields+` FROM pickup_point ORDER BY city, name`)
+	if err != nil {
+		return nil, err
+	}
+	return q.getQueryPickupPoints(pointsSqlx)
+}
+
+func (q *queryService) ListActivePickupPoints(city string) ([]query.PickupPoint, error) {
+	const pickupPointsQuery = `
+		SELECT ` + pickupPointFields + `
+		FROM pickup_point
+		WHERE active = 1 AND (? = '' OR city = ?)
+		ORDER BY city, name
+	`
+
+	var pointsSqlx []sqlxPickupPoint
+	err := q.client.Select(&pointsSqlx, pickupPointsQuery, city, city)
+	if err != nil {
+		return nil, err
+	}
+	return q.getQueryPickupPoints(pointsSqlx)
+}
+
+func (q *queryService) GetPickupPoint(pickupPointID uuid.UUID) (*query.PickupPoint, error) {
+	point, err := NewPickupPointRepository(q.client).GetByID(pickupPointID)
+	if errors.Is(err, domain.ErrPickupPointNotFound) {
+		return nil, query.ErrPickupPointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := q.getQueryPickupPoint(point)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (q *queryService) getQueryPickupPoints(pointsSqlx []sqlxPickupPoint) ([]query.PickupPoint, error) {
+	repo := &pickupPointRepo{client: q.client}
+	result := make([]query.PickupPoint, 0, len(pointsSqlx))
+	for _, pointSqlx := range pointsSqlx {
+		point, err := repo.getDomainPickupPoint(&pointSqlx)
+		if err != nil {
+			return nil, err
+		}
+
+		queryPoint, err := q.getQueryPickupPoint(point)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, queryPoint)
+	}
+	return result, nil
+}
+
+func (q *queryService) getQueryPickupPoint(point *domain.PickupPoint) (query.PickupPoint, error) {
+	occupied, err := NewDeliveryRepository(q.client).CountActiveByPickupPointID(point.ID)
+	if err != nil {
+		return query.PickupPoint{}, err
+	}
+
+	available := point.Capacity - occupied
+	if available < 0 {
+		available = 0
+	}
+	return query.PickupPoint{
+		ID:           point.ID,
+		Name:         point.Name,
+		Address:      point.Address,
+		OpeningHours: point.OpeningHours,
+		Capacity:     point.Capacity,
+		Available:    available,
+		Active:       point.Active,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"strings"
+	"time"
+)
+
+const pickupPointFields = `
+	id, name, country, city, street, building, apartment, postal_code, recipient, phone, capacity, active
+`
+
+type pickupPointRepo struct {
+	client mysql.Client
+}
+
+func (r *pickupPointRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *pickupPointRepo) GetByID(id uuid.UUID) (*domain.PickupPoint, error) {
+	return r.getPickupPoint(`SELECT `+pickupPointFields+` FROM pickup_point WHERE id = ?`, id)
+}
+
+func (r *pickupPointRepo) LockByID(id uuid.UUID) (*domain.PickupPoint, error) {
+	return r.getPickupPoint(`SELECT `+pickupPointFields+` FROM pickup_point WHERE id = ? FOR UPDATE`, id)
+}
+
+func (r *pickupPointRepo) Store(point *domain.PickupPoint) error {
+	const pickupPointQuery = `
+		INSERT INTO pickup_point (` + pickupPointFields + `, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			name = VALUES(name), country = VALUES(country), city = VALUES(city), street = VALUES(street),
+			building = VALUES(building), apartment = VALUES(apartment), postal_code = VALUES(postal_code),
+			recipient = VALUES(recipient), phone = VALUES(phone), capacity = VALUES(capacity), active = VALUES(active),
+			updated_at = NOW()
+	`
+
+	binaryID, err := point.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		pickupPointQuery,
+		binaryID,
+		point.Name,
+		point.Address.Country,
+		point.Address.City,
+		point.Address.Street,
+		point.Address.Building,
+		point.Address.Apartment,
+		point.Address.PostalCode,
+		point.Address.Recipient,
+		point.Address.Phone,
+		point.Capacity,
+		point.Active,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`DELETE FROM pickup_point_opening_hours WHERE pickup_point_id = ?`, binaryID)
+	if err != nil {
+		return err
+	}
+
+	if len(point.OpeningHours) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO pickup_point_opening_hours (pickup_point_id, weekday, opens, closes)
+		VALUES %s%s
+	`, "(?, ?, ?, ?)", strings.Repeat(", (?, ?, ?, ?)", len(point.OpeningHours)-1))
+	args := make([]any, 0, len(point.OpeningHours)*4) // arguments count
+	for _, hours := range point.OpeningHours {
+		args = append(args, binaryID, int(hours.Weekday), hours.Opens, hours.Closes)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func (r *pickupPointRepo) getPickupPoint(pickupPointQuery string, id uuid.UUID) (*domain.PickupPoint, error) {
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var pointSqlx sqlxPickupPoint
+	err = r.client.Get(&pointSqlx, pickupPointQuery, binaryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPickupPointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getDomainPickupPoint(&pointSqlx)
+}
+
+func (r *pickupPointRepo) getDomainPickupPoint(pointSqlx *sqlxPickupPoint) (*domain.PickupPoint, error) {
+	binaryID, err := pointSqlx.ID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var hoursSqlx []sqlxOpeningHours
+	err = r.client.Select(&hoursSqlx, `SELECT weekday, opens, closes FROM pickup_point_opening_hours WHERE pickup_point_id = ? ORDER BY weekday`, binaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	openingHours := make([]domain.OpeningHours, 0, len(hoursSqlx))
+	for _, hourSqlx := range hoursSqlx {
+		openingHours = append(openingHours, domain.OpeningHours{
+			Weekday: time.Weekday(hourSqlx.Weekday),
+			Opens:   hourSqlx.Opens,
+			Closes:  hourSqlx.Closes,
+		})
+	}
+
+	return &domain.PickupPoint{
+		ID:           pointSqlx.ID,
+		Name:         pointSqlx.Name,
+		Address:      pointSqlx.getPostalAddress(),
+		OpeningHours: openingHours,
+		Capacity:     pointSqlx.Capacity,
+		Active:       pointSqlx.Active,
+	}, nil
+}
+
+func NewPickupPointRepository(client mysql.Client) domain.PickupPointRepository {
+	return &pickupPointRepo{client: client}
+}
+
+type sqlxPickupPoint struct {
+	sqlxPostalAddress
+	ID       uuid.UUID `db:"id"`
+	Name     string    `db:"name"`
+	Capacity int       `db:"capacity"`
+	Active   bool      `db:"active"`
+}
+
+type sqlxOpeningHours struct {
+	Weekday int    `db:"weekday"`
+	Opens   string `db:"opens"`
+	Closes  string `db:"closes"`
+}
@@ -9,20 +9,149 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
 )
 
+const selectAddressQuery = `
+	SELECT id, user_id, country, city, street, building, apartment, postal_code, recipient, phone, is_default, deleted
+	FROM address
+`
+
 type queryService struct {
 	client mysql.Client
 }
 
-func (q *queryService) GetByID(orderID uuid.UUID) (*query.Delivery, error) {
-	const selectQuery = `SELECT order_id, status, address FROM delivery WHERE order_id = ?`
+func (q *queryService) ListOrderDeliveries(orderID uuid.UUID) ([]query.Delivery, error) {
+	const selectQuery = `SELECT ` + deliveryFields + ` FROM delivery WHERE order_id = ? ORDER BY created_at, id`
+
+	binaryOrderID, err := orderID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveriesSqlx []sqlxDelivery
+	err = q.client.Select(&deliveriesSqlx, selectQuery, binaryOrderID)
+	if err != nil {
+		return nil, err
+	}
+	return q.getQueryDeliveries(deliveriesSqlx)
+}
+
+func (q *queryService) ListUserAddresses(userID uuid.UUID) ([]query.Address, error) {
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var addressesSqlx []sqlxAddress
+	err = q.client.Select(
+		&addressesSqlx,
+		selectAddressQuery+`WHERE user_id = ? AND deleted = FALSE ORDER BY created_at`,
+		binaryUserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]query.Address, 0, len(addressesSqlx))
+	for _, addressSqlx := range addressesSqlx {
+		result = append(result, *getQueryAddress(&addressSqlx))
+	}
+	return result, nil
+}
+
+func (q *queryService) GetUserAddress(userID, addressID uuid.UUID) (*query.Address, error) {
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	binaryAddressID, err := addressID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return q.getAddress(
+		selectAddressQuery+`WHERE id = ? AND user_id = ? AND deleted = FALSE`,
+		binaryAddressID,
+		binaryUserID,
+	)
+}
+
+func (q *queryService) GetUserDefaultAddress(userID uuid.UUID) (*query.Address, error) {
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return q.getAddress(
+		selectAddressQuery+`WHERE user_id = ? AND is_default = TRUE AND deleted = FALSE`,
+		binaryUserID,
+	)
+}
+
+func (q *queryService) getAddress(addressQuery string, args ...any) (*query.Address, error) {
+	var addressSqlx sqlxAddress
+	err := q.client.Get(&addressSqlx, addressQuery, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, query.ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return getQueryAddress(&addressSqlx), nil
+}
+
+func getQueryAddress(addressSqlx *sqlxAddress) *query.Address {
+	return &query.Address{
+		ID:        addressSqlx.ID,
+		Address:   addressSqlx.getPostalAddress(),
+		IsDefault: addressSqlx.IsDefault,
+	}
+}
+
+func (q *queryService) ListUserOrderDeliveries(userID, orderID uuid.UUID) ([]query.Delivery, error) {
+	const selectQuery = `
+		SELECT ` + deliveryFields + `
+		FROM delivery
+		WHERE order_id = ? AND (user_id = ? OR address_id IN (SELECT id FROM address WHERE user_id = ?))
+		ORDER BY created_at, id
+	`
 
 	binaryOrderID, err := orderID.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
 
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveriesSqlx []sqlxDelivery
+	err = q.client.Select(&deliveriesSqlx, selectQuery, binaryOrderID, binaryUserID, binaryUserID)
+	if err != nil {
+		return nil, err
+	}
+	return q.getQueryDeliveries(deliveriesSqlx)
+}
+
+func (q *queryService) GetUserDelivery(userID, deliveryID uuid.UUID) (*query.Delivery, error) {
+	const selectQuery = `
+		SELECT ` + deliveryFields + `
+		FROM delivery
+		WHERE id = ? AND (user_id = ? OR address_id IN (SELECT id FROM address WHERE user_id = ?))
+	`
+
+	binaryDeliveryID, err := deliveryID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
 	var deliverySqlx sqlxDelivery
-	err = q.client.Get(&deliverySqlx, selectQuery, binaryOrderID)
+	err = q.client.Get(&deliverySqlx, selectQuery, binaryDeliveryID, binaryUserID, binaryUserID)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, query.ErrDeliveryNotFound
 	}
@@ -30,11 +159,116 @@ func (q *queryService) GetByID(orderID uuid.UUID) (*query.Delivery, error) {
 		return nil, err
 	}
 
-	return &query.Delivery{
-		OrderID: deliverySqlx.OrderID,
-		Status:  domain.DeliveryStatus(deliverySqlx.Status),
-		Address: deliverySqlx.Address,
-	}, nil
+	result, err := q.getQueryDelivery(&deliverySqlx)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (q *queryService) ListDeliveryAttempts(deliveryID uuid.UUID) ([]query.DeliveryAttempt, error) {
+	const selectQuery = `
+		SELECT number, courier_id, reason, attempted_at
+		FROM delivery_attempt
+		WHERE delivery_id = ?
+		ORDER BY number
+	`
+
+	binaryDeliveryID, err := deliveryID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var attemptsSqlx []sqlxDeliveryAttempt
+	err = q.client.Select(&attemptsSqlx, selectQuery, binaryDeliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]query.DeliveryAttempt, 0, len(attemptsSqlx))
+	for _, attemptSqlx := range attemptsSqlx {
+		result = append(result, query.DeliveryAttempt{
+			Number:      attemptSqlx.Number,
+			Reason:      attemptSqlx.Reason,
+			AttemptedAt: attemptSqlx.AttemptedAt,
+		})
+	}
+	return result, nil
+}
+
+func (q *queryService) ListTrackingEvents(orderID uuid.UUID, afterID int64) ([]query.TrackingEvent, error) {
+	const selectQuery = `
+		SELECT id, delivery_id, type, details, occurred_at
+		FROM tracking_event
+		WHERE order_id = ? AND id > ?
+		ORDER BY id
+	`
+
+	binaryOrderID, err := orderID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var eventsSqlx []sqlxTrackingEvent
+	err = q.client.Select(&eventsSqlx, selectQuery, binaryOrderID, afterID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]query.TrackingEvent, 0, len(eventsSqlx))
+	for _, eventSqlx := range eventsSqlx {
+		result = append(result, query.TrackingEvent{
+			ID:         eventSqlx.ID,
+			DeliveryID: eventSqlx.DeliveryID,
+			Type:       domain.TrackingEventType(eventSqlx.Type),
+			Details:    eventSqlx.Details.String,
+			OccurredAt: eventSqlx.OccurredAt,
+		})
+	}
+	return result, nil
+}
+
+func (q *queryService) getQueryDeliveries(deliveriesSqlx []sqlxDelivery) ([]query.Delivery, error) {
+	result := make([]query.Delivery, 0, len(deliveriesSqlx))
+	for i := range deliveriesSqlx {
+		delivery, err := q.getQueryDelivery(&deliveriesSqlx[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, delivery)
+	}
+	return result, nil
+}
+
+func (q *queryService) getQueryDelivery(deliverySqlx *sqlxDelivery) (query.Delivery, error) {
+	items, err := getDeliveryItems(q.client, deliverySqlx.ID)
+	if err != nil {
+		return query.Delivery{}, err
+	}
+
+	result := query.Delivery{
+		ID:            deliverySqlx.ID,
+		OrderID:       deliverySqlx.OrderID,
+		Items:         items,
+		Status:        domain.DeliveryStatus(deliverySqlx.Status),
+		Address:       deliverySqlx.getPostalAddress(),
+		PickupCode:    deliverySqlx.PickupCode.String,
+		FailureReason: deliverySqlx.FailureReason.String,
+		Attempts:      deliverySqlx.Attempts,
+	}
+	if deliverySqlx.SlotID != uuid.Nil {
+		result.SlotID = &deliverySqlx.SlotID
+	}
+	if deliverySqlx.PickupPointID != uuid.Nil {
+		result.PickupPointID = &deliverySqlx.PickupPointID
+	}
+	if deliverySqlx.CourierID != uuid.Nil {
+		result.CourierID = &deliverySqlx.CourierID
+	}
+	if deliverySqlx.EstimatedArrival.Valid {
+		result.EstimatedArrival = &deliverySqlx.EstimatedArrival.Time
+	}
+	return result, nil
 }
 
 func NewQueryService(client mysql.Client) query.Service {
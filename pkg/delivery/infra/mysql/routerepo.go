@@ -0,0 +1,51 @@
+package mysql
+
+import (
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+)
+
+type routeRepo struct {
+	client mysql.Client
+}
+
+func (r *routeRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *routeRepo) Store(route *domain.Route) error {
+	const routeQuery = `
+		INSERT INTO route (id, courier_id, zone_id, slot_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			courier_id = VALUES(courier_id), zone_id = VALUES(zone_id), slot_id = VALUES(slot_id)
+	`
+
+	binaryID, err := route.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryCourierID, err := route.CourierID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryZoneID, err := route.ZoneID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binarySlotID, err := marshalNullableUUID(route.SlotID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(routeQuery, binaryID, binaryCourierID, binaryZoneID, binarySlotID, route.CreatedAt.UTC())
+	return err
+}
+
+func NewRouteRepository(client mysql.Client) domain.RouteRepository {
+	return &routeRepo{client: client}
+}
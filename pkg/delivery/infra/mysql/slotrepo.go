@@ -0,0 +1,94 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
+)
+
+type slotRepo struct {
+	client mysql.Client
+}
+
+func (r *slotRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *slotRepo) LockByID(id uuid.UUID) (*domain.Slot, error) {
+	const slotQuery = `
+		SELECT id, zone_id, starts_at, ends_at, capacity, reserved
+		FROM slot
+		WHERE id = ?
+		FOR UPDATE
+	`
+
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var slotSqlx sqlxSlot
+	err = r.client.Get(&slotSqlx, slotQuery, binaryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSlotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Slot{
+		ID:       slotSqlx.ID,
+		ZoneID:   slotSqlx.ZoneID,
+		StartsAt: slotSqlx.StartsAt,
+		EndsAt:   slotSqlx.EndsAt,
+		Capacity: slotSqlx.Capacity,
+		Reserved: slotSqlx.Reserved,
+	}, nil
+}
+
+func (r *slotRepo) Store(slot *domain.Slot) error {
+	const slotQuery = `
+		INSERT INTO slot (id, zone_id, starts_at, ends_at, capacity, reserved, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			zone_id = VALUES(zone_id), starts_at = VALUES(starts_at), ends_at = VALUES(ends_at),
+			capacity = VALUES(capacity), reserved = VALUES(reserved), updated_at = NOW()
+	`
+
+	binaryID, err := slot.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryZoneID, err := slot.ZoneID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		slotQuery,
+		binaryID,
+		binaryZoneID,
+		slot.StartsAt.UTC(),
+		slot.EndsAt.UTC(),
+		slot.Capacity,
+		slot.Reserved,
+	)
+	return err
+}
+
+func NewSlotRepository(client mysql.Client) domain.SlotRepository {
+	return &slotRepo{client: client}
+}
+
+type sqlxSlot struct {
+	ID       uuid.UUID `db:"id"`
+	ZoneID   uuid.UUID `db:"zone_id"`
+	StartsAt time.Time `db:"starts_at"`
+	EndsAt   time.Time `db:"ends_at"`
+	Capacity int       `db:"capacity"`
+	Reserved int       `db:"reserved"`
+}
@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
+)
+
+type trackingEventRepo struct {
+	client mysql.Client
+}
+
+func (r *trackingEventRepo) Add(event *domain.TrackingEvent) error {
+	const eventQuery = `
+		INSERT INTO tracking_event (order_id, delivery_id, type, details, occurred_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	binaryOrderID, err := event.OrderID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryDeliveryID, err := event.DeliveryID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		eventQuery,
+		binaryOrderID,
+		binaryDeliveryID,
+		event.Type,
+		sql.NullString{String: event.Details, Valid: event.Details != ""},
+		event.OccurredAt.UTC(),
+	)
+	return err
+}
+
+func NewTrackingEventRepository(client mysql.Client) domain.TrackingEventRepository {
+	return &trackingEventRepo{client: client}
+}
+
+type sqlxTrackingEvent struct {
+	ID         int64          `db:"id"`
+	DeliveryID uuid.UUID      `db:"delivery_id"`
+	Type       int            `db:"type"`
+	Details    sql.NullString `db:"details"`
+	OccurredAt time.Time      `db:"occurred_at"`
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/persistence"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/service/async"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/infra/deliveryeventapi"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/infra/orderapi"
 )
 
@@ -18,10 +19,46 @@ func (p *persistentProvider) DeliveryRepository() domain.DeliveryRepository {
 	return NewDeliveryRepository(p.db)
 }
 
+func (p *persistentProvider) DeliveryAttemptRepository() domain.DeliveryAttemptRepository {
+	return NewDeliveryAttemptRepository(p.db)
+}
+
+func (p *persistentProvider) TrackingEventRepository() domain.TrackingEventRepository {
+	return NewTrackingEventRepository(p.db)
+}
+
+func (p *persistentProvider) AddressRepository() domain.AddressRepository {
+	return NewAddressRepository(p.db)
+}
+
+func (p *persistentProvider) ZoneRepository() domain.ZoneRepository {
+	return NewZoneRepository(p.db)
+}
+
+func (p *persistentProvider) SlotRepository() domain.SlotRepository {
+	return NewSlotRepository(p.db)
+}
+
+func (p *persistentProvider) CourierRepository() domain.CourierRepository {
+	return NewCourierRepository(p.db)
+}
+
+func (p *persistentProvider) RouteRepository() domain.RouteRepository {
+	return NewRouteRepository(p.db)
+}
+
+func (p *persistentProvider) PickupPointRepository() domain.PickupPointRepository {
+	return NewPickupPointRepository(p.db)
+}
+
 func (p *persistentProvider) OrderAPI() async.OrderAPI {
 	return orderapi.New(p.eventDispatcher(p.db))
 }
 
+func (p *persistentProvider) DeliveryEventAPI() async.DeliveryEventAPI {
+	return deliveryeventapi.New(p.eventDispatcher(p.db))
+}
+
 func (p *persistentProvider) eventDispatcher(db mysql.Client) event.Dispatcher {
 	return event.NewDispatcher(mysql.NewMessageStore(db))
 }
@@ -0,0 +1,113 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/query"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"time"
+)
+
+func (q *queryService) ListZones() ([]query.Zone, error) {
+	var zonesSqlx []sqlxZone
+	err := q.client.Select(&zonesSqlx, `SELECT id, name, free_shipping_amount FROM zone ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &zoneRepo{client: q.client}
+	result := make([]query.Zone, 0, len(zonesSqlx))
+	for _, zoneSqlx := range zonesSqlx {
+		zone, err := repo.getZone(&zoneSqlx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, getQueryZone(zone))
+	}
+	return result, nil
+}
+
+func (q *queryService) FindZoneByAddress(address *domain.PostalAddress) (*query.Zone, error) {
+	zone, err := domain.FindZoneByAddress(address, NewZoneRepository(q.client))
+	if errors.Is(err, domain.ErrZoneNotFound) {
+		return nil, query.ErrZoneNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := getQueryZone(zone)
+	return &result, nil
+}
+
+func (q *queryService) ListAvailableSlots(zoneID uuid.UUID, from time.Time) ([]query.Slot, error) {
+	const slotsQuery = `
+		SELECT id, zone_id, starts_at, ends_at, capacity, reserved
+		FROM slot
+		WHERE zone_id = ? AND starts_at > ? AND reserved < capacity
+		ORDER BY starts_at
+	`
+
+	binaryZoneID, err := zoneID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var slotsSqlx []sqlxSlot
+	err = q.client.Select(&slotsSqlx, slotsQuery, binaryZoneID, from.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]query.Slot, 0, len(slotsSqlx))
+	for _, slotSqlx := range slotsSqlx {
+		result = append(result, query.Slot{
+			ID:        slotSqlx.ID,
+			StartsAt:  slotSqlx.StartsAt,
+			EndsAt:    slotSqlx.EndsAt,
+			Available: slotSqlx.Capacity - slotSqlx.Reserved,
+		})
+	}
+	return result, nil
+}
+
+func getQueryZone(zone *domain.Zone) query.Zone {
+	return query.Zone{
+		ID:                 zone.ID,
+		Name:               zone.Name,
+		Cities:             zone.Cities,
+		PostalCodeRanges:   zone.PostalCodeRanges,
+		Rates:              zone.Rates,
+		FreeShippingAmount: zone.FreeShippingAmount,
+	}
+}
+
+func (q *queryService) GetSlot(slotID uuid.UUID) (*query.Slot, error) {
+	const slotQuery = `
+		SELECT id, zone_id, starts_at, ends_at, capacity, reserved
+		FROM slot
+		WHERE id = ?
+	`
+
+	binaryID, err := slotID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var slotSqlx sqlxSlot
+	err = q.client.Get(&slotSqlx, slotQuery, binaryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, query.ErrSlotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &query.Slot{
+		ID:        slotSqlx.ID,
+		StartsAt:  slotSqlx.StartsAt,
+		EndsAt:    slotSqlx.EndsAt,
+		Available: slotSqlx.Capacity - slotSqlx.Reserved,
+	}, nil
+}
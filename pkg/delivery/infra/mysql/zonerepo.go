@@ -0,0 +1,248 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"strings"
+)
+
+type zoneRepo struct {
+	client mysql.Client
+}
+
+func (r *zoneRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *zoneRepo) GetByID(id uuid.UUID) (*domain.Zone, error) {
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var zoneSqlx sqlxZone
+	err = r.client.Get(&zoneSqlx, `SELECT id, name, free_shipping_amount FROM zone WHERE id = ?`, binaryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrZoneNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getZone(&zoneSqlx)
+}
+
+func (r *zoneRepo) FindByCity(city string) (*domain.Zone, error) {
+	const zoneQuery = `
+		SELECT z.id, z.name, z.free_shipping_amount
+		FROM zone z
+		INNER JOIN zone_city c ON c.zone_id = z.id
+		WHERE c.city = ?
+	`
+
+	var zoneSqlx sqlxZone
+	err := r.client.Get(&zoneSqlx, zoneQuery, city)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrZoneNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getZone(&zoneSqlx)
+}
+
+func (r *zoneRepo) FindByPostalCode(postalCode string) (*domain.Zone, error) {
+	const zoneQuery = `
+		SELECT z.id, z.name, z.free_shipping_amount
+		FROM zone z
+		INNER JOIN zone_postal_code_range pr ON pr.zone_id = z.id
+		WHERE pr.code_from <= ? AND pr.code_to >= ? AND LENGTH(pr.code_from) = ?
+		LIMIT 1
+	`
+
+	var zoneSqlx sqlxZone
+	err := r.client.Get(&zoneSqlx, zoneQuery, postalCode, postalCode, len(postalCode))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrZoneNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getZone(&zoneSqlx)
+}
+
+func (r *zoneRepo) Store(zone *domain.Zone) error {
+	const zoneQuery = `
+		INSERT INTO zone (id, name, free_shipping_amount, created_at)
+		VALUES (?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			name = VALUES(name), free_shipping_amount = VALUES(free_shipping_amount), updated_at = NOW()
+	`
+
+	binaryID, err := zone.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(zoneQuery, binaryID, zone.Name, zone.FreeShippingAmount)
+	if err != nil {
+		return err
+	}
+
+	err = r.storePostalCodeRanges(binaryID, zone.PostalCodeRanges)
+	if err != nil {
+		return err
+	}
+
+	err = r.storeRates(binaryID, zone.Rates)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(`DELETE FROM zone_city WHERE zone_id = ?`, binaryID)
+	if err != nil {
+		return err
+	}
+
+	if len(zone.Cities) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO zone_city (city, zone_id)
+		VALUES %s%s
+		ON DUPLICATE KEY UPDATE
+			zone_id = VALUES(zone_id)
+	`, "(?, ?)", strings.Repeat(", (?, ?)", len(zone.Cities)-1))
+	args := make([]any, 0, len(zone.Cities)*2) // arguments count
+	for _, city := range zone.Cities {
+		args = append(args, city, binaryID)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func (r *zoneRepo) storePostalCodeRanges(binaryZoneID []byte, ranges []domain.PostalCodeRange) error {
+	_, err := r.client.Exec(`DELETE FROM zone_postal_code_range WHERE zone_id = ?`, binaryZoneID)
+	if err != nil {
+		return err
+	}
+
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO zone_postal_code_range (zone_id, code_from, code_to)
+		VALUES %s%s
+	`, "(?, ?, ?)", strings.Repeat(", (?, ?, ?)", len(ranges)-1))
+	args := make([]any, 0, len(ranges)*3) // arguments count
+	for _, postalCodeRange := range ranges {
+		args = append(args, binaryZoneID, postalCodeRange.From, postalCodeRange.To)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func (r *zoneRepo) storeRates(binaryZoneID []byte, rates []domain.ShippingRate) error {
+	_, err := r.client.Exec(`DELETE FROM zone_rate WHERE zone_id = ?`, binaryZoneID)
+	if err != nil {
+		return err
+	}
+
+	if len(rates) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO zone_rate (zone_id, max_weight, min_order_amount, price)
+		VALUES %s%s
+	`, "(?, ?, ?, ?)", strings.Repeat(", (?, ?, ?, ?)", len(rates)-1))
+	args := make([]any, 0, len(rates)*4) // arguments count
+	for _, rate := range rates {
+		args = append(args, binaryZoneID, rate.MaxWeight, rate.MinOrderAmount, rate.Price)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func (r *zoneRepo) getZone(zoneSqlx *sqlxZone) (*domain.Zone, error) {
+	binaryID, err := zoneSqlx.ID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var cities []string
+	err = r.client.Select(&cities, `SELECT city FROM zone_city WHERE zone_id = ? ORDER BY city`, binaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rangesSqlx []sqlxPostalCodeRange
+	err = r.client.Select(&rangesSqlx, `SELECT code_from, code_to FROM zone_postal_code_range WHERE zone_id = ? ORDER BY code_from`, binaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]domain.PostalCodeRange, 0, len(rangesSqlx))
+	for _, rangeSqlx := range rangesSqlx {
+		ranges = append(ranges, domain.PostalCodeRange{
+			From: rangeSqlx.From,
+			To:   rangeSqlx.To,
+		})
+	}
+
+	var ratesSqlx []sqlxShippingRate
+	err = r.client.Select(&ratesSqlx, `SELECT max_weight, min_order_amount, price FROM zone_rate WHERE zone_id = ? ORDER BY price`, binaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]domain.ShippingRate, 0, len(ratesSqlx))
+	for _, rateSqlx := range ratesSqlx {
+		rates = append(rates, domain.ShippingRate{
+			MaxWeight:      rateSqlx.MaxWeight,
+			MinOrderAmount: rateSqlx.MinOrderAmount,
+			Price:          rateSqlx.Price,
+		})
+	}
+
+	return &domain.Zone{
+		ID:                 zoneSqlx.ID,
+		Name:               zoneSqlx.Name,
+		Cities:             cities,
+		PostalCodeRanges:   ranges,
+		Rates:              rates,
+		FreeShippingAmount: zoneSqlx.FreeShippingAmount,
+	}, nil
+}
+
+func NewZoneRepository(client mysql.Client) domain.ZoneRepository {
+	return &zoneRepo{client: client}
+}
+
+type sqlxZone struct {
+	ID                 uuid.UUID `db:"id"`
+	Name               string    `db:"name"`
+	FreeShippingAmount int       `db:"free_shipping_amount"`
+}
+
+type sqlxPostalCodeRange struct {
+	From string `db:"code_from"`
+	To   string `db:"code_to"`
+}
+
+type sqlxShippingRate struct {
+	MaxWeight      int `db:"max_weight"`
+	MinOrderAmount int `db:"min_order_amount"`
+	Price          int `db:"price"`
+}
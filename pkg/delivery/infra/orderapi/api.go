@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/service/async"
+	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
 )
 
 const orderEventTopicName = "order_event"
@@ -15,13 +16,67 @@ type api struct {
 }
 
 func (a *api) NotifyDeliveryScheduled(orderID uuid.UUID) error {
+	return a.dispatchOrderEvent("delivery_scheduled", orderID)
+}
+
+func (a *api) NotifyDeliveryScheduleRejected(orderID uuid.UUID) error {
+	return a.dispatchOrderEvent("delivery_schedule_rejected", orderID)
+}
+
+func (a *api) NotifyOrderDelivered(orderID uuid.UUID) error {
+	return a.dispatchOrderEvent("order_delivered", orderID)
+}
+
+func (a *api) NotifyOrderPartiallyDelivered(orderID uuid.UUID) error {
+	return a.dispatchOrderEvent("order_partially_delivered", orderID)
+}
+
+func (a *api) NotifyDeliveryFailed(orderID uuid.UUID) error {
+	return a.dispatchOrderEvent("delivery_failed", orderID)
+}
+
+func (a *api) NotifyDeliveryPartiallyFailed(orderID uuid.UUID, failedItems []domain.DeliveryItem) error {
+	type itemSchema struct {
+		ProductID uuid.UUID `json:"product_id"`
+		Quantity  int       `json:"quantity"`
+	}
+
+	items := make([]itemSchema, 0, len(failedItems))
+	for _, item := range failedItems {
+		items = append(items, itemSchema{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	body, err := json.Marshal(struct {
+		OrderID uuid.UUID    `json:"order_id"`
+		Items   []itemSchema `json:"items"`
+	}{orderID, items})
+	if err != nil {
+		return errors.New("failed to encode failed items")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "delivery_partially_failed",
+		TopicName: orderEventTopicName,
+		Key:       orderID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func (a *api) dispatchOrderEvent(eventType string, orderID uuid.UUID) error {
 	jsonID, err := json.Marshal(orderID)
 	if err != nil {
 		return errors.New("failed to encode orderID")
 	}
 
 	err = a.eventDispatcher.Dispatch(&event.Event{
-		Type:      "delivery_scheduled",
+		Type:      eventType,
 		TopicName: orderEventTopicName,
 		Key:       orderID.String(),
 		Body:      jsonID,
@@ -3,6 +3,7 @@ package transport
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
@@ -10,50 +11,1425 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/query"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/app/service"
 	"github.com/klwxsrx/arch-course-project/pkg/delivery/domain"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 )
 
-const healthEndpoint = "/healthz"
+const (
+	healthEndpoint = "/healthz"
+
+	trackingStreamPollInterval      = 2 * time.Second
+	trackingStreamHeartbeatInterval = 15 * time.Second
+)
+
+type postalAddressJSONSchema struct {
+	Country    string `json:"country"`
+	City       string `json:"city"`
+	Street     string `json:"street"`
+	Building   string `json:"building"`
+	Apartment  string `json:"apartment"`
+	PostalCode string `json:"postal_code"`
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+}
+
+type addressJSONSchema struct {
+	postalAddressJSONSchema
+	ID        uuid.UUID `json:"id"`
+	IsDefault bool      `json:"is_default"`
+}
+
+type slotJSONSchema struct {
+	ID        uuid.UUID `json:"id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Available int       `json:"available"`
+}
+
+type deliveryItemJSONSchema struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+type deliveryJSONSchema struct {
+	ID            uuid.UUID                `json:"id"`
+	OrderID       uuid.UUID                `json:"order_id"`
+	Items         []deliveryItemJSONSchema `json:"items"`
+	Status        string                   `json:"status"`
+	SlotID        *uuid.UUID               `json:"slot_id"`
+	Address       postalAddressJSONSchema  `json:"address"`
+	CourierID     *uuid.UUID               `json:"courier_id"`
+	PickupPointID *uuid.UUID               `json:"pickup_point_id,omitempty"`
+	FailureReason string                   `json:"failure_reason,omitempty"`
+	Attempts      int                      `json:"attempts"`
+}
+
+type trackingEventJSONSchema struct {
+	ID         int64     `json:"id"`
+	DeliveryID uuid.UUID `json:"delivery_id"`
+	Type       string    `json:"type"`
+	Details    string    `json:"details,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type courierJSONSchema struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   uuid.UUID `json:"user_id"`
+	Name     string    `json:"name"`
+	Phone    string    `json:"phone"`
+	ZoneID   uuid.UUID `json:"zone_id"`
+	Capacity int       `json:"capacity"`
+	Active   bool      `json:"active"`
+}
+
+type openingHoursJSONSchema struct {
+	Weekday time.Weekday `json:"weekday"`
+	Opens   string       `json:"opens"`
+	Closes  string       `json:"closes"`
+}
+
+type pickupPointJSONSchema struct {
+	ID           uuid.UUID                `json:"id"`
+	Name         string                   `json:"name"`
+	Address      postalAddressJSONSchema  `json:"address"`
+	OpeningHours []openingHoursJSONSchema `json:"opening_hours"`
+	Capacity     int                      `json:"capacity"`
+	Available    int                      `json:"available"`
+	Active       bool                     `json:"active"`
+}
+
+type postalCodeRangeJSONSchema struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type shippingRateJSONSchema struct {
+	MaxWeight      int `json:"max_weight"`
+	MinOrderAmount int `json:"min_order_amount"`
+	Price          int `json:"price"`
+}
+
+type zoneJSONSchema struct {
+	ID                 uuid.UUID                   `json:"id"`
+	Name               string                      `json:"name"`
+	Cities             []string                    `json:"cities"`
+	PostalCodeRanges   []postalCodeRangeJSONSchema `json:"postal_code_ranges"`
+	Rates              []shippingRateJSONSchema    `json:"rates"`
+	FreeShippingAmount int                         `json:"free_shipping_amount"`
+}
 
 type route struct {
 	Name    string
 	Method  string
 	Pattern string
-	Handler func(*service.DeliveryService, query.Service, http.ResponseWriter, *http.Request)
+	Handler func(*service.DeliveryService, *service.AddressService, *service.ZoneService, *service.CourierService, *service.PickupPointService, query.Service, http.ResponseWriter, *http.Request)
+}
+
+func getRoutes() []route {
+	return []route{
+		{
+			"listZones",
+			http.MethodGet,
+			"/delivery/zones",
+			listZonesHandler,
+		},
+		{
+			"storeZone",
+			http.MethodPut,
+			"/delivery/zones",
+			storeZoneHandler,
+		},
+		{
+			"addSlot",
+			http.MethodPost,
+			"/delivery/zones/{zoneID}/slots",
+			addSlotHandler,
+		},
+		{
+			"listCouriers",
+			http.MethodGet,
+			"/delivery/couriers",
+			listCouriersHandler,
+		},
+		{
+			"storeCourier",
+			http.MethodPut,
+			"/delivery/couriers",
+			storeCourierHandler,
+		},
+		{
+			"assignRoutes",
+			http.MethodPost,
+			"/delivery/routes/assign",
+			assignRoutesHandler,
+		},
+		{
+			"listPickupPoints",
+			http.MethodGet,
+			"/delivery/pickup-points",
+			listPickupPointsHandler,
+		},
+		{
+			"storePickupPoint",
+			http.MethodPut,
+			"/delivery/pickup-points",
+			storePickupPointHandler,
+		},
+		{
+			"returnUncollected",
+			http.MethodPost,
+			"/delivery/pickup-points/return-uncollected",
+			returnUncollectedHandler,
+		},
+		{
+			"getPickupPoint",
+			http.MethodGet,
+			"/delivery/pickup-points/{pickupPointID}",
+			getPickupPointHandler,
+		},
+		{
+			"handOverDelivery",
+			http.MethodPost,
+			"/delivery/pickup-points/{pickupPointID}/deliveries/{deliveryID}/handed-over",
+			handOverDeliveryHandler,
+		},
+		{
+			"splitParcel",
+			http.MethodPost,
+			"/delivery/parcels/{deliveryID}/split",
+			splitParcelHandler,
+		},
+		{
+			"quoteShipping",
+			http.MethodPost,
+			"/delivery/shipping/quote",
+			quoteShippingHandler,
+		},
+		{
+			"getDelivery",
+			http.MethodGet,
+			"/delivery/{orderID}",
+			getDeliveryHandler,
+		},
+		{
+			"getUserDefaultAddress",
+			http.MethodGet,
+			"/delivery/users/{userID}/addresses/default",
+			getUserDefaultAddressHandler,
+		},
+		{
+			"getUserAddress",
+			http.MethodGet,
+			"/delivery/users/{userID}/addresses/{addressID}",
+			getUserAddressHandler,
+		},
+		{
+			"listAddresses",
+			http.MethodGet,
+			"/web/delivery/addresses",
+			listAddressesHandler,
+		},
+		{
+			"addAddress",
+			http.MethodPost,
+			"/web/delivery/addresses",
+			addAddressHandler,
+		},
+		{
+			"getAddress",
+			http.MethodGet,
+			"/web/delivery/addresses/{addressID}",
+			getAddressHandler,
+		},
+		{
+			"updateAddress",
+			http.MethodPut,
+			"/web/delivery/addresses/{addressID}",
+			updateAddressHandler,
+		},
+		{
+			"deleteAddress",
+			http.MethodDelete,
+			"/web/delivery/addresses/{addressID}",
+			deleteAddressHandler,
+		},
+		{
+			"listAvailableSlots",
+			http.MethodGet,
+			"/web/delivery/slots",
+			listAvailableSlotsHandler,
+		},
+		{
+			"listActivePickupPoints",
+			http.MethodGet,
+			"/web/delivery/pickup-points",
+			listActivePickupPointsHandler,
+		},
+		{
+			"getRedeliveryOffer",
+			http.MethodGet,
+			"/web/delivery/parcels/{deliveryID}/redelivery",
+			getRedeliveryOfferHandler,
+		},
+		{
+			"scheduleRedelivery",
+			http.MethodPost,
+			"/web/delivery/parcels/{deliveryID}/redelivery",
+			scheduleRedeliveryHandler,
+		},
+		{
+			"getDeliveryTracking",
+			http.MethodGet,
+			"/web/delivery/{orderID}/tracking",
+			getDeliveryTrackingHandler,
+		},
+		{
+			"streamDeliveryTracking",
+			http.MethodGet,
+			"/web/delivery/{orderID}/tracking/stream",
+			streamDeliveryTrackingHandler,
+		},
+		{
+			"listCourierDeliveries",
+			http.MethodGet,
+			"/web/delivery/courier/deliveries",
+			listCourierDeliveriesHandler,
+		},
+		{
+			"pickUpDelivery",
+			http.MethodPost,
+			"/web/delivery/courier/deliveries/{deliveryID}/picked-up",
+			pickUpDeliveryHandler,
+		},
+		{
+			"completeDelivery",
+			http.MethodPost,
+			"/web/delivery/courier/deliveries/{deliveryID}/delivered",
+			completeDeliveryHandler,
+		},
+		{
+			"failDelivery",
+			http.MethodPost,
+			"/web/delivery/courier/deliveries/{deliveryID}/failed",
+			failDeliveryHandler,
+		},
+		{
+			"health",
+			http.MethodGet,
+			healthEndpoint,
+			healthCheckHandler,
+		},
+	}
+}
+
+func getDeliveryHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	orderID, err := parseUUID(mux.Vars(r)["orderID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := qs.ListOrderDeliveries(orderID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(deliveries) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	result := make([]deliveryJSONSchema, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryJSON, err := getDeliveryJSONSchema(&delivery)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		result = append(result, deliveryJSON)
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getUserDefaultAddressHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUID(mux.Vars(r)["userID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	address, err := qs.GetUserDefaultAddress(userID)
+	writeAddress(w, address, err)
+}
+
+func getUserAddressHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	userID, err := parseUUID(mux.Vars(r)["userID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	addressID, err := parseUUID(mux.Vars(r)["addressID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	address, err := qs.GetUserAddress(userID, addressID)
+	writeAddress(w, address, err)
+}
+
+func listAddressesHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	addresses, err := qs.ListUserAddresses(authUserID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]addressJSONSchema, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, getAddressJSONSchema(&address))
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func addAddressHandler(_ *service.DeliveryService, srv *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var body addressJSONSchema
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	addressID, err := srv.Add(authUserID, getPostalAddress(&body.postalAddressJSONSchema), body.IsDefault)
+	if errors.Is(err, service.ErrInvalidAddress) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(addressID)
+}
+
+func getAddressHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	addressID, err := parseUUID(mux.Vars(r)["addressID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	address, err := qs.GetUserAddress(authUserID, addressID)
+	writeAddress(w, address, err)
+}
+
+func updateAddressHandler(_ *service.DeliveryService, srv *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	addressID, err := parseUUID(mux.Vars(r)["addressID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var body addressJSONSchema
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.Update(authUserID, addressID, getPostalAddress(&body.postalAddressJSONSchema), body.IsDefault)
+	switch {
+	case errors.Is(err, service.ErrInvalidAddress):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrAddressNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func deleteAddressHandler(_ *service.DeliveryService, srv *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	addressID, err := parseUUID(mux.Vars(r)["addressID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.Delete(authUserID, addressID)
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func listZonesHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, _ *http.Request) {
+	zones, err := qs.ListZones()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]zoneJSONSchema, 0, len(zones))
+	for _, zone := range zones {
+		result = append(result, getZoneJSONSchema(&zone))
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func storeZoneHandler(_ *service.DeliveryService, _ *service.AddressService, srv *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	var body zoneJSONSchema
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	zoneID, err := srv.StoreZone(getZone(&body))
+	switch {
+	case errors.Is(err, service.ErrInvalidZone):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrCityInOtherZone), errors.Is(err, service.ErrPostalCodeInOtherZone):
+		w.WriteHeader(http.StatusConflict)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		_ = json.NewEncoder(w).Encode(zoneID)
+	}
+}
+
+func addSlotHandler(_ *service.DeliveryService, _ *service.AddressService, srv *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	zoneID, err := parseUUID(mux.Vars(r)["zoneID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		StartsAt time.Time `json:"starts_at"`
+		EndsAt   time.Time `json:"ends_at"`
+		Capacity int       `json:"capacity"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	slotID, err := srv.AddSlot(zoneID, body.StartsAt, body.EndsAt, body.Capacity)
+	switch {
+	case errors.Is(err, service.ErrInvalidSlot):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrZoneNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		_ = json.NewEncoder(w).Encode(slotID)
+	}
+}
+
+func splitParcelHandler(srv *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := parseUUID(mux.Vars(r)["deliveryID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Parcels []struct {
+			Items []deliveryItemJSONSchema `json:"items"`
+		} `json:"parcels"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	parcelsItems := make([][]domain.DeliveryItem, 0, len(body.Parcels))
+	for _, parcel := range body.Parcels {
+		items := make([]domain.DeliveryItem, 0, len(parcel.Items))
+		for _, item := range parcel.Items {
+			items = append(items, domain.DeliveryItem{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+			})
+		}
+		parcelsItems = append(parcelsItems, items)
+	}
+
+	parcelIDs, err := srv.SplitParcel(deliveryID, parcelsItems)
+	switch {
+	case errors.Is(err, service.ErrInvalidParcelItems):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidDeliveryStatus):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, service.ErrSlotUnavailable):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(struct {
+			Error string `json:"error"`
+		}{"slot_unavailable"})
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		_ = json.NewEncoder(w).Encode(parcelIDs)
+	}
+}
+
+func quoteShippingHandler(_ *service.DeliveryService, _ *service.AddressService, srv *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		UserID        uuid.UUID `json:"user_id"`
+		AddressID     uuid.UUID `json:"address_id"`
+		PickupPointID uuid.UUID `json:"pickup_point_id"`
+		Weight        int       `json:"weight"`
+		OrderAmount   int       `json:"order_amount"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil || body.Weight < 0 || body.OrderAmount < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if body.AddressID != uuid.Nil && body.PickupPointID != uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var quote *service.ShippingQuote
+	if body.PickupPointID != uuid.Nil {
+		quote, err = srv.QuotePickupShipping(body.PickupPointID, body.Weight, body.OrderAmount)
+	} else {
+		quote, err = srv.QuoteShipping(body.UserID, body.AddressID, body.Weight, body.OrderAmount)
+	}
+	switch {
+	case errors.Is(err, service.ErrAddressNotFound), errors.Is(err, service.ErrPickupPointNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrShippingUnavailable):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(struct {
+			Error string `json:"error"`
+		}{"shipping_unavailable"})
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		_ = json.NewEncoder(w).Encode(struct {
+			ZoneID uuid.UUID `json:"zone_id"`
+			Price  int       `json:"price"`
+		}{quote.ZoneID, quote.Price})
+	}
+}
+
+func listPickupPointsHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, _ *http.Request) {
+	points, err := qs.ListPickupPoints()
+	writePickupPoints(w, points, err)
+}
+
+func listActivePickupPointsHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	points, err := qs.ListActivePickupPoints(r.URL.Query().Get("city"))
+	writePickupPoints(w, points, err)
+}
+
+func getPickupPointHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	pickupPointID, err := parseUUID(mux.Vars(r)["pickupPointID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	point, err := qs.GetPickupPoint(pickupPointID)
+	if errors.Is(err, query.ErrPickupPointNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(getPickupPointJSONSchema(point))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func storePickupPointHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, srv *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	var body pickupPointJSONSchema
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	pickupPointID, err := srv.StorePickupPoint(getPickupPoint(&body))
+	switch {
+	case errors.Is(err, service.ErrInvalidPickupPoint):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrPickupPointNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		_ = json.NewEncoder(w).Encode(pickupPointID)
+	}
+}
+
+func handOverDeliveryHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, srv *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	pickupPointID, err := parseUUID(mux.Vars(r)["pickupPointID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	deliveryID, err := parseUUID(mux.Vars(r)["deliveryID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		PickupCode string `json:"pickup_code"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil || body.PickupCode == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.HandOver(pickupPointID, deliveryID, body.PickupCode)
+	switch {
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidDeliveryStatus):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, service.ErrInvalidPickupCode):
+		w.WriteHeader(http.StatusForbidden)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func returnUncollectedHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, srv *service.PickupPointService, _ query.Service, w http.ResponseWriter, _ *http.Request) {
+	deliveryIDs, err := srv.ReturnUncollected()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if deliveryIDs == nil {
+		deliveryIDs = []uuid.UUID{}
+	}
+
+	err = json.NewEncoder(w).Encode(deliveryIDs)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func listAvailableSlotsHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var address *query.Address
+	if addressIDParam := r.URL.Query().Get("address_id"); addressIDParam != "" {
+		addressID, err := parseUUID(addressIDParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		address, err = qs.GetUserAddress(authUserID, addressID)
+	} else {
+		address, err = qs.GetUserDefaultAddress(authUserID)
+	}
+	if errors.Is(err, query.ErrAddressNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result, err := getAvailableSlots(&address.Address, qs)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func listCouriersHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, _ *http.Request) {
+	couriers, err := qs.ListCouriers()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]courierJSONSchema, 0, len(couriers))
+	for _, courier := range couriers {
+		result = append(result, courierJSONSchema{
+			ID:       courier.ID,
+			UserID:   courier.UserID,
+			Name:     courier.Name,
+			Phone:    courier.Phone,
+			ZoneID:   courier.ZoneID,
+			Capacity: courier.Capacity,
+			Active:   courier.Active,
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func storeCourierHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, srv *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	var body courierJSONSchema
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	courierID, err := srv.StoreCourier(&domain.Courier{
+		ID:       body.ID,
+		UserID:   body.UserID,
+		Name:     body.Name,
+		Phone:    body.Phone,
+		ZoneID:   body.ZoneID,
+		Capacity: body.Capacity,
+		Active:   body.Active,
+	})
+	switch {
+	case errors.Is(err, service.ErrInvalidCourier):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrZoneNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrCourierUserConflict):
+		w.WriteHeader(http.StatusConflict)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		_ = json.NewEncoder(w).Encode(courierID)
+	}
+}
+
+func assignRoutesHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, srv *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, _ *http.Request) {
+	routes, err := srv.AssignRoutes()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type routeJSONSchema struct {
+		ID          uuid.UUID   `json:"id"`
+		CourierID   uuid.UUID   `json:"courier_id"`
+		ZoneID      uuid.UUID   `json:"zone_id"`
+		SlotID      *uuid.UUID  `json:"slot_id"`
+		DeliveryIDs []uuid.UUID `json:"delivery_ids"`
+	}
+
+	result := make([]routeJSONSchema, 0, len(routes))
+	for _, route := range routes {
+		var slotID *uuid.UUID
+		if route.SlotID != uuid.Nil {
+			slotID = &route.SlotID
+		}
+		result = append(result, routeJSONSchema{
+			ID:          route.ID,
+			CourierID:   route.CourierID,
+			ZoneID:      route.ZoneID,
+			SlotID:      slotID,
+			DeliveryIDs: route.DeliveryIDs,
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func listCourierDeliveriesHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	courier, err := qs.GetCourierByUserID(authUserID)
+	if errors.Is(err, query.ErrCourierNotFound) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := qs.ListCourierDeliveries(courier.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]deliveryJSONSchema, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryJSON, err := getDeliveryJSONSchema(&delivery)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		result = append(result, deliveryJSON)
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 }
 
-func getRoutes() []route {
-	return []route{
-		{
-			"getDelivery",
-			http.MethodGet,
-			"/delivery/{orderID}",
-			getDeliveryHandler,
-		},
-		{
-			"health",
-			http.MethodGet,
-			healthEndpoint,
-			healthCheckHandler,
-		},
+func pickUpDeliveryHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, srv *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		ETA time.Time `json:"eta"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil && !errors.Is(err, io.EOF) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	handleCourierDeliveryUpdate(w, r, func(courierUserID, deliveryID uuid.UUID) error {
+		return srv.PickUp(courierUserID, deliveryID, body.ETA)
+	})
+}
+
+func completeDeliveryHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, srv *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	handleCourierDeliveryUpdate(w, r, srv.Complete)
+}
+
+func failDeliveryHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, srv *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	handleCourierDeliveryUpdate(w, r, func(courierUserID, deliveryID uuid.UUID) error {
+		return srv.Fail(courierUserID, deliveryID, body.Reason)
+	})
+}
+
+func handleCourierDeliveryUpdate(w http.ResponseWriter, r *http.Request, update func(courierUserID, deliveryID uuid.UUID) error) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	deliveryID, err := parseUUID(mux.Vars(r)["deliveryID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = update(authUserID, deliveryID)
+	switch {
+	case errors.Is(err, service.ErrInvalidFailureReason), errors.Is(err, service.ErrInvalidEstimatedArrival):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrCourierNotFound), errors.Is(err, service.ErrDeliveryNotAssigned):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidDeliveryStatus):
+		w.WriteHeader(http.StatusConflict)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getDeliveryTrackingHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	orderID, err := parseUUID(mux.Vars(r)["orderID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := qs.ListUserOrderDeliveries(authUserID, orderID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(deliveries) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	events, err := qs.ListTrackingEvents(orderID, 0)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	eventsJSON, err := getTrackingEventsJSONSchema(events)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type trackingSlotJSONSchema struct {
+		StartsAt time.Time `json:"starts_at"`
+		EndsAt   time.Time `json:"ends_at"`
+	}
+
+	type trackingCourierJSONSchema struct {
+		Name string `json:"name"`
+	}
+
+	type parcelTrackingJSONSchema struct {
+		ID            uuid.UUID                  `json:"id"`
+		Status        string                     `json:"status"`
+		Items         []deliveryItemJSONSchema   `json:"items"`
+		Slot          *trackingSlotJSONSchema    `json:"slot"`
+		Courier       *trackingCourierJSONSchema `json:"courier"`
+		PickupPointID *uuid.UUID                 `json:"pickup_point_id,omitempty"`
+		PickupCode    string                     `json:"pickup_code,omitempty"`
+		ETA           *time.Time                 `json:"eta"`
+		Events        []trackingEventJSONSchema  `json:"events"`
+	}
+
+	parcels := make([]parcelTrackingJSONSchema, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryJSON, err := getDeliveryJSONSchema(&delivery)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		parcel := parcelTrackingJSONSchema{
+			ID:            delivery.ID,
+			Status:        deliveryJSON.Status,
+			Items:         deliveryJSON.Items,
+			PickupPointID: delivery.PickupPointID,
+			ETA:           delivery.EstimatedArrival,
+			Events:        make([]trackingEventJSONSchema, 0),
+		}
+		if delivery.SlotID != nil {
+			slot, err := qs.GetSlot(*delivery.SlotID)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			parcel.Slot = &trackingSlotJSONSchema{StartsAt: slot.StartsAt, EndsAt: slot.EndsAt}
+		}
+		if delivery.CourierID != nil {
+			courier, err := qs.GetCourierByID(*delivery.CourierID)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			parcel.Courier = &trackingCourierJSONSchema{Name: courier.Name}
+		}
+		if delivery.Status == domain.DeliveryStatusReadyForPickup {
+			parcel.PickupCode = delivery.PickupCode
+		}
+		for _, eventJSON := range eventsJSON {
+			if eventJSON.DeliveryID == delivery.ID {
+				parcel.Events = append(parcel.Events, eventJSON)
+			}
+		}
+		parcels = append(parcels, parcel)
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		OrderID uuid.UUID                  `json:"order_id"`
+		Parcels []parcelTrackingJSONSchema `json:"parcels"`
+	}{
+		orderID,
+		parcels,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 }
 
-func getDeliveryHandler(_ *service.DeliveryService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+func streamDeliveryTrackingHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
 	orderID, err := parseUUID(mux.Vars(r)["orderID"])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	del, err := qs.GetByID(orderID)
+	var lastEventID int64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		lastEventID, err = strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := qs.ListUserOrderDeliveries(authUserID, orderID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(deliveries) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	pollTicker := time.NewTicker(trackingStreamPollInterval)
+	defer pollTicker.Stop()
+	heartbeatTicker := time.NewTicker(trackingStreamHeartbeatInterval)
+	defer heartbeatTicker.Stop()
+
+	for {
+		deliveries, err := qs.ListUserOrderDeliveries(authUserID, orderID)
+		if err != nil {
+			return
+		}
+		events, err := qs.ListTrackingEvents(orderID, lastEventID)
+		if err != nil {
+			return
+		}
+		eventsJSON, err := getTrackingEventsJSONSchema(events)
+		if err != nil {
+			return
+		}
+
+		deliveryByID := make(map[uuid.UUID]*query.Delivery, len(deliveries))
+		isFinal := true
+		for i := range deliveries {
+			deliveryByID[deliveries[i].ID] = &deliveries[i]
+			isFinal = isFinal && isFinalDeliveryStatus(deliveries[i].Status)
+		}
+
+		for _, eventJSON := range eventsJSON {
+			delivery, ok := deliveryByID[eventJSON.DeliveryID]
+			if !ok {
+				continue
+			}
+			deliveryJSON, err := getDeliveryJSONSchema(delivery)
+			if err != nil {
+				return
+			}
+
+			data, err := json.Marshal(struct {
+				trackingEventJSONSchema
+				Status string     `json:"status"`
+				ETA    *time.Time `json:"eta"`
+			}{
+				eventJSON,
+				deliveryJSON.Status,
+				delivery.EstimatedArrival,
+			})
+			if err != nil {
+				return
+			}
+
+			_, err = fmt.Fprintf(w, "id: %d\nevent: tracking\ndata: %s\n\n", eventJSON.ID, data)
+			if err != nil {
+				return
+			}
+			lastEventID = eventJSON.ID
+		}
+		flusher.Flush()
+
+		if isFinal {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeatTicker.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-pollTicker.C:
+		}
+	}
+}
+
+func getRedeliveryOfferHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	deliveryID, err := parseUUID(mux.Vars(r)["deliveryID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := qs.GetUserDelivery(authUserID, deliveryID)
 	if errors.Is(err, query.ErrDeliveryNotFound) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if delivery.Status != domain.DeliveryStatusAwaitingRedelivery {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	attempts, err := qs.ListDeliveryAttempts(deliveryID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	slots, err := getAvailableSlots(&delivery.Address, qs)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type attemptJSONSchema struct {
+		Number      int       `json:"number"`
+		Reason      string    `json:"reason"`
+		AttemptedAt time.Time `json:"attempted_at"`
+	}
+
+	attemptsJSON := make([]attemptJSONSchema, 0, len(attempts))
+	for _, attempt := range attempts {
+		attemptsJSON = append(attemptsJSON, attemptJSONSchema{
+			Number:      attempt.Number,
+			Reason:      attempt.Reason,
+			AttemptedAt: attempt.AttemptedAt,
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		DeliveryID uuid.UUID           `json:"delivery_id"`
+		OrderID    uuid.UUID           `json:"order_id"`
+		Attempts   []attemptJSONSchema `json:"attempts"`
+		Slots      []slotJSONSchema    `json:"slots"`
+	}{
+		delivery.ID,
+		delivery.OrderID,
+		attemptsJSON,
+		slots,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func scheduleRedeliveryHandler(srv *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	authUserID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	deliveryID, err := parseUUID(mux.Vars(r)["deliveryID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		SlotID uuid.UUID `json:"slot_id"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.ScheduleRedelivery(authUserID, deliveryID, body.SlotID)
+	switch {
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrRedeliveryUnavailable):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, service.ErrSlotUnavailable):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(struct {
+			Error string `json:"error"`
+		}{"slot_unavailable"})
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getTrackingEventsJSONSchema(events []query.TrackingEvent) ([]trackingEventJSONSchema, error) {
+	result := make([]trackingEventJSONSchema, 0, len(events))
+	for _, event := range events {
+		var eventType string
+		switch event.Type {
+		case domain.TrackingEventScheduled:
+			eventType = "scheduled"
+		case domain.TrackingEventAwaitingDelivery:
+			eventType = "awaiting_delivery"
+		case domain.TrackingEventCourierAssigned:
+			eventType = "courier_assigned"
+		case domain.TrackingEventPickedUp:
+			eventType = "picked_up"
+		case domain.TrackingEventDelivered:
+			eventType = "delivered"
+		case domain.TrackingEventAttemptFailed:
+			eventType = "attempt_failed"
+		case domain.TrackingEventRedeliveryScheduled:
+			eventType = "redelivery_scheduled"
+		case domain.TrackingEventFailed:
+			eventType = "failed"
+		case domain.TrackingEventCancelled:
+			eventType = "cancelled"
+		case domain.TrackingEventReadyForPickup:
+			eventType = "ready_for_pickup"
+		case domain.TrackingEventSplit:
+			eventType = "split"
+		default:
+			return nil, errors.New("unknown tracking event type")
+		}
+
+		result = append(result, trackingEventJSONSchema{
+			ID:         event.ID,
+			DeliveryID: event.DeliveryID,
+			Type:       eventType,
+			Details:    event.Details,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+	return result, nil
+}
+
+func isFinalDeliveryStatus(status domain.DeliveryStatus) bool {
+	return status == domain.DeliveryStatusDelivered ||
+		status == domain.DeliveryStatusFailed ||
+		status == domain.DeliveryStatusCancelled
+}
+
+func getAvailableSlots(address *domain.PostalAddress, qs query.Service) ([]slotJSONSchema, error) {
+	result := make([]slotJSONSchema, 0)
+	zone, err := qs.FindZoneByAddress(address)
+	if errors.Is(err, query.ErrZoneNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := qs.ListAvailableSlots(zone.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		result = append(result, slotJSONSchema{
+			ID:        slot.ID,
+			StartsAt:  slot.StartsAt,
+			EndsAt:    slot.EndsAt,
+			Available: slot.Available,
+		})
+	}
+	return result, nil
+}
 
+func getDeliveryJSONSchema(delivery *query.Delivery) (deliveryJSONSchema, error) {
 	var status string
-	switch del.Status {
+	switch delivery.Status {
 	case domain.DeliveryStatusScheduled:
 		status = "scheduled"
 	case domain.DeliveryStatusAwaitingDelivery:
@@ -64,47 +1440,242 @@ func getDeliveryHandler(_ *service.DeliveryService, qs query.Service, w http.Res
 		status = "delivered"
 	case domain.DeliveryStatusCancelled:
 		status = "cancelled"
+	case domain.DeliveryStatusFailed:
+		status = "failed"
+	case domain.DeliveryStatusAwaitingRedelivery:
+		status = "awaiting_redelivery"
+	case domain.DeliveryStatusReadyForPickup:
+		status = "ready_for_pickup"
 	default:
+		return deliveryJSONSchema{}, errors.New("unknown delivery status")
+	}
+
+	items := make([]deliveryItemJSONSchema, 0, len(delivery.Items))
+	for _, item := range delivery.Items {
+		items = append(items, deliveryItemJSONSchema{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return deliveryJSONSchema{
+		ID:            delivery.ID,
+		OrderID:       delivery.OrderID,
+		Items:         items,
+		Status:        status,
+		SlotID:        delivery.SlotID,
+		Address:       getPostalAddressJSONSchema(&delivery.Address),
+		CourierID:     delivery.CourierID,
+		PickupPointID: delivery.PickupPointID,
+		FailureReason: delivery.FailureReason,
+		Attempts:      delivery.Attempts,
+	}, nil
+}
+
+func writeAddress(w http.ResponseWriter, address *query.Address, err error) {
+	if errors.Is(err, query.ErrAddressNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(struct {
-		OrderID uuid.UUID `json:"order_id"`
-		Status  string    `json:"status"`
-		Address string    `json:"address"`
-	}{
-		del.OrderID,
-		status,
-		del.Address,
-	})
+	err = json.NewEncoder(w).Encode(getAddressJSONSchema(address))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getAddressJSONSchema(address *query.Address) addressJSONSchema {
+	return addressJSONSchema{
+		postalAddressJSONSchema: getPostalAddressJSONSchema(&address.Address),
+		ID:                      address.ID,
+		IsDefault:               address.IsDefault,
+	}
+}
+
+func getPostalAddressJSONSchema(address *domain.PostalAddress) postalAddressJSONSchema {
+	return postalAddressJSONSchema{
+		Country:    address.Country,
+		City:       address.City,
+		Street:     address.Street,
+		Building:   address.Building,
+		Apartment:  address.Apartment,
+		PostalCode: address.PostalCode,
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+	}
+}
+
+func writePickupPoints(w http.ResponseWriter, points []query.PickupPoint, err error) {
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]pickupPointJSONSchema, 0, len(points))
+	for _, point := range points {
+		result = append(result, getPickupPointJSONSchema(&point))
+	}
+
+	err = json.NewEncoder(w).Encode(result)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
 
-func healthCheckHandler(_ *service.DeliveryService, _ query.Service, w http.ResponseWriter, _ *http.Request) {
+func getPickupPointJSONSchema(point *query.PickupPoint) pickupPointJSONSchema {
+	hours := make([]openingHoursJSONSchema, 0, len(point.OpeningHours))
+	for _, h := range point.OpeningHours {
+		hours = append(hours, openingHoursJSONSchema{
+			Weekday: h.Weekday,
+			Opens:   h.Opens,
+			Closes:  h.Closes,
+		})
+	}
+
+	return pickupPointJSONSchema{
+		ID:           point.ID,
+		Name:         point.Name,
+		Address:      getPostalAddressJSONSchema(&point.Address),
+		OpeningHours: hours,
+		Capacity:     point.Capacity,
+		Available:    point.Available,
+		Active:       point.Active,
+	}
+}
+
+func getPickupPoint(point *pickupPointJSONSchema) *domain.PickupPoint {
+	hours := make([]domain.OpeningHours, 0, len(point.OpeningHours))
+	for _, h := range point.OpeningHours {
+		hours = append(hours, domain.OpeningHours{
+			Weekday: h.Weekday,
+			Opens:   h.Opens,
+			Closes:  h.Closes,
+		})
+	}
+
+	return &domain.PickupPoint{
+		ID:           point.ID,
+		Name:         point.Name,
+		Address:      getPostalAddress(&point.Address),
+		OpeningHours: hours,
+		Capacity:     point.Capacity,
+		Active:       point.Active,
+	}
+}
+
+func getPostalAddress(address *postalAddressJSONSchema) domain.PostalAddress {
+	return domain.PostalAddress{
+		Country:    address.Country,
+		City:       address.City,
+		Street:     address.Street,
+		Building:   address.Building,
+		Apartment:  address.Apartment,
+		PostalCode: address.PostalCode,
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+	}
+}
+
+func getZoneJSONSchema(zone *query.Zone) zoneJSONSchema {
+	ranges := make([]postalCodeRangeJSONSchema, 0, len(zone.PostalCodeRanges))
+	for _, postalCodeRange := range zone.PostalCodeRanges {
+		ranges = append(ranges, postalCodeRangeJSONSchema{
+			From: postalCodeRange.From,
+			To:   postalCodeRange.To,
+		})
+	}
+
+	rates := make([]shippingRateJSONSchema, 0, len(zone.Rates))
+	for _, rate := range zone.Rates {
+		rates = append(rates, shippingRateJSONSchema{
+			MaxWeight:      rate.MaxWeight,
+			MinOrderAmount: rate.MinOrderAmount,
+			Price:          rate.Price,
+		})
+	}
+
+	return zoneJSONSchema{
+		ID:                 zone.ID,
+		Name:               zone.Name,
+		Cities:             zone.Cities,
+		PostalCodeRanges:   ranges,
+		Rates:              rates,
+		FreeShippingAmount: zone.FreeShippingAmount,
+	}
+}
+
+func getZone(zone *zoneJSONSchema) *domain.Zone {
+	ranges := make([]domain.PostalCodeRange, 0, len(zone.PostalCodeRanges))
+	for _, postalCodeRange := range zone.PostalCodeRanges {
+		ranges = append(ranges, domain.PostalCodeRange{
+			From: postalCodeRange.From,
+			To:   postalCodeRange.To,
+		})
+	}
+
+	rates := make([]domain.ShippingRate, 0, len(zone.Rates))
+	for _, rate := range zone.Rates {
+		rates = append(rates, domain.ShippingRate{
+			MaxWeight:      rate.MaxWeight,
+			MinOrderAmount: rate.MinOrderAmount,
+			Price:          rate.Price,
+		})
+	}
+
+	return &domain.Zone{
+		ID:                 zone.ID,
+		Name:               zone.Name,
+		Cities:             zone.Cities,
+		PostalCodeRanges:   ranges,
+		Rates:              rates,
+		FreeShippingAmount: zone.FreeShippingAmount,
+	}
+}
+
+func healthCheckHandler(_ *service.DeliveryService, _ *service.AddressService, _ *service.ZoneService, _ *service.CourierService, _ *service.PickupPointService, _ query.Service, w http.ResponseWriter, _ *http.Request) {
 	_ = json.NewEncoder(w).Encode(struct {
 		Status string `json:"status"`
 	}{"OK"})
 }
 
+func parseAuthUserID(r *http.Request) (uuid.UUID, error) {
+	id := r.Header.Get("X-Auth-User-ID")
+	return uuid.Parse(id)
+}
+
 func parseUUID(str string) (uuid.UUID, error) {
 	return uuid.Parse(str)
 }
 
 func getHandlerFunc(
 	service *service.DeliveryService,
+	addressService *service.AddressService,
+	zoneService *service.ZoneService,
+	courierService *service.CourierService,
+	pickupPointService *service.PickupPointService,
 	query query.Service,
-	f func(*service.DeliveryService, query.Service, http.ResponseWriter, *http.Request),
+	f func(*service.DeliveryService, *service.AddressService, *service.ZoneService, *service.CourierService, *service.PickupPointService, query.Service, http.ResponseWriter, *http.Request),
 ) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		f(service, query, w, r)
+		f(service, addressService, zoneService, courierService, pickupPointService, query, w, r)
 	}
 }
 
-func NewHTTPHandler(service *service.DeliveryService, query query.Service, logger log.Logger) (http.Handler, error) {
+func NewHTTPHandler(
+	service *service.DeliveryService,
+	addressService *service.AddressService,
+	zoneService *service.ZoneService,
+	courierService *service.CourierService,
+	pickupPointService *service.PickupPointService,
+	query query.Service,
+	logger log.Logger,
+) (http.Handler, error) {
 	router := mux.NewRouter()
 
 	for _, route := range getRoutes() {
@@ -112,7 +1683,7 @@ func NewHTTPHandler(service *service.DeliveryService, query query.Service, logge
 			Methods(route.Method).
 			Path(route.Pattern).
 			Name(route.Name).
-			HandlerFunc(getHandlerFunc(service, query, route.Handler))
+			HandlerFunc(getHandlerFunc(service, addressService, zoneService, courierService, pickupPointService, query, route.Handler))
 	}
 
 	router.Use(transport.NewLoggingMiddleware(logger, []string{healthEndpoint}))
@@ -0,0 +1,39 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/order/app/service"
+)
+
+type deliveryFailedHandler struct {
+	service *service.OrderService
+}
+
+func (h *deliveryFailedHandler) TopicName() string {
+	return orderEventTopicName
+}
+
+func (h *deliveryFailedHandler) Type() string {
+	return "delivery_failed"
+}
+
+func (h *deliveryFailedHandler) Handle(msg *message.Message) error {
+	var orderID uuid.UUID
+	err := json.Unmarshal(msg.Body, &orderID)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.HandleDeliveryFailed(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to handle delivery failed: %w", err)
+	}
+	return nil
+}
+
+func NewDeliveryFailedHandler(service *service.OrderService) message.Handler {
+	return &deliveryFailedHandler{service: service}
+}
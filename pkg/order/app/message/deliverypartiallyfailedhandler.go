@@ -0,0 +1,53 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/order/app/service"
+)
+
+type deliveryPartiallyFailedHandler struct {
+	service *service.OrderService
+}
+
+func (h *deliveryPartiallyFailedHandler) TopicName() string {
+	return orderEventTopicName
+}
+
+func (h *deliveryPartiallyFailedHandler) Type() string {
+	return "delivery_partially_failed"
+}
+
+func (h *deliveryPartiallyFailedHandler) Handle(msg *message.Message) error {
+	var body struct {
+		OrderID uuid.UUID `json:"order_id"`
+		Items   []struct {
+			ProductID uuid.UUID `json:"product_id"`
+			Quantity  int       `json:"quantity"`
+		} `json:"items"`
+	}
+	err := json.Unmarshal(msg.Body, &body)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	items := make([]service.ReturnedItemData, 0, len(body.Items))
+	for _, item := range body.Items {
+		items = append(items, service.ReturnedItemData{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	err = h.service.HandleDeliveryPartiallyFailed(body.OrderID, items)
+	if err != nil {
+		return fmt.Errorf("failed to handle delivery partially failed: %w", err)
+	}
+	return nil
+}
+
+func NewDeliveryPartiallyFailedHandler(service *service.OrderService) message.Handler {
+	return &deliveryPartiallyFailedHandler{service: service}
+}
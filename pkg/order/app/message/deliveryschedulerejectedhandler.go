@@ -0,0 +1,39 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/order/app/service"
+)
+
+type deliveryScheduleRejectedHandler struct {
+	service *service.OrderService
+}
+
+func (h *deliveryScheduleRejectedHandler) TopicName() string {
+	return orderEventTopicName
+}
+
+func (h *deliveryScheduleRejectedHandler) Type() string {
+	return "delivery_schedule_rejected"
+}
+
+func (h *deliveryScheduleRejectedHandler) Handle(msg *message.Message) error {
+	var orderID uuid.UUID
+	err := json.Unmarshal(msg.Body, &orderID)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.HandleDeliveryScheduleRejected(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to handle delivery schedule rejected: %w", err)
+	}
+	return nil
+}
+
+func NewDeliveryScheduleRejectedHandler(service *service.OrderService) message.Handler {
+	return &deliveryScheduleRejectedHandler{service: service}
+}
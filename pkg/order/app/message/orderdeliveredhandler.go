@@ -0,0 +1,39 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/order/app/service"
+)
+
+type orderDeliveredHandler struct {
+	service *service.OrderService
+}
+
+func (h *orderDeliveredHandler) TopicName() string {
+	return orderEventTopicName
+}
+
+func (h *orderDeliveredHandler) Type() string {
+	return "order_delivered"
+}
+
+func (h *orderDeliveredHandler) Handle(msg *message.Message) error {
+	var orderID uuid.UUID
+	err := json.Unmarshal(msg.Body, &orderID)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.HandleOrderDelivered(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to handle order delivered: %w", err)
+	}
+	return nil
+}
+
+func NewOrderDeliveredHandler(service *service.OrderService) message.Handler {
+	return &orderDeliveredHandler{service: service}
+}
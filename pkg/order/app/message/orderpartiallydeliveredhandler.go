@@ -0,0 +1,39 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/order/app/service"
+)
+
+type orderPartiallyDeliveredHandler struct {
+	service *service.OrderService
+}
+
+func (h *orderPartiallyDeliveredHandler) TopicName() string {
+	return orderEventTopicName
+}
+
+func (h *orderPartiallyDeliveredHandler) Type() string {
+	return "order_partially_delivered"
+}
+
+func (h *orderPartiallyDeliveredHandler) Handle(msg *message.Message) error {
+	var orderID uuid.UUID
+	err := json.Unmarshal(msg.Body, &orderID)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.HandleOrderPartiallyDelivered(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to handle order partially delivered: %w", err)
+	}
+	return nil
+}
+
+func NewOrderPartiallyDeliveredHandler(service *service.OrderService) message.Handler {
+	return &orderPartiallyDeliveredHandler{service: service}
+}
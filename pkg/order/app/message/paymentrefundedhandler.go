@@ -0,0 +1,39 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/order/app/service"
+)
+
+type paymentRefundedHandler struct {
+	orderService *service.OrderService
+}
+
+func (h *paymentRefundedHandler) TopicName() string {
+	return orderEventTopicName
+}
+
+func (h *paymentRefundedHandler) Type() string {
+	return "payment_refunded"
+}
+
+func (h *paymentRefundedHandler) Handle(msg *message.Message) error {
+	var orderID uuid.UUID
+	err := json.Unmarshal(msg.Body, &orderID)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.orderService.HandlePaymentRefunded(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to handle payment refunded: %w", err)
+	}
+	return nil
+}
+
+func NewPaymentRefundedHandler(orderService *service.OrderService) message.Handler {
+	return &paymentRefundedHandler{orderService: orderService}
+}
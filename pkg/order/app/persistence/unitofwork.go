@@ -8,6 +8,8 @@ import (
 
 type PersistentProvider interface {
 	OrderRepository() domain.OrderRepository
+	PromoCodeRepository() domain.PromoCodeRepository
+	PromoCodeUsageRepository() domain.PromoCodeUsageRepository
 	IdempotenceKeyStore() idempotence.KeyStore
 	PaymentAPI() async.PaymentAPI
 	WarehouseAPI() async.WarehouseAPI
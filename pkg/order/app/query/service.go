@@ -8,21 +8,29 @@ import (
 
 type OrderItemData struct {
 	ID        uuid.UUID
+	VariantID uuid.UUID
 	ItemPrice int
 	Quantity  int
+	Discount  int
 }
 
 type OrderData struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	AddressID   uuid.UUID
-	Items       []OrderItemData
-	Status      domain.OrderStatus
-	TotalAmount int
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	AddressID      uuid.UUID
+	DeliverySlotID uuid.UUID
+	PickupPointID  uuid.UUID
+	Items          []OrderItemData
+	PromoCode      string
+	ShippingFee    int
+	Status         domain.OrderStatus
+	TotalAmount    int
+	RiskReasons    []string
 }
 
 var ErrOrderNotFound = errors.New("order not found")
 
 type Service interface {
 	GetOrderData(id uuid.UUID) (*OrderData, error)
+	ListOnHoldOrders() ([]OrderData, error)
 }
@@ -2,8 +2,22 @@ package async
 
 import "github.com/google/uuid"
 
+type DeliveryItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+type ScheduleDeliveryData struct {
+	OrderID       uuid.UUID
+	UserID        uuid.UUID
+	AddressID     uuid.UUID
+	PickupPointID uuid.UUID
+	SlotID        uuid.UUID
+	Items         []DeliveryItem
+}
+
 type DeliveryAPI interface {
-	ScheduleDelivery(orderID uuid.UUID, addressID uuid.UUID) error
+	ScheduleDelivery(data *ScheduleDeliveryData) error
 	CancelDeliverySchedule(orderID uuid.UUID) error
 	ProcessDelivery(orderID uuid.UUID) error
 }
@@ -5,7 +5,9 @@ import (
 )
 
 type PaymentAPI interface {
-	AuthorizeOrder(orderID uuid.UUID, totalAmount int) error
+	AuthorizeOrder(orderID uuid.UUID, userID uuid.UUID, totalAmount int) error
 	CompleteTransaction(orderID uuid.UUID) error
 	CancelPayment(orderID uuid.UUID) error
+	RefundPayment(orderID uuid.UUID) error
+	RefundPaymentPartially(orderID, refundID uuid.UUID, amount int) error
 }
@@ -12,4 +12,6 @@ type ItemQuantity struct {
 type WarehouseAPI interface {
 	ReserveItems(orderID uuid.UUID, items []ItemQuantity) error
 	RemoveItemsReservation(orderID uuid.UUID) error
+	ReturnItems(orderID uuid.UUID) error
+	ReturnItemsPartially(orderID uuid.UUID, items []ItemQuantity) error
 }
@@ -9,58 +9,152 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/order/app/persistence"
 	"github.com/klwxsrx/arch-course-project/pkg/order/app/service/async"
 	"github.com/klwxsrx/arch-course-project/pkg/order/domain"
+	"time"
 )
 
 var (
 	ErrOrderAlreadyCreated = errors.New("order with key is already created")
 	ErrEmptyOrder          = errors.New("empty or completely free order")
+	ErrOrderNotOnHold      = errors.New("order is not on hold")
 )
 
+type OrderItemData struct {
+	ID              uuid.UUID
+	VariantID       uuid.UUID
+	WarehouseItemID uuid.UUID
+	ItemPrice       int
+	Quantity        int
+	CategoryIDs     []uuid.UUID
+}
+
+type ReturnedItemData struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+type CreateOrderData struct {
+	UserID           uuid.UUID
+	UserRegisteredAt time.Time
+	AddressID        uuid.UUID
+	DeliverySlotID   uuid.UUID
+	PickupPointID    uuid.UUID
+	ShippingFee      int
+	Items            []OrderItemData
+	PromoCode        string
+}
+
 type OrderService struct {
-	ufw    persistence.UnitOfWork
-	logger log.Logger
+	ufw       persistence.UnitOfWork
+	riskRules []domain.RiskRule
+	logger    log.Logger
 }
 
-func (s *OrderService) Create(
-	idempotenceKey string,
-	userID uuid.UUID,
-	addressID uuid.UUID,
-	items []domain.OrderItem,
-) (uuid.UUID, error) {
+func (s *OrderService) Create(idempotenceKey string, data *CreateOrderData) (uuid.UUID, error) {
 	var orderID uuid.UUID
 	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
-		order, err := createOrder(idempotenceKey, userID, addressID, items, p)
-		if errors.Is(err, ErrOrderAlreadyCreated) || errors.Is(err, ErrEmptyOrder) {
+		order, err := createOrder(idempotenceKey, data, p)
+		if errors.Is(err, ErrOrderAlreadyCreated) {
+			orderID, err = p.OrderRepository().GetIDByIdempotenceKey(idempotenceKey)
+			if errors.Is(err, domain.ErrOrderNotFound) {
+				return ErrOrderAlreadyCreated // key is taken by a concurrent request that is not committed yet
+			}
+			return err
+		}
+		if errors.Is(err, ErrEmptyOrder) || isPromoCodeError(err) {
 			return err
 		}
 		if err != nil {
 			return fmt.Errorf("failed to create order: %w", err)
 		}
 
-		err = p.PaymentAPI().AuthorizeOrder(order.ID, order.TotalAmount)
+		err = s.checkOrderRisk(order, data.UserRegisteredAt, p)
 		if err != nil {
-			return fmt.Errorf("failed to authorize order: %w", err)
+			return fmt.Errorf("failed to check order risk: %w", err)
 		}
 
 		orderID = order.ID
 		return nil
 	})
 
-	if err == nil || errors.Is(err, ErrOrderAlreadyCreated) || errors.Is(err, ErrEmptyOrder) {
+	if errors.Is(err, ErrOrderAlreadyCreated) {
 		s.logger.With(log.Fields{
-			"userID": userID,
+			"userID": data.UserID,
+			"result": err,
+		}).Info("Create in progress")
+		return uuid.Nil, err
+	}
+	if err == nil || errors.Is(err, ErrEmptyOrder) {
+		s.logger.With(log.Fields{
+			"userID": data.UserID,
 			"order":  orderID,
 			"result": err,
 		}).Info("Create completed")
 		return orderID, nil
 	}
 
+	if isPromoCodeError(err) {
+		s.logger.With(log.Fields{
+			"userID":    data.UserID,
+			"promoCode": data.PromoCode,
+			"result":    err,
+		}).Info("Create rejected")
+		return uuid.Nil, err
+	}
+
 	s.logger.WithError(err).With(log.Fields{
-		"userID": userID,
+		"userID": data.UserID,
 	}).Error("Create failed")
 	return uuid.Nil, err
 }
 
+func (s *OrderService) ApproveOnHoldOrder(orderID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		order, err := p.OrderRepository().GetByID(orderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != domain.OrderStatusOnHold {
+			return ErrOrderNotOnHold
+		}
+
+		return approveOrder(order, p)
+	})
+	if errors.Is(err, domain.ErrOrderNotFound) || errors.Is(err, ErrOrderNotOnHold) {
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to approve on hold order")
+		return err
+	}
+
+	s.logger.With(log.Fields{"orderID": orderID}).Info("on hold order approved")
+	return nil
+}
+
+func (s *OrderService) RejectOnHoldOrder(orderID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		order, err := p.OrderRepository().GetByID(orderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != domain.OrderStatusOnHold {
+			return ErrOrderNotOnHold
+		}
+
+		return rejectOrder(order, p)
+	})
+	if errors.Is(err, domain.ErrOrderNotFound) || errors.Is(err, ErrOrderNotOnHold) {
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to reject on hold order")
+		return err
+	}
+
+	s.logger.With(log.Fields{"orderID": orderID}).Info("on hold order rejected")
+	return nil
+}
+
 func (s *OrderService) HandlePaymentAuthorized(orderID uuid.UUID) error {
 	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
 		order, err := p.OrderRepository().GetByID(orderID)
@@ -80,9 +174,15 @@ func (s *OrderService) HandlePaymentAuthorized(orderID uuid.UUID) error {
 		}
 
 		itemQuantity := make([]async.ItemQuantity, 0, len(order.Items))
+		itemIndexByID := make(map[uuid.UUID]int, len(order.Items))
 		for _, orderItem := range order.Items {
+			if i, ok := itemIndexByID[orderItem.StockItemID()]; ok {
+				itemQuantity[i].Quantity += orderItem.Quantity
+				continue
+			}
+			itemIndexByID[orderItem.StockItemID()] = len(itemQuantity)
 			itemQuantity = append(itemQuantity, async.ItemQuantity{
-				ItemID:   orderItem.ID,
+				ItemID:   orderItem.StockItemID(),
 				Quantity: orderItem.Quantity,
 			})
 		}
@@ -118,7 +218,28 @@ func (s *OrderService) HandleItemsReserved(orderID uuid.UUID) error {
 			return fmt.Errorf("failed to update order status: %w", err)
 		}
 
-		err = p.DeliveryAPI().ScheduleDelivery(order.ID, order.AddressID)
+		items := make([]async.DeliveryItem, 0, len(order.Items))
+		itemIndexByProductID := make(map[uuid.UUID]int, len(order.Items))
+		for _, item := range order.Items {
+			if i, ok := itemIndexByProductID[item.ID]; ok {
+				items[i].Quantity += item.Quantity
+				continue
+			}
+			itemIndexByProductID[item.ID] = len(items)
+			items = append(items, async.DeliveryItem{
+				ProductID: item.ID,
+				Quantity:  item.Quantity,
+			})
+		}
+
+		err = p.DeliveryAPI().ScheduleDelivery(&async.ScheduleDeliveryData{
+			OrderID:       order.ID,
+			UserID:        order.UserID,
+			AddressID:     order.AddressID,
+			PickupPointID: order.PickupPointID,
+			SlotID:        order.DeliverySlotID,
+			Items:         items,
+		})
 		if err != nil {
 			return fmt.Errorf("failed to schedule delivery: %w", err)
 		}
@@ -154,6 +275,11 @@ func (s *OrderService) HandleItemsOutOfStock(orderID uuid.UUID) error {
 		if err != nil {
 			return fmt.Errorf("failed to cancel payment: %w", err)
 		}
+
+		err = updatePromoCodeUsageStatus(order, domain.PromoCodeUsageStatusReleased, p.PromoCodeUsageRepository())
+		if err != nil {
+			return fmt.Errorf("failed to release promo code: %w", err)
+		}
 		return nil
 	})
 	if err != nil {
@@ -162,6 +288,46 @@ func (s *OrderService) HandleItemsOutOfStock(orderID uuid.UUID) error {
 	return err
 }
 
+func (s *OrderService) HandleDeliveryScheduleRejected(orderID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		order, err := p.OrderRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return errors.New("failed to get order not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		if order.Status != domain.OrderStatusItemsReserved {
+			return nil
+		}
+
+		err = updateOrderStatus(order, domain.OrderStatusCancelled, p.OrderRepository())
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		err = p.WarehouseAPI().RemoveItemsReservation(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to remove items reservation: %w", err)
+		}
+		err = p.PaymentAPI().CancelPayment(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to cancel payment: %w", err)
+		}
+
+		err = updatePromoCodeUsageStatus(order, domain.PromoCodeUsageStatusReleased, p.PromoCodeUsageRepository())
+		if err != nil {
+			return fmt.Errorf("failed to release promo code: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to handle delivery schedule rejected")
+	}
+	return err
+}
+
 func (s *OrderService) HandleDeliveryScheduled(orderID uuid.UUID) error {
 	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
 		order, err := p.OrderRepository().GetByID(orderID)
@@ -217,6 +383,11 @@ func (s *OrderService) HandlePaymentCompleted(orderID uuid.UUID) error {
 			return fmt.Errorf("failed to process delivery: %w", err)
 		}
 
+		err = updatePromoCodeUsageStatus(order, domain.PromoCodeUsageStatusUsed, p.PromoCodeUsageRepository())
+		if err != nil {
+			return fmt.Errorf("failed to mark promo code used: %w", err)
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -253,6 +424,11 @@ func (s *OrderService) HandlePaymentCompletionRejected(orderID uuid.UUID) error
 			return fmt.Errorf("failed to remove items reservation: %w", err)
 		}
 
+		err = updatePromoCodeUsageStatus(order, domain.PromoCodeUsageStatusReleased, p.PromoCodeUsageRepository())
+		if err != nil {
+			return fmt.Errorf("failed to release promo code: %w", err)
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -261,11 +437,235 @@ func (s *OrderService) HandlePaymentCompletionRejected(orderID uuid.UUID) error
 	return err
 }
 
+func (s *OrderService) HandleOrderDelivered(orderID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		order, err := p.OrderRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return errors.New("failed to get order not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		if order.Status != domain.OrderStatusSentToDelivery && order.Status != domain.OrderStatusPartiallyDelivered {
+			return nil
+		}
+
+		err = updateOrderStatus(order, domain.OrderStatusDelivered, p.OrderRepository())
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to handle order delivered")
+	}
+	return err
+}
+
+func (s *OrderService) HandleOrderPartiallyDelivered(orderID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		order, err := p.OrderRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return errors.New("failed to get order not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		if order.Status != domain.OrderStatusSentToDelivery {
+			return nil
+		}
+
+		err = updateOrderStatus(order, domain.OrderStatusPartiallyDelivered, p.OrderRepository())
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to handle order partially delivered")
+	}
+	return err
+}
+
+func (s *OrderService) HandleDeliveryFailed(orderID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		order, err := p.OrderRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return errors.New("failed to get order not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		if order.Status != domain.OrderStatusSentToDelivery {
+			return nil
+		}
+
+		err = updateOrderStatus(order, domain.OrderStatusReturning, p.OrderRepository())
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		err = p.WarehouseAPI().ReturnItems(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to return items: %w", err)
+		}
+
+		err = p.PaymentAPI().RefundPayment(orderID)
+		if err != nil {
+			return fmt.Errorf("failed to refund payment: %w", err)
+		}
+
+		err = updatePromoCodeUsageStatus(order, domain.PromoCodeUsageStatusReleased, p.PromoCodeUsageRepository())
+		if err != nil {
+			return fmt.Errorf("failed to release promo code: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to handle delivery failed")
+	}
+	return err
+}
+
+func (s *OrderService) HandleDeliveryPartiallyFailed(orderID uuid.UUID, returnedItems []ReturnedItemData) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		order, err := p.OrderRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return errors.New("failed to get order not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		if order.Status != domain.OrderStatusSentToDelivery && order.Status != domain.OrderStatusPartiallyDelivered {
+			return nil
+		}
+
+		err = updateOrderStatus(order, domain.OrderStatusPartiallyReturned, p.OrderRepository())
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		itemQuantity, refundAmount := getReturnedOrderItems(order, returnedItems)
+		if len(itemQuantity) == 0 {
+			return nil
+		}
+
+		err = p.WarehouseAPI().ReturnItemsPartially(orderID, itemQuantity)
+		if err != nil {
+			return fmt.Errorf("failed to return items: %w", err)
+		}
+
+		if refundAmount > 0 {
+			err = p.PaymentAPI().RefundPaymentPartially(orderID, uuid.New(), refundAmount)
+			if err != nil {
+				return fmt.Errorf("failed to refund payment partially: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to handle delivery partially failed")
+	}
+	return err
+}
+
+func (s *OrderService) HandlePaymentRefunded(orderID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		order, err := p.OrderRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return errors.New("failed to get order not found")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		if order.Status != domain.OrderStatusSentToDelivery &&
+			order.Status != domain.OrderStatusDelivered &&
+			order.Status != domain.OrderStatusPartiallyDelivered &&
+			order.Status != domain.OrderStatusReturning {
+			return nil
+		}
+
+		err = updateOrderStatus(order, domain.OrderStatusRefunded, p.OrderRepository())
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to handle payment refunded")
+	}
+	return err
+}
+
+func (s *OrderService) checkOrderRisk(
+	order *domain.Order,
+	userRegisteredAt time.Time,
+	p persistence.PersistentProvider,
+) error {
+	decision, reasons, err := domain.AssessRisk(s.riskRules, &domain.RiskCheck{
+		Order:            order,
+		UserRegisteredAt: userRegisteredAt,
+		CheckedAt:        time.Now(),
+	}, p.OrderRepository())
+	if err != nil {
+		return err
+	}
+	order.RiskReasons = reasons
+
+	switch decision {
+	case domain.RiskDecisionApprove:
+		return approveOrder(order, p)
+	case domain.RiskDecisionReview:
+		s.logger.With(log.Fields{
+			"orderID": order.ID,
+			"reasons": reasons,
+		}).Info("order is put on hold for manual review")
+		return updateOrderStatus(order, domain.OrderStatusOnHold, p.OrderRepository())
+	case domain.RiskDecisionReject:
+		s.logger.With(log.Fields{
+			"orderID": order.ID,
+			"reasons": reasons,
+		}).Info("order is rejected by risk check")
+		return rejectOrder(order, p)
+	default:
+		return fmt.Errorf("unknown risk decision %v", decision)
+	}
+}
+
+func approveOrder(order *domain.Order, p persistence.PersistentProvider) error {
+	err := updateOrderStatus(order, domain.OrderStatusCreated, p.OrderRepository())
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	err = p.PaymentAPI().AuthorizeOrder(order.ID, order.UserID, order.TotalAmount)
+	if err != nil {
+		return fmt.Errorf("failed to authorize order: %w", err)
+	}
+	return nil
+}
+
+func rejectOrder(order *domain.Order, p persistence.PersistentProvider) error {
+	err := updateOrderStatus(order, domain.OrderStatusCancelled, p.OrderRepository())
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	err = updatePromoCodeUsageStatus(order, domain.PromoCodeUsageStatusReleased, p.PromoCodeUsageRepository())
+	if err != nil {
+		return fmt.Errorf("failed to release promo code: %w", err)
+	}
+	return nil
+}
+
 func createOrder(
 	idempotenceKey string,
-	userID uuid.UUID,
-	addressID uuid.UUID,
-	items []domain.OrderItem,
+	data *CreateOrderData,
 	p persistence.PersistentProvider,
 ) (*domain.Order, error) {
 	err := p.IdempotenceKeyStore().StoreUnique(idempotenceKey)
@@ -276,35 +676,129 @@ func createOrder(
 		return nil, err
 	}
 
-	totalAmount := calculateTotalAmount(items)
+	orderItems := make([]domain.OrderItem, 0, len(data.Items))
+	for _, item := range data.Items {
+		orderItems = append(orderItems, domain.OrderItem{
+			ID:              item.ID,
+			VariantID:       item.VariantID,
+			WarehouseItemID: item.WarehouseItemID,
+			ItemPrice:       item.ItemPrice,
+			Quantity:        item.Quantity,
+		})
+	}
+
+	if data.PromoCode != "" {
+		discounts, err := calculatePromoCodeDiscounts(data.PromoCode, data.UserID, data.Items, true, p)
+		if err != nil {
+			return nil, err
+		}
+		for i := range orderItems {
+			orderItems[i].Discount = discounts[i]
+		}
+	}
+
+	totalAmount := calculateTotalAmount(orderItems)
 	if totalAmount == 0 {
 		return nil, ErrEmptyOrder
 	}
 
 	order := &domain.Order{
-		ID:          p.OrderRepository().NextID(),
-		UserID:      userID,
-		AddressID:   addressID,
-		Items:       items,
-		Status:      domain.OrderStatusCreated,
-		TotalAmount: totalAmount,
+		ID:             p.OrderRepository().NextID(),
+		IdempotenceKey: idempotenceKey,
+		UserID:         data.UserID,
+		AddressID:      data.AddressID,
+		DeliverySlotID: data.DeliverySlotID,
+		PickupPointID:  data.PickupPointID,
+		Items:          orderItems,
+		PromoCode:      data.PromoCode,
+		ShippingFee:    data.ShippingFee,
+		Status:         domain.OrderStatusCreated,
+		TotalAmount:    totalAmount + data.ShippingFee,
 	}
 
 	err = p.OrderRepository().Store(order)
 	if err != nil {
 		return nil, err
 	}
+
+	if data.PromoCode != "" {
+		err = p.PromoCodeUsageRepository().Store(&domain.PromoCodeUsage{
+			Code:    data.PromoCode,
+			UserID:  data.UserID,
+			OrderID: order.ID,
+			Status:  domain.PromoCodeUsageStatusReserved,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve promo code: %w", err)
+		}
+	}
 	return order, nil
 }
 
+// getReturnedOrderItems matches returned product quantities with order items, which may be split by variants,
+// and calculates the amount paid for them
+func getReturnedOrderItems(order *domain.Order, returnedItems []ReturnedItemData) ([]async.ItemQuantity, int) {
+	remaining := make(map[uuid.UUID]int, len(returnedItems))
+	for _, item := range returnedItems {
+		remaining[item.ProductID] += item.Quantity
+	}
+
+	var itemQuantity []async.ItemQuantity
+	itemIndexByID := make(map[uuid.UUID]int, len(order.Items))
+	var amount int
+	for _, orderItem := range order.Items {
+		quantity := remaining[orderItem.ID]
+		if quantity > orderItem.Quantity {
+			quantity = orderItem.Quantity
+		}
+		if quantity <= 0 {
+			continue
+		}
+		remaining[orderItem.ID] -= quantity
+
+		amount += orderItem.ItemPrice*quantity - orderItem.Discount*quantity/orderItem.Quantity
+		if i, ok := itemIndexByID[orderItem.StockItemID()]; ok {
+			itemQuantity[i].Quantity += quantity
+			continue
+		}
+		itemIndexByID[orderItem.StockItemID()] = len(itemQuantity)
+		itemQuantity = append(itemQuantity, async.ItemQuantity{
+			ItemID:   orderItem.StockItemID(),
+			Quantity: quantity,
+		})
+	}
+	return itemQuantity, amount
+}
+
 func calculateTotalAmount(items []domain.OrderItem) int {
 	var result int
 	for _, item := range items {
-		result += item.ItemPrice * item.Quantity
+		result += item.ItemPrice*item.Quantity - item.Discount
 	}
 	return result
 }
 
+func updatePromoCodeUsageStatus(
+	order *domain.Order,
+	new domain.PromoCodeUsageStatus,
+	repo domain.PromoCodeUsageRepository,
+) error {
+	if order.PromoCode == "" {
+		return nil
+	}
+
+	usage, err := repo.GetByOrderID(order.ID)
+	if err != nil {
+		return err
+	}
+	if usage.Status != domain.PromoCodeUsageStatusReserved {
+		return nil
+	}
+
+	usage.Status = new
+	return repo.Store(usage)
+}
+
 func updateOrderStatus(
 	order *domain.Order,
 	new domain.OrderStatus,
@@ -320,10 +814,12 @@ func updateOrderStatus(
 
 func NewOrderService(
 	ufw persistence.UnitOfWork,
+	riskRules []domain.RiskRule,
 	logger log.Logger,
 ) *OrderService {
 	return &OrderService{
-		ufw:    ufw,
-		logger: logger,
+		ufw:       ufw,
+		riskRules: riskRules,
+		logger:    logger,
 	}
 }
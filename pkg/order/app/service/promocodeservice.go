@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"github.com/klwxsrx/arch-course-project/pkg/order/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/order/domain"
+	"strings"
+	"time"
+)
+
+var ErrInvalidPromoCode = errors.New("invalid promo code")
+
+type PromoCodeService struct {
+	ufw    persistence.UnitOfWork
+	logger log.Logger
+}
+
+func (s *PromoCodeService) Store(promoCode *domain.PromoCode) error {
+	err := validatePromoCode(promoCode)
+	if err != nil {
+		return err
+	}
+
+	err = s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		return p.PromoCodeRepository().Store(promoCode)
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"code": promoCode.Code}).Error("failed to store promo code")
+	}
+	return err
+}
+
+func (s *PromoCodeService) Get(code string) (*domain.PromoCode, error) {
+	var promoCode *domain.PromoCode
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		var err error
+		promoCode, err = p.PromoCodeRepository().GetByCode(code)
+		return err
+	})
+	return promoCode, err
+}
+
+// CalculateDiscounts previews item discounts without reserving the promo code
+func (s *PromoCodeService) CalculateDiscounts(code string, userID uuid.UUID, items []OrderItemData) ([]int, error) {
+	var discounts []int
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		var err error
+		discounts, err = calculatePromoCodeDiscounts(code, userID, items, false, p)
+		return err
+	})
+	if err != nil && !isPromoCodeError(err) {
+		s.logger.WithError(err).With(log.Fields{"code": code, "userID": userID}).Error("failed to calculate discounts")
+	}
+	return discounts, err
+}
+
+func calculatePromoCodeDiscounts(
+	code string,
+	userID uuid.UUID,
+	items []OrderItemData,
+	lock bool,
+	p persistence.PersistentProvider,
+) ([]int, error) {
+	var promoCode *domain.PromoCode
+	var err error
+	if lock {
+		promoCode, err = p.PromoCodeRepository().LockByCode(code)
+	} else {
+		promoCode, err = p.PromoCodeRepository().GetByCode(code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	usageCount, err := p.PromoCodeUsageRepository().CountActive(code, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count promo code usages: %w", err)
+	}
+
+	discountItems := make([]domain.DiscountItem, 0, len(items))
+	for _, item := range items {
+		discountItems = append(discountItems, domain.DiscountItem{
+			ProductID:   item.ID,
+			CategoryIDs: item.CategoryIDs,
+			ItemPrice:   item.ItemPrice,
+			Quantity:    item.Quantity,
+		})
+	}
+	return promoCode.CalculateDiscounts(discountItems, usageCount, time.Now())
+}
+
+func validatePromoCode(promoCode *domain.PromoCode) error {
+	if strings.TrimSpace(promoCode.Code) == "" {
+		return fmt.Errorf("%w: empty code", ErrInvalidPromoCode)
+	}
+	if promoCode.DiscountValue <= 0 {
+		return fmt.Errorf("%w: discount value %d", ErrInvalidPromoCode, promoCode.DiscountValue)
+	}
+	if promoCode.DiscountType == domain.DiscountTypePercentage && promoCode.DiscountValue > 100 {
+		return fmt.Errorf("%w: discount percentage %d", ErrInvalidPromoCode, promoCode.DiscountValue)
+	}
+	if promoCode.MinBasketAmount < 0 || promoCode.PerUserLimit < 0 {
+		return fmt.Errorf("%w: negative limits", ErrInvalidPromoCode)
+	}
+	if !promoCode.ValidFrom.Before(promoCode.ValidTo) {
+		return fmt.Errorf("%w: validity window", ErrInvalidPromoCode)
+	}
+	return nil
+}
+
+func isPromoCodeError(err error) bool {
+	return errors.Is(err, domain.ErrPromoCodeNotFound) ||
+		errors.Is(err, domain.ErrPromoCodeNotActive) ||
+		errors.Is(err, domain.ErrPromoCodeMinBasketNotReached) ||
+		errors.Is(err, domain.ErrPromoCodeUsageLimitExceeded) ||
+		errors.Is(err, domain.ErrPromoCodeNotApplicable)
+}
+
+func NewPromoCodeService(ufw persistence.UnitOfWork, logger log.Logger) *PromoCodeService {
+	return &PromoCodeService{ufw: ufw, logger: logger}
+}
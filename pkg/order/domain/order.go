@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"github.com/google/uuid"
+	"time"
 )
 
 type OrderStatus int
@@ -15,21 +16,42 @@ const (
 	OrderStatusSentToDelivery
 	OrderStatusDelivered
 	OrderStatusCancelled
+	OrderStatusRefunded
+	OrderStatusOnHold
+	OrderStatusReturning
+	OrderStatusPartiallyDelivered
+	OrderStatusPartiallyReturned
 )
 
 type OrderItem struct {
-	ID        uuid.UUID
-	ItemPrice int
-	Quantity  int
+	ID              uuid.UUID
+	VariantID       uuid.UUID
+	WarehouseItemID uuid.UUID
+	ItemPrice       int
+	Quantity        int
+	Discount        int
+}
+
+func (i OrderItem) StockItemID() uuid.UUID {
+	if i.WarehouseItemID == uuid.Nil {
+		return i.ID
+	}
+	return i.WarehouseItemID
 }
 
 type Order struct {
-	ID          uuid.UUID
-	UserID      uuid.UUID
-	AddressID   uuid.UUID
-	Items       []OrderItem
-	Status      OrderStatus
-	TotalAmount int
+	ID             uuid.UUID
+	IdempotenceKey string
+	UserID         uuid.UUID
+	AddressID      uuid.UUID
+	DeliverySlotID uuid.UUID
+	PickupPointID  uuid.UUID
+	Items          []OrderItem
+	PromoCode      string
+	ShippingFee    int
+	Status         OrderStatus
+	TotalAmount    int
+	RiskReasons    []string
 }
 
 var ErrOrderNotFound = errors.New("order not found")
@@ -37,5 +59,7 @@ var ErrOrderNotFound = errors.New("order not found")
 type OrderRepository interface {
 	NextID() uuid.UUID
 	GetByID(id uuid.UUID) (*Order, error)
+	GetIDByIdempotenceKey(key string) (uuid.UUID, error)
+	CountByUserCreatedSince(userID uuid.UUID, since time.Time) (int, error)
 	Store(order *Order) error
 }
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+type DiscountType int
+
+const (
+	DiscountTypePercentage DiscountType = iota
+	DiscountTypeFixed
+)
+
+type PromoCode struct {
+	Code            string
+	DiscountType    DiscountType
+	DiscountValue   int
+	MinBasketAmount int
+	PerUserLimit    int
+	ValidFrom       time.Time
+	ValidTo         time.Time
+	ProductIDs      []uuid.UUID
+	CategoryIDs     []uuid.UUID
+}
+
+type PromoCodeUsageStatus int
+
+const (
+	PromoCodeUsageStatusReserved PromoCodeUsageStatus = iota
+	PromoCodeUsageStatusUsed
+	PromoCodeUsageStatusReleased
+)
+
+type PromoCodeUsage struct {
+	Code    string
+	UserID  uuid.UUID
+	OrderID uuid.UUID
+	Status  PromoCodeUsageStatus
+}
+
+type DiscountItem struct {
+	ProductID   uuid.UUID
+	CategoryIDs []uuid.UUID
+	ItemPrice   int
+	Quantity    int
+}
+
+var (
+	ErrPromoCodeNotFound            = errors.New("promo code not found")
+	ErrPromoCodeUsageNotFound       = errors.New("promo code usage not found")
+	ErrPromoCodeNotActive           = errors.New("promo code is not active")
+	ErrPromoCodeMinBasketNotReached = errors.New("promo code minimal basket amount is not reached")
+	ErrPromoCodeUsageLimitExceeded  = errors.New("promo code usage limit exceeded")
+	ErrPromoCodeNotApplicable       = errors.New("promo code is not applicable to items")
+)
+
+type PromoCodeRepository interface {
+	GetByCode(code string) (*PromoCode, error)
+	LockByCode(code string) (*PromoCode, error)
+	Store(promoCode *PromoCode) error
+}
+
+type PromoCodeUsageRepository interface {
+	CountActive(code string, userID uuid.UUID) (int, error)
+	GetByOrderID(orderID uuid.UUID) (*PromoCodeUsage, error)
+	Store(usage *PromoCodeUsage) error
+}
+
+// CalculateDiscounts returns discount amount for each item in the same order as items
+func (c *PromoCode) CalculateDiscounts(items []DiscountItem, userUsageCount int, now time.Time) ([]int, error) {
+	if now.Before(c.ValidFrom) || !now.Before(c.ValidTo) {
+		return nil, ErrPromoCodeNotActive
+	}
+	if c.PerUserLimit > 0 && userUsageCount >= c.PerUserLimit {
+		return nil, ErrPromoCodeUsageLimitExceeded
+	}
+
+	var basketAmount, eligibleAmount int
+	eligible := make([]bool, len(items))
+	for i, item := range items {
+		lineAmount := item.ItemPrice * item.Quantity
+		basketAmount += lineAmount
+		if c.isApplicable(&item) {
+			eligible[i] = true
+			eligibleAmount += lineAmount
+		}
+	}
+	if basketAmount < c.MinBasketAmount {
+		return nil, ErrPromoCodeMinBasketNotReached
+	}
+	if eligibleAmount == 0 {
+		return nil, ErrPromoCodeNotApplicable
+	}
+
+	discounts := make([]int, len(items))
+	switch c.DiscountType {
+	case DiscountTypePercentage:
+		for i, item := range items {
+			if eligible[i] {
+				discounts[i] = item.ItemPrice * item.Quantity * c.DiscountValue / 100
+			}
+		}
+	case DiscountTypeFixed:
+		totalDiscount := c.DiscountValue
+		if totalDiscount > eligibleAmount {
+			totalDiscount = eligibleAmount
+		}
+
+		// distribute fixed discount proportionally, the rest goes to the last eligible item
+		lastEligible, distributed := 0, 0
+		for i, item := range items {
+			if !eligible[i] {
+				continue
+			}
+			discounts[i] = totalDiscount * item.ItemPrice * item.Quantity / eligibleAmount
+			distributed += discounts[i]
+			lastEligible = i
+		}
+		discounts[lastEligible] += totalDiscount - distributed
+	}
+	return discounts, nil
+}
+
+func (c *PromoCode) isApplicable(item *DiscountItem) bool {
+	if len(c.ProductIDs) == 0 && len(c.CategoryIDs) == 0 {
+		return true
+	}
+	for _, productID := range c.ProductIDs {
+		if productID == item.ProductID {
+			return true
+		}
+	}
+	for _, categoryID := range c.CategoryIDs {
+		for _, itemCategoryID := range item.CategoryIDs {
+			if categoryID == itemCategoryID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"time"
+)
+
+type RiskDecision int
+
+const (
+	RiskDecisionApprove RiskDecision = iota
+	RiskDecisionReview
+	RiskDecisionReject
+)
+
+type RiskCheck struct {
+	Order            *Order
+	UserRegisteredAt time.Time
+	CheckedAt        time.Time
+}
+
+type RiskRule interface {
+	Name() string
+	Check(check *RiskCheck, orders OrderRepository) (RiskDecision, error)
+}
+
+func AssessRisk(rules []RiskRule, check *RiskCheck, orders OrderRepository) (RiskDecision, []string, error) {
+	result := RiskDecisionApprove
+	var reasons []string
+	for _, rule := range rules {
+		decision, err := rule.Check(check, orders)
+		if err != nil {
+			return RiskDecisionApprove, nil, err
+		}
+		if decision == RiskDecisionApprove {
+			continue
+		}
+
+		reasons = append(reasons, rule.Name())
+		if decision > result {
+			result = decision
+		}
+	}
+	return result, reasons, nil
+}
+
+type AmountThresholdRule struct {
+	ReviewAmount int
+	RejectAmount int
+}
+
+func (r *AmountThresholdRule) Name() string {
+	return "amount_threshold"
+}
+
+func (r *AmountThresholdRule) Check(check *RiskCheck, _ OrderRepository) (RiskDecision, error) {
+	switch {
+	case r.RejectAmount > 0 && check.Order.TotalAmount >= r.RejectAmount:
+		return RiskDecisionReject, nil
+	case r.ReviewAmount > 0 && check.Order.TotalAmount >= r.ReviewAmount:
+		return RiskDecisionReview, nil
+	default:
+		return RiskDecisionApprove, nil
+	}
+}
+
+type OrderVelocityRule struct {
+	Period    time.Duration
+	MaxOrders int
+}
+
+func (r *OrderVelocityRule) Name() string {
+	return "order_velocity"
+}
+
+func (r *OrderVelocityRule) Check(check *RiskCheck, orders OrderRepository) (RiskDecision, error) {
+	count, err := orders.CountByUserCreatedSince(check.Order.UserID, check.CheckedAt.Add(-r.Period))
+	if err != nil {
+		return RiskDecisionApprove, err
+	}
+	if count > r.MaxOrders {
+		return RiskDecisionReview, nil
+	}
+	return RiskDecisionApprove, nil
+}
+
+type NewAccountHighValueRule struct {
+	AccountAge time.Duration
+	Amount     int
+}
+
+func (r *NewAccountHighValueRule) Name() string {
+	return "new_account_high_value"
+}
+
+func (r *NewAccountHighValueRule) Check(check *RiskCheck, _ OrderRepository) (RiskDecision, error) {
+	if check.UserRegisteredAt.IsZero() {
+		return RiskDecisionApprove, nil // registration date is unknown for sessions created before it was tracked
+	}
+	if check.CheckedAt.Sub(check.UserRegisteredAt) < r.AccountAge && check.Order.TotalAmount >= r.Amount {
+		return RiskDecisionReview, nil
+	}
+	return RiskDecisionApprove, nil
+}
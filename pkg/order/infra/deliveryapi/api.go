@@ -14,13 +14,34 @@ type apiClient struct {
 	eventDispatcher event.Dispatcher
 }
 
-func (a *apiClient) ScheduleDelivery(orderID uuid.UUID, addressID uuid.UUID) error {
+func (a *apiClient) ScheduleDelivery(data *async.ScheduleDeliveryData) error {
+	type itemSchema struct {
+		ProductID uuid.UUID `json:"product_id"`
+		Quantity  int       `json:"quantity"`
+	}
+
+	items := make([]itemSchema, 0, len(data.Items))
+	for _, item := range data.Items {
+		items = append(items, itemSchema{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
 	body := struct {
-		OrderID   uuid.UUID `json:"order_id"`
-		AddressID uuid.UUID `json:"address_id"`
+		OrderID       uuid.UUID    `json:"order_id"`
+		UserID        uuid.UUID    `json:"user_id"`
+		AddressID     uuid.UUID    `json:"address_id"`
+		PickupPointID uuid.UUID    `json:"pickup_point_id"`
+		SlotID        uuid.UUID    `json:"slot_id"`
+		Items         []itemSchema `json:"items"`
 	}{
-		OrderID:   orderID,
-		AddressID: addressID,
+		OrderID:       data.OrderID,
+		UserID:        data.UserID,
+		AddressID:     data.AddressID,
+		PickupPointID: data.PickupPointID,
+		SlotID:        data.SlotID,
+		Items:         items,
 	}
 
 	jsonBody, err := json.Marshal(body)
@@ -31,7 +52,7 @@ func (a *apiClient) ScheduleDelivery(orderID uuid.UUID, addressID uuid.UUID) err
 	err = a.eventDispatcher.Dispatch(&event.Event{
 		Type:      "schedule_delivery",
 		TopicName: deliveryEventTopicName,
-		Key:       orderID.String(),
+		Key:       data.OrderID.String(),
 		Body:      jsonBody,
 	})
 	if err != nil {
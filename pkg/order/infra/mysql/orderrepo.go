@@ -8,8 +8,11 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
 	"github.com/klwxsrx/arch-course-project/pkg/order/domain"
 	"strings"
+	"time"
 )
 
+const riskReasonsSeparator = ","
+
 type orderRepo struct {
 	client mysql.Client
 }
@@ -20,7 +23,7 @@ func (r *orderRepo) NextID() uuid.UUID {
 
 func (r *orderRepo) GetByID(id uuid.UUID) (*domain.Order, error) {
 	const orderQuery = `
-		SELECT id, user_id, address_id, status, total_amount
+		SELECT id, user_id, address_id, delivery_slot_id, pickup_point_id, promo_code, shipping_fee, status, total_amount, risk_reasons
 		FROM ` + " `order` " + `
 		WHERE id = ?
 	`
@@ -40,7 +43,7 @@ func (r *orderRepo) GetByID(id uuid.UUID) (*domain.Order, error) {
 	}
 
 	const itemsQuery = `
-		SELECT id, price, quantity
+		SELECT id, variant_id, warehouse_item_id, price, quantity, discount
 		FROM order_item
 		WHERE order_id = ?
 	`
@@ -54,29 +57,71 @@ func (r *orderRepo) GetByID(id uuid.UUID) (*domain.Order, error) {
 	orderItems := make([]domain.OrderItem, 0, len(sqlxItems))
 	for _, sqlxItem := range sqlxItems {
 		orderItems = append(orderItems, domain.OrderItem{
-			ID:        sqlxItem.ID,
-			ItemPrice: sqlxItem.Price,
-			Quantity:  sqlxItem.Quantity,
+			ID:              sqlxItem.ID,
+			VariantID:       sqlxItem.VariantID,
+			WarehouseItemID: sqlxItem.WarehouseItemID,
+			ItemPrice:       sqlxItem.Price,
+			Quantity:        sqlxItem.Quantity,
+			Discount:        sqlxItem.Discount,
 		})
 	}
 
 	return &domain.Order{
-		ID:          orderSqlx.ID,
-		UserID:      orderSqlx.UserID,
-		AddressID:   orderSqlx.AddressID,
-		Items:       orderItems,
-		Status:      domain.OrderStatus(orderSqlx.Status),
-		TotalAmount: orderSqlx.TotalAmount,
+		ID:             orderSqlx.ID,
+		UserID:         orderSqlx.UserID,
+		AddressID:      orderSqlx.AddressID,
+		DeliverySlotID: orderSqlx.DeliverySlotID,
+		PickupPointID:  orderSqlx.PickupPointID,
+		Items:          orderItems,
+		PromoCode:      orderSqlx.PromoCode.String,
+		ShippingFee:    orderSqlx.ShippingFee,
+		Status:         domain.OrderStatus(orderSqlx.Status),
+		TotalAmount:    orderSqlx.TotalAmount,
+		RiskReasons:    splitRiskReasons(orderSqlx.RiskReasons),
 	}, nil
 }
 
+func (r *orderRepo) GetIDByIdempotenceKey(key string) (uuid.UUID, error) {
+	const orderQuery = `
+		SELECT id
+		FROM ` + " `order` " + `
+		WHERE idempotence_key = ?
+	`
+
+	var id uuid.UUID
+	err := r.client.Get(&id, orderQuery, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, domain.ErrOrderNotFound
+	}
+	return id, err
+}
+
+func (r *orderRepo) CountByUserCreatedSince(userID uuid.UUID, since time.Time) (int, error) {
+	const countQuery = `
+		SELECT COUNT(*)
+		FROM ` + " `order` " + `
+		WHERE user_id = ? AND created_at >= ?
+	`
+
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.client.Get(&count, countQuery, binaryUserID, since)
+	return count, err
+}
+
 func (r *orderRepo) Store(order *domain.Order) error {
 	const orderQuery = `
-		INSERT INTO` + " `order` " + `(id, user_id, address_id, status, total_amount, created_at)
-		VALUES (?, ?, ?, ?, ?, NOW())
+		INSERT INTO` + " `order` " + `(id, idempotence_key, user_id, address_id, delivery_slot_id, pickup_point_id, promo_code, shipping_fee, status, total_amount, risk_reasons, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())
 		ON DUPLICATE KEY UPDATE
-			user_id = VALUES(user_id), address_id = VALUES(address_id), status = VALUES(status),
-			total_amount = VALUES(total_amount), updated_at = NOW()
+			user_id = VALUES(user_id), address_id = VALUES(address_id), delivery_slot_id = VALUES(delivery_slot_id),
+			pickup_point_id = VALUES(pickup_point_id),			promo_code = VALUES(promo_code), shipping_fee = VALUES(shipping_fee),
+			status = VALUES(status), total_amount = VALUES(total_amount), risk_reasons = VALUES(risk_reasons),
+			updated_at = NOW()
 	`
 
 	binaryOrderID, err := order.ID.MarshalBinary()
@@ -89,12 +134,48 @@ func (r *orderRepo) Store(order *domain.Order) error {
 		return err
 	}
 
-	binaryAddressID, err := order.AddressID.MarshalBinary()
-	if err != nil {
-		return err
+	var binaryAddressID []byte
+	if order.AddressID != uuid.Nil {
+		binaryAddressID, err = order.AddressID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	}
+
+	var binaryDeliverySlotID []byte
+	if order.DeliverySlotID != uuid.Nil {
+		binaryDeliverySlotID, err = order.DeliverySlotID.MarshalBinary()
+		if err != nil {
+			return err
+		}
 	}
 
-	_, err = r.client.Exec(orderQuery, binaryOrderID, binaryUserID, binaryAddressID, int(order.Status), order.TotalAmount)
+	var binaryPickupPointID []byte
+	if order.PickupPointID != uuid.Nil {
+		binaryPickupPointID, err = order.PickupPointID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	}
+
+	promoCode := sql.NullString{String: order.PromoCode, Valid: order.PromoCode != ""}
+	riskReasons := sql.NullString{String: strings.Join(order.RiskReasons, riskReasonsSeparator), Valid: len(order.RiskReasons) > 0}
+	idempotenceKey := sql.NullString{String: order.IdempotenceKey, Valid: order.IdempotenceKey != ""}
+
+	_, err = r.client.Exec(
+		orderQuery,
+		binaryOrderID,
+		idempotenceKey,
+		binaryUserID,
+		binaryAddressID,
+		binaryDeliverySlotID,
+		binaryPickupPointID,
+		promoCode,
+		order.ShippingFee,
+		int(order.Status),
+		order.TotalAmount,
+		riskReasons,
+	)
 	if err != nil {
 		return err
 	}
@@ -109,16 +190,30 @@ func (r *orderRepo) Store(order *domain.Order) error {
 	}
 
 	insertQuery := fmt.Sprintf(`
-		INSERT INTO order_item (id, order_id, price, quantity)
+		INSERT INTO order_item (id, variant_id, warehouse_item_id, order_id, price, quantity, discount)
 		VALUES %s%s
-	`, "(?, ?, ?, ?)", strings.Repeat(", (?, ?, ?, ?)", len(order.Items)-1))
-	args := make([]any, 0, len(order.Items)*4) // arguments count
+	`, "(?, ?, ?, ?, ?, ?, ?)", strings.Repeat(", (?, ?, ?, ?, ?, ?, ?)", len(order.Items)-1))
+	args := make([]any, 0, len(order.Items)*7) // arguments count
 	for _, item := range order.Items {
 		binaryItemID, err := item.ID.MarshalBinary()
 		if err != nil {
 			return err
 		}
-		args = append(args, binaryItemID, binaryOrderID, item.ItemPrice, item.Quantity)
+
+		var binaryVariantID, binaryWarehouseItemID []byte
+		if item.VariantID != uuid.Nil {
+			binaryVariantID, err = item.VariantID.MarshalBinary()
+			if err != nil {
+				return err
+			}
+		}
+		if item.WarehouseItemID != uuid.Nil {
+			binaryWarehouseItemID, err = item.WarehouseItemID.MarshalBinary()
+			if err != nil {
+				return err
+			}
+		}
+		args = append(args, binaryItemID, binaryVariantID, binaryWarehouseItemID, binaryOrderID, item.ItemPrice, item.Quantity, item.Discount)
 	}
 
 	_, err = r.client.Exec(insertQuery, args...)
@@ -129,17 +224,32 @@ func NewOrderRepository(client mysql.Client) domain.OrderRepository {
 	return &orderRepo{client: client}
 }
 
+func splitRiskReasons(reasons sql.NullString) []string {
+	if !reasons.Valid || reasons.String == "" {
+		return nil
+	}
+	return strings.Split(reasons.String, riskReasonsSeparator)
+}
+
 type sqlxOrder struct {
-	ID          uuid.UUID `db:"id"`
-	UserID      uuid.UUID `db:"user_id"`
-	AddressID   uuid.UUID `db:"address_id"`
-	Status      int       `db:"status"`
-	TotalAmount int       `db:"total_amount"`
+	ID             uuid.UUID      `db:"id"`
+	UserID         uuid.UUID      `db:"user_id"`
+	AddressID      uuid.UUID      `db:"address_id"`
+	DeliverySlotID uuid.UUID      `db:"delivery_slot_id"`
+	PickupPointID  uuid.UUID      `db:"pickup_point_id"`
+	PromoCode      sql.NullString `db:"promo_code"`
+	ShippingFee    int            `db:"shipping_fee"`
+	Status         int            `db:"status"`
+	TotalAmount    int            `db:"total_amount"`
+	RiskReasons    sql.NullString `db:"risk_reasons"`
 }
 
 type sqlxOrderItem struct {
-	ID       uuid.UUID `db:"id"`
-	OrderID  uuid.UUID `db:"order_id"`
-	Price    int       `db:"price"`
-	Quantity int       `db:"quantity"`
+	ID              uuid.UUID `db:"id"`
+	VariantID       uuid.UUID `db:"variant_id"`
+	WarehouseItemID uuid.UUID `db:"warehouse_item_id"`
+	OrderID         uuid.UUID `db:"order_id"`
+	Price           int       `db:"price"`
+	Quantity        int       `db:"quantity"`
+	Discount        int       `db:"discount"`
 }
@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/order/domain"
+	"strings"
+	"time"
+)
+
+type promoCodeRepo struct {
+	client mysql.Client
+}
+
+func (r *promoCodeRepo) GetByCode(code string) (*domain.PromoCode, error) {
+	return r.get(code, false)
+}
+
+func (r *promoCodeRepo) LockByCode(code string) (*domain.PromoCode, error) {
+	return r.get(code, true)
+}
+
+func (r *promoCodeRepo) Store(promoCode *domain.PromoCode) error {
+	const promoCodeQuery = `
+		INSERT INTO promo_code
+			(code, discount_type, discount_value, min_basket_amount, per_user_limit, valid_from, valid_to, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			discount_type = VALUES(discount_type), discount_value = VALUES(discount_value),
+			min_basket_amount = VALUES(min_basket_amount), per_user_limit = VALUES(per_user_limit),
+			valid_from = VALUES(valid_from), valid_to = VALUES(valid_to), updated_at = NOW()
+	`
+
+	_, err := r.client.Exec(
+		promoCodeQuery,
+		promoCode.Code,
+		int(promoCode.DiscountType),
+		promoCode.DiscountValue,
+		promoCode.MinBasketAmount,
+		promoCode.PerUserLimit,
+		promoCode.ValidFrom,
+		promoCode.ValidTo,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = r.storeScope("promo_code_product", "product_id", promoCode.Code, promoCode.ProductIDs)
+	if err != nil {
+		return err
+	}
+	return r.storeScope("promo_code_category", "category_id", promoCode.Code, promoCode.CategoryIDs)
+}
+
+func (r *promoCodeRepo) get(code string, forUpdate bool) (*domain.PromoCode, error) {
+	promoCodeQuery := `
+		SELECT code, discount_type, discount_value, min_basket_amount, per_user_limit, valid_from, valid_to
+		FROM promo_code
+		WHERE code = ?
+	`
+	if forUpdate {
+		promoCodeQuery += " FOR UPDATE"
+	}
+
+	var promoCodeSqlx sqlxPromoCode
+	err := r.client.Get(&promoCodeSqlx, promoCodeQuery, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var productIDs []uuid.UUID
+	err = r.client.Select(&productIDs, `SELECT product_id FROM promo_code_product WHERE code = ?`, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var categoryIDs []uuid.UUID
+	err = r.client.Select(&categoryIDs, `SELECT category_id FROM promo_code_category WHERE code = ?`, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PromoCode{
+		Code:            promoCodeSqlx.Code,
+		DiscountType:    domain.DiscountType(promoCodeSqlx.DiscountType),
+		DiscountValue:   promoCodeSqlx.DiscountValue,
+		MinBasketAmount: promoCodeSqlx.MinBasketAmount,
+		PerUserLimit:    promoCodeSqlx.PerUserLimit,
+		ValidFrom:       promoCodeSqlx.ValidFrom,
+		ValidTo:         promoCodeSqlx.ValidTo,
+		ProductIDs:      productIDs,
+		CategoryIDs:     categoryIDs,
+	}, nil
+}
+
+func (r *promoCodeRepo) storeScope(table, column, code string, ids []uuid.UUID) error {
+	_, err := r.client.Exec(fmt.Sprintf(`DELETE FROM %s WHERE code = ?`, table), code)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (code, %s)
+		VALUES %s%s
+	`, table, column, "(?, ?)", strings.Repeat(", (?, ?)", len(ids)-1))
+	args := make([]any, 0, len(ids)*2) // arguments count
+	for _, id := range ids {
+		binaryID, err := id.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		args = append(args, code, binaryID)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func NewPromoCodeRepository(client mysql.Client) domain.PromoCodeRepository {
+	return &promoCodeRepo{client: client}
+}
+
+type sqlxPromoCode struct {
+	Code            string    `db:"code"`
+	DiscountType    int       `db:"discount_type"`
+	DiscountValue   int       `db:"discount_value"`
+	MinBasketAmount int       `db:"min_basket_amount"`
+	PerUserLimit    int       `db:"per_user_limit"`
+	ValidFrom       time.Time `db:"valid_from"`
+	ValidTo         time.Time `db:"valid_to"`
+}
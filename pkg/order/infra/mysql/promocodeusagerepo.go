@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/order/domain"
+)
+
+type promoCodeUsageRepo struct {
+	client mysql.Client
+}
+
+func (r *promoCodeUsageRepo) CountActive(code string, userID uuid.UUID) (int, error) {
+	const countQuery = `SELECT COUNT(*) FROM promo_code_usage WHERE code = ? AND user_id = ? AND status != ?`
+
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.client.Get(&count, countQuery, code, binaryUserID, int(domain.PromoCodeUsageStatusReleased))
+	return count, err
+}
+
+func (r *promoCodeUsageRepo) GetByOrderID(orderID uuid.UUID) (*domain.PromoCodeUsage, error) {
+	const usageQuery = `SELECT order_id, code, user_id, status FROM promo_code_usage WHERE order_id = ?`
+
+	binaryOrderID, err := orderID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var usageSqlx sqlxPromoCodeUsage
+	err = r.client.Get(&usageSqlx, usageQuery, binaryOrderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPromoCodeUsageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PromoCodeUsage{
+		Code:    usageSqlx.Code,
+		UserID:  usageSqlx.UserID,
+		OrderID: usageSqlx.OrderID,
+		Status:  domain.PromoCodeUsageStatus(usageSqlx.Status),
+	}, nil
+}
+
+func (r *promoCodeUsageRepo) Store(usage *domain.PromoCodeUsage) error {
+	const usageQuery = `
+		INSERT INTO promo_code_usage (order_id, code, user_id, status, created_at)
+		VALUES (?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status), updated_at = NOW()
+	`
+
+	binaryOrderID, err := usage.OrderID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	binaryUserID, err := usage.UserID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(usageQuery, binaryOrderID, usage.Code, binaryUserID, int(usage.Status))
+	return err
+}
+
+func NewPromoCodeUsageRepository(client mysql.Client) domain.PromoCodeUsageRepository {
+	return &promoCodeUsageRepo{client: client}
+}
+
+type sqlxPromoCodeUsage struct {
+	OrderID uuid.UUID `db:"order_id"`
+	Code    string    `db:"code"`
+	UserID  uuid.UUID `db:"user_id"`
+	Status  int       `db:"status"`
+}
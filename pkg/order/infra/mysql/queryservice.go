@@ -15,7 +15,7 @@ type orderQueryService struct {
 
 func (s *orderQueryService) GetOrderData(id uuid.UUID) (*query.OrderData, error) {
 	const orderQuery = `
-		SELECT id, user_id, address_id, status, total_amount
+		SELECT id, user_id, address_id, delivery_slot_id, pickup_point_id, promo_code, shipping_fee, status, total_amount, risk_reasons
 		FROM ` + " `order` " + `
 		WHERE id = ?
 	`
@@ -34,12 +34,46 @@ func (s *orderQueryService) GetOrderData(id uuid.UUID) (*query.OrderData, error)
 		return nil, err
 	}
 
+	return s.getOrderData(&orderSqlx)
+}
+
+func (s *orderQueryService) ListOnHoldOrders() ([]query.OrderData, error) {
+	const ordersQuery = `
+		SELECT id, user_id, address_id, delivery_slot_id, pickup_point_id, promo_code, shipping_fee, status, total_amount, risk_reasons
+		FROM ` + " `order` " + `
+		WHERE status = ?
+		ORDER BY created_at
+	`
+
+	var ordersSqlx []sqlxOrder
+	err := s.client.Select(&ordersSqlx, ordersQuery, int(domain.OrderStatusOnHold))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]query.OrderData, 0, len(ordersSqlx))
+	for _, orderSqlx := range ordersSqlx {
+		orderData, err := s.getOrderData(&orderSqlx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *orderData)
+	}
+	return result, nil
+}
+
+func (s *orderQueryService) getOrderData(orderSqlx *sqlxOrder) (*query.OrderData, error) {
 	const itemsQuery = `
-		SELECT id, price, quantity
+		SELECT id, variant_id, warehouse_item_id, price, quantity, discount
 		FROM order_item
 		WHERE order_id = ?
 	`
 
+	binaryID, err := orderSqlx.ID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
 	var sqlxItems []sqlxOrderItem
 	err = s.client.Select(&sqlxItems, itemsQuery, binaryID)
 	if err != nil {
@@ -50,18 +84,25 @@ func (s *orderQueryService) GetOrderData(id uuid.UUID) (*query.OrderData, error)
 	for _, sqlxItem := range sqlxItems {
 		orderItems = append(orderItems, query.OrderItemData{
 			ID:        sqlxItem.ID,
+			VariantID: sqlxItem.VariantID,
 			ItemPrice: sqlxItem.Price,
 			Quantity:  sqlxItem.Quantity,
+			Discount:  sqlxItem.Discount,
 		})
 	}
 
 	return &query.OrderData{
-		ID:          orderSqlx.ID,
-		UserID:      orderSqlx.UserID,
-		AddressID:   orderSqlx.AddressID,
-		Items:       orderItems,
-		Status:      domain.OrderStatus(orderSqlx.Status),
-		TotalAmount: orderSqlx.TotalAmount,
+		ID:             orderSqlx.ID,
+		UserID:         orderSqlx.UserID,
+		AddressID:      orderSqlx.AddressID,
+		DeliverySlotID: orderSqlx.DeliverySlotID,
+		PickupPointID:  orderSqlx.PickupPointID,
+		Items:          orderItems,
+		PromoCode:      orderSqlx.PromoCode.String,
+		ShippingFee:    orderSqlx.ShippingFee,
+		Status:         domain.OrderStatus(orderSqlx.Status),
+		TotalAmount:    orderSqlx.TotalAmount,
+		RiskReasons:    splitRiskReasons(orderSqlx.RiskReasons),
 	}, nil
 }
 
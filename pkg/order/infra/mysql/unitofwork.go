@@ -33,6 +33,14 @@ func (p *persistentProvider) OrderRepository() domain.OrderRepository {
 	return NewOrderRepository(p.db)
 }
 
+func (p *persistentProvider) PromoCodeRepository() domain.PromoCodeRepository {
+	return NewPromoCodeRepository(p.db)
+}
+
+func (p *persistentProvider) PromoCodeUsageRepository() domain.PromoCodeUsageRepository {
+	return NewPromoCodeUsageRepository(p.db)
+}
+
 func (p *persistentProvider) IdempotenceKeyStore() idempotence.KeyStore {
 	return mysql.NewIdempotenceKeyStore(p.db)
 }
@@ -14,12 +14,13 @@ type apiClient struct {
 	eventDispatcher event.Dispatcher
 }
 
-func (a *apiClient) AuthorizeOrder(orderID uuid.UUID, totalAmount int) error {
+func (a *apiClient) AuthorizeOrder(orderID uuid.UUID, userID uuid.UUID, totalAmount int) error {
 	createPayment := struct {
 		OrderID     uuid.UUID `json:"order_id"`
+		UserID      uuid.UUID `json:"user_id"`
 		TotalAmount int       `json:"total_amount"`
 	}{
-		orderID, totalAmount,
+		orderID, userID, totalAmount,
 	}
 
 	createPaymentJSON, err := json.Marshal(createPayment)
@@ -75,6 +76,46 @@ func (a *apiClient) CancelPayment(orderID uuid.UUID) error {
 	return nil
 }
 
+func (a *apiClient) RefundPayment(orderID uuid.UUID) error {
+	orderIDJSON, err := json.Marshal(orderID.String())
+	if err != nil {
+		return errors.New("failed to encode uuid to json")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "refund_payment",
+		TopicName: paymentEventTopicName,
+		Key:       orderID.String(),
+		Body:      orderIDJSON,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func (a *apiClient) RefundPaymentPartially(orderID, refundID uuid.UUID, amount int) error {
+	body, err := json.Marshal(struct {
+		OrderID  uuid.UUID `json:"order_id"`
+		RefundID uuid.UUID `json:"refund_id"`
+		Amount   int       `json:"amount"`
+	}{orderID, refundID, amount})
+	if err != nil {
+		return errors.New("failed to encode json request")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "refund_payment_partially",
+		TopicName: paymentEventTopicName,
+		Key:       orderID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
 func New(messageDispatcher event.Dispatcher) async.PaymentAPI {
 	return &apiClient{eventDispatcher: messageDispatcher}
 }
@@ -3,6 +3,7 @@ package transport
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
@@ -11,27 +12,48 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/order/app/service"
 	"github.com/klwxsrx/arch-course-project/pkg/order/domain"
 	"net/http"
+	"time"
 )
 
 const healthEndpoint = "/healthz"
 
 type createOrderItemData struct {
-	ID        uuid.UUID `json:"id"`
-	ItemPrice int       `json:"item_price"`
-	Quantity  int       `json:"quantity"`
+	ID              uuid.UUID   `json:"id"`
+	VariantID       *uuid.UUID  `json:"variant_id"`
+	WarehouseItemID *uuid.UUID  `json:"warehouse_item_id"`
+	ItemPrice       int         `json:"item_price"`
+	Quantity        int         `json:"quantity"`
+	CategoryIDs     []uuid.UUID `json:"category_ids"`
 }
 
 type createOrderData struct {
-	UserID    uuid.UUID             `json:"user_id"`
-	AddressID uuid.UUID             `json:"address_id"`
-	Items     []createOrderItemData `json:"items"`
+	UserID           uuid.UUID             `json:"user_id"`
+	UserRegisteredAt *time.Time            `json:"user_registered_at"`
+	AddressID        uuid.UUID             `json:"address_id"`
+	DeliverySlotID   uuid.UUID             `json:"delivery_slot_id"`
+	PickupPointID    uuid.UUID             `json:"pickup_point_id"`
+	ShippingFee      int                   `json:"shipping_fee"`
+	Items            []createOrderItemData `json:"items"`
+	PromoCode        string                `json:"promo_code"`
+}
+
+type promoCodeJSONSchema struct {
+	Code            string      `json:"code"`
+	DiscountType    string      `json:"discount_type"`
+	DiscountValue   int         `json:"discount_value"`
+	MinBasketAmount int         `json:"min_basket_amount"`
+	PerUserLimit    int         `json:"per_user_limit"`
+	ValidFrom       time.Time   `json:"valid_from"`
+	ValidTo         time.Time   `json:"valid_to"`
+	ProductIDs      []uuid.UUID `json:"product_ids"`
+	CategoryIDs     []uuid.UUID `json:"category_ids"`
 }
 
 type route struct {
 	Name    string
 	Method  string
 	Pattern string
-	Handler func(*service.OrderService, query.Service, http.ResponseWriter, *http.Request)
+	Handler func(*service.OrderService, *service.PromoCodeService, query.Service, http.ResponseWriter, *http.Request)
 }
 
 func getRoutes() []route {
@@ -42,6 +64,42 @@ func getRoutes() []route {
 			"/orders",
 			createOrderHandler,
 		},
+		{
+			"calculateDiscounts",
+			http.MethodPost,
+			"/orders/discounts",
+			calculateDiscountsHandler,
+		},
+		{
+			"storePromoCode",
+			http.MethodPut,
+			"/order/promo-codes",
+			storePromoCodeHandler,
+		},
+		{
+			"getPromoCode",
+			http.MethodGet,
+			"/order/promo-codes/{code}",
+			getPromoCodeHandler,
+		},
+		{
+			"listOnHoldOrders",
+			http.MethodGet,
+			"/order/on-hold",
+			listOnHoldOrdersHandler,
+		},
+		{
+			"approveOnHoldOrder",
+			http.MethodPost,
+			"/order/{orderID}/approve",
+			approveOnHoldOrderHandler,
+		},
+		{
+			"rejectOnHoldOrder",
+			http.MethodPost,
+			"/order/{orderID}/reject",
+			rejectOnHoldOrderHandler,
+		},
 		{
 			"getOrder",
 			http.MethodGet,
@@ -57,7 +115,7 @@ func getRoutes() []route {
 	}
 }
 
-func createOrderHandler(srv *service.OrderService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+func createOrderHandler(srv *service.OrderService, _ *service.PromoCodeService, _ query.Service, w http.ResponseWriter, r *http.Request) {
 	idempotenceKey, err := parseIdempotenceKey(r)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -66,21 +124,29 @@ func createOrderHandler(srv *service.OrderService, _ query.Service, w http.Respo
 
 	var createOrder createOrderData
 	err = json.NewDecoder(r.Body).Decode(&createOrder)
-	if err != nil {
+	if err != nil || createOrder.ShippingFee < 0 || (createOrder.AddressID != uuid.Nil && createOrder.PickupPointID != uuid.Nil) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	orderItems := make([]domain.OrderItem, 0, len(createOrder.Items))
-	for _, item := range createOrder.Items {
-		orderItems = append(orderItems, domain.OrderItem{
-			ID:        item.ID,
-			ItemPrice: item.ItemPrice,
-			Quantity:  item.Quantity,
-		})
+	var userRegisteredAt time.Time
+	if createOrder.UserRegisteredAt != nil {
+		userRegisteredAt = *createOrder.UserRegisteredAt
 	}
 
-	orderID, err := srv.Create(idempotenceKey, createOrder.UserID, createOrder.AddressID, orderItems)
+	orderID, err := srv.Create(idempotenceKey, &service.CreateOrderData{
+		UserID:           createOrder.UserID,
+		UserRegisteredAt: userRegisteredAt,
+		AddressID:        createOrder.AddressID,
+		DeliverySlotID:   createOrder.DeliverySlotID,
+		PickupPointID:    createOrder.PickupPointID,
+		ShippingFee:      createOrder.ShippingFee,
+		Items:            getOrderItemData(createOrder.Items),
+		PromoCode:        createOrder.PromoCode,
+	})
+	if writePromoCodeError(w, err) {
+		return
+	}
 	if errors.Is(err, service.ErrOrderAlreadyCreated) {
 		w.WriteHeader(http.StatusConflict)
 		return
@@ -97,7 +163,118 @@ func createOrderHandler(srv *service.OrderService, _ query.Service, w http.Respo
 	_ = json.NewEncoder(w).Encode(orderID)
 }
 
-func getOrderHandler(_ *service.OrderService, qs query.Service, w http.ResponseWriter, r *http.Request) {
+func calculateDiscountsHandler(_ *service.OrderService, srv *service.PromoCodeService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		UserID    uuid.UUID             `json:"user_id"`
+		PromoCode string                `json:"promo_code"`
+		Items     []createOrderItemData `json:"items"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	discounts, err := srv.CalculateDiscounts(body.PromoCode, body.UserID, getOrderItemData(body.Items))
+	if writePromoCodeError(w, err) {
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type itemDiscountJSONSchema struct {
+		ID        uuid.UUID  `json:"id"`
+		VariantID *uuid.UUID `json:"variant_id,omitempty"`
+		Discount  int        `json:"discount"`
+	}
+
+	result := make([]itemDiscountJSONSchema, 0, len(body.Items))
+	for i, item := range body.Items {
+		result = append(result, itemDiscountJSONSchema{
+			ID:        item.ID,
+			VariantID: item.VariantID,
+			Discount:  discounts[i],
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func storePromoCodeHandler(_ *service.OrderService, srv *service.PromoCodeService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	var body promoCodeJSONSchema
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	discountType, err := parseDiscountType(body.DiscountType)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.Store(&domain.PromoCode{
+		Code:            body.Code,
+		DiscountType:    discountType,
+		DiscountValue:   body.DiscountValue,
+		MinBasketAmount: body.MinBasketAmount,
+		PerUserLimit:    body.PerUserLimit,
+		ValidFrom:       body.ValidFrom,
+		ValidTo:         body.ValidTo,
+		ProductIDs:      body.ProductIDs,
+		CategoryIDs:     body.CategoryIDs,
+	})
+	switch {
+	case errors.Is(err, service.ErrInvalidPromoCode):
+		w.WriteHeader(http.StatusBadRequest)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func getPromoCodeHandler(_ *service.OrderService, srv *service.PromoCodeService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	promoCode, err := srv.Get(mux.Vars(r)["code"])
+	if errors.Is(err, domain.ErrPromoCodeNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	discountType := "percentage"
+	if promoCode.DiscountType == domain.DiscountTypeFixed {
+		discountType = "fixed"
+	}
+
+	err = json.NewEncoder(w).Encode(promoCodeJSONSchema{
+		Code:            promoCode.Code,
+		DiscountType:    discountType,
+		DiscountValue:   promoCode.DiscountValue,
+		MinBasketAmount: promoCode.MinBasketAmount,
+		PerUserLimit:    promoCode.PerUserLimit,
+		ValidFrom:       promoCode.ValidFrom,
+		ValidTo:         promoCode.ValidTo,
+		ProductIDs:      promoCode.ProductIDs,
+		CategoryIDs:     promoCode.CategoryIDs,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getOrderHandler(_ *service.OrderService, _ *service.PromoCodeService, qs query.Service, w http.ResponseWriter, r *http.Request) {
 	authUserID, err := parseAuthUserID(r)
 	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -125,29 +302,43 @@ func getOrderHandler(_ *service.OrderService, qs query.Service, w http.ResponseW
 	}
 
 	type orderItemJSONSchema struct {
-		ID        uuid.UUID `json:"id"`
-		ItemPrice int       `json:"price"`
-		Quantity  int       `json:"quantity"`
+		ID        uuid.UUID  `json:"id"`
+		VariantID *uuid.UUID `json:"variant_id,omitempty"`
+		ItemPrice int        `json:"price"`
+		Quantity  int        `json:"quantity"`
+		Discount  int        `json:"discount"`
 	}
 	type orderJSONSchema struct {
-		ID          uuid.UUID             `json:"id"`
-		UserID      uuid.UUID             `json:"user_id"`
-		AddressID   uuid.UUID             `json:"address_id"`
-		Items       []orderItemJSONSchema `json:"items"`
-		Status      string                `json:"status"`
-		TotalAmount int                   `json:"total_amount"`
+		ID            uuid.UUID             `json:"id"`
+		UserID        uuid.UUID             `json:"user_id"`
+		AddressID     *uuid.UUID            `json:"address_id,omitempty"`
+		SlotID        *uuid.UUID            `json:"delivery_slot_id,omitempty"`
+		PickupPointID *uuid.UUID            `json:"pickup_point_id,omitempty"`
+		Items         []orderItemJSONSchema `json:"items"`
+		PromoCode     string                `json:"promo_code,omitempty"`
+		ShippingFee   int                   `json:"shipping_fee"`
+		Status        string                `json:"status"`
+		TotalAmount   int                   `json:"total_amount"`
 	}
 
 	var orderStatus string
 	switch order.Status {
-	case domain.OrderStatusCreated, domain.OrderStatusPaymentAuthorized, domain.OrderStatusItemsReserved, domain.OrderStatusDeliveryScheduled:
+	case domain.OrderStatusCreated, domain.OrderStatusOnHold, domain.OrderStatusPaymentAuthorized, domain.OrderStatusItemsReserved, domain.OrderStatusDeliveryScheduled:
 		orderStatus = "processing"
 	case domain.OrderStatusSentToDelivery:
 		orderStatus = "sent_to_delivery"
 	case domain.OrderStatusDelivered:
 		orderStatus = "delivered"
+	case domain.OrderStatusPartiallyDelivered:
+		orderStatus = "partially_delivered"
+	case domain.OrderStatusPartiallyReturned:
+		orderStatus = "partially_returned"
 	case domain.OrderStatusCancelled:
 		orderStatus = "cancelled"
+	case domain.OrderStatusReturning:
+		orderStatus = "returning"
+	case domain.OrderStatusRefunded:
+		orderStatus = "refunded"
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -155,20 +346,41 @@ func getOrderHandler(_ *service.OrderService, qs query.Service, w http.ResponseW
 
 	orderItems := make([]orderItemJSONSchema, 0, len(order.Items))
 	for _, item := range order.Items {
+		var variantID *uuid.UUID
+		if item.VariantID != uuid.Nil {
+			variantID = &item.VariantID
+		}
 		orderItems = append(orderItems, orderItemJSONSchema{
 			ID:        item.ID,
+			VariantID: variantID,
 			ItemPrice: item.ItemPrice,
 			Quantity:  item.Quantity,
+			Discount:  item.Discount,
 		})
 	}
 
+	var addressID, slotID, pickupPointID *uuid.UUID
+	if order.AddressID != uuid.Nil {
+		addressID = &order.AddressID
+	}
+	if order.DeliverySlotID != uuid.Nil {
+		slotID = &order.DeliverySlotID
+	}
+	if order.PickupPointID != uuid.Nil {
+		pickupPointID = &order.PickupPointID
+	}
+
 	err = json.NewEncoder(w).Encode(orderJSONSchema{
-		ID:          order.ID,
-		UserID:      order.UserID,
-		AddressID:   order.AddressID,
-		Items:       orderItems,
-		Status:      orderStatus,
-		TotalAmount: order.TotalAmount,
+		ID:            order.ID,
+		UserID:        order.UserID,
+		AddressID:     addressID,
+		SlotID:        slotID,
+		PickupPointID: pickupPointID,
+		Items:         orderItems,
+		PromoCode:     order.PromoCode,
+		ShippingFee:   order.ShippingFee,
+		Status:        orderStatus,
+		TotalAmount:   order.TotalAmount,
 	})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -176,12 +388,133 @@ func getOrderHandler(_ *service.OrderService, qs query.Service, w http.ResponseW
 	}
 }
 
-func healthCheckHandler(_ *service.OrderService, _ query.Service, w http.ResponseWriter, _ *http.Request) {
+func listOnHoldOrdersHandler(_ *service.OrderService, _ *service.PromoCodeService, qs query.Service, w http.ResponseWriter, _ *http.Request) {
+	orders, err := qs.ListOnHoldOrders()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type onHoldOrderJSONSchema struct {
+		ID          uuid.UUID `json:"id"`
+		UserID      uuid.UUID `json:"user_id"`
+		TotalAmount int       `json:"total_amount"`
+		RiskReasons []string  `json:"risk_reasons"`
+	}
+
+	result := make([]onHoldOrderJSONSchema, 0, len(orders))
+	for _, order := range orders {
+		result = append(result, onHoldOrderJSONSchema{
+			ID:          order.ID,
+			UserID:      order.UserID,
+			TotalAmount: order.TotalAmount,
+			RiskReasons: order.RiskReasons,
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func approveOnHoldOrderHandler(srv *service.OrderService, _ *service.PromoCodeService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	orderID, err := parseUUID(mux.Vars(r)["orderID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	writeOnHoldOrderResult(w, srv.ApproveOnHoldOrder(orderID))
+}
+
+func rejectOnHoldOrderHandler(srv *service.OrderService, _ *service.PromoCodeService, _ query.Service, w http.ResponseWriter, r *http.Request) {
+	orderID, err := parseUUID(mux.Vars(r)["orderID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	writeOnHoldOrderResult(w, srv.RejectOnHoldOrder(orderID))
+}
+
+func writeOnHoldOrderResult(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrOrderNotOnHold):
+		w.WriteHeader(http.StatusConflict)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func healthCheckHandler(_ *service.OrderService, _ *service.PromoCodeService, _ query.Service, w http.ResponseWriter, _ *http.Request) {
 	_ = json.NewEncoder(w).Encode(struct {
 		Status string `json:"status"`
 	}{"OK"})
 }
 
+func getOrderItemData(items []createOrderItemData) []service.OrderItemData {
+	result := make([]service.OrderItemData, 0, len(items))
+	for _, item := range items {
+		var variantID, warehouseItemID uuid.UUID
+		if item.VariantID != nil {
+			variantID = *item.VariantID
+		}
+		if item.WarehouseItemID != nil {
+			warehouseItemID = *item.WarehouseItemID
+		}
+		result = append(result, service.OrderItemData{
+			ID:              item.ID,
+			VariantID:       variantID,
+			WarehouseItemID: warehouseItemID,
+			ItemPrice:       item.ItemPrice,
+			Quantity:        item.Quantity,
+			CategoryIDs:     item.CategoryIDs,
+		})
+	}
+	return result
+}
+
+func writePromoCodeError(w http.ResponseWriter, err error) bool {
+	var reason string
+	switch {
+	case errors.Is(err, domain.ErrPromoCodeNotFound):
+		reason = "promo_code_not_found"
+	case errors.Is(err, domain.ErrPromoCodeNotActive):
+		reason = "promo_code_not_active"
+	case errors.Is(err, domain.ErrPromoCodeMinBasketNotReached):
+		reason = "promo_code_min_basket_not_reached"
+	case errors.Is(err, domain.ErrPromoCodeUsageLimitExceeded):
+		reason = "promo_code_usage_limit_exceeded"
+	case errors.Is(err, domain.ErrPromoCodeNotApplicable):
+		reason = "promo_code_not_applicable"
+	default:
+		return false
+	}
+
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{reason})
+	return true
+}
+
+func parseDiscountType(str string) (domain.DiscountType, error) {
+	switch str {
+	case "percentage":
+		return domain.DiscountTypePercentage, nil
+	case "fixed":
+		return domain.DiscountTypeFixed, nil
+	default:
+		return 0, fmt.Errorf("unknown discount type %s", str)
+	}
+}
+
 func parseAuthUserID(r *http.Request) (uuid.UUID, error) {
 	id := r.Header.Get("X-Auth-User-ID")
 	return uuid.Parse(id)
@@ -201,15 +534,21 @@ func parseIdempotenceKey(r *http.Request) (string, error) {
 
 func getHandlerFunc(
 	orderService *service.OrderService,
+	promoCodeService *service.PromoCodeService,
 	queryService query.Service,
-	f func(*service.OrderService, query.Service, http.ResponseWriter, *http.Request),
+	f func(*service.OrderService, *service.PromoCodeService, query.Service, http.ResponseWriter, *http.Request),
 ) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		f(orderService, queryService, w, r)
+		f(orderService, promoCodeService, queryService, w, r)
 	}
 }
 
-func NewHTTPHandler(orderService *service.OrderService, queryService query.Service, logger log.Logger) (http.Handler, error) {
+func NewHTTPHandler(
+	orderService *service.OrderService,
+	promoCodeService *service.PromoCodeService,
+	queryService query.Service,
+	logger log.Logger,
+) (http.Handler, error) {
 	router := mux.NewRouter()
 
 	for _, route := range getRoutes() {
@@ -217,7 +556,7 @@ func NewHTTPHandler(orderService *service.OrderService, queryService query.Servi
 			Methods(route.Method).
 			Path(route.Pattern).
 			Name(route.Name).
-			HandlerFunc(getHandlerFunc(orderService, queryService, route.Handler))
+			HandlerFunc(getHandlerFunc(orderService, promoCodeService, queryService, route.Handler))
 	}
 
 	router.Use(transport.NewLoggingMiddleware(logger, []string{healthEndpoint}))
@@ -23,6 +23,7 @@ func (h *authorizePaymentHandler) Type() string {
 func (h *authorizePaymentHandler) Handle(msg *message.Message) error {
 	var authorizePayment struct {
 		OrderID     uuid.UUID `json:"order_id"`
+		UserID      uuid.UUID `json:"user_id"`
 		TotalAmount int       `json:"total_amount"`
 	}
 
@@ -31,7 +32,7 @@ func (h *authorizePaymentHandler) Handle(msg *message.Message) error {
 		return fmt.Errorf("failed to decode message")
 	}
 
-	err = h.paymentService.AuthorizePayment(authorizePayment.OrderID, authorizePayment.TotalAmount)
+	err = h.paymentService.AuthorizePayment(authorizePayment.OrderID, authorizePayment.UserID, authorizePayment.TotalAmount)
 	if err != nil {
 		return fmt.Errorf("failed to authorize payment: %w", err)
 	}
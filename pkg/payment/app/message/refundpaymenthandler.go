@@ -0,0 +1,39 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/app/service"
+)
+
+type refundPaymentHandler struct {
+	paymentService *service.PaymentService
+}
+
+func (h *refundPaymentHandler) TopicName() string {
+	return paymentEventTopicName
+}
+
+func (h *refundPaymentHandler) Type() string {
+	return "refund_payment"
+}
+
+func (h *refundPaymentHandler) Handle(msg *message.Message) error {
+	var orderID uuid.UUID
+	err := json.Unmarshal(msg.Body, &orderID)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.paymentService.RefundPayment(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+	return nil
+}
+
+func NewRefundPaymentHandler(paymentService *service.PaymentService) message.Handler {
+	return &refundPaymentHandler{paymentService: paymentService}
+}
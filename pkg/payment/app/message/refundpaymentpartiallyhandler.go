@@ -0,0 +1,43 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/app/service"
+)
+
+type refundPaymentPartiallyHandler struct {
+	paymentService *service.PaymentService
+}
+
+func (h *refundPaymentPartiallyHandler) TopicName() string {
+	return paymentEventTopicName
+}
+
+func (h *refundPaymentPartiallyHandler) Type() string {
+	return "refund_payment_partially"
+}
+
+func (h *refundPaymentPartiallyHandler) Handle(msg *message.Message) error {
+	var body struct {
+		OrderID  uuid.UUID `json:"order_id"`
+		RefundID uuid.UUID `json:"refund_id"`
+		Amount   int       `json:"amount"`
+	}
+	err := json.Unmarshal(msg.Body, &body)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.paymentService.RefundPaymentPartially(body.OrderID, body.RefundID, body.Amount)
+	if err != nil {
+		return fmt.Errorf("failed to refund payment partially: %w", err)
+	}
+	return nil
+}
+
+func NewRefundPaymentPartiallyHandler(paymentService *service.PaymentService) message.Handler {
+	return &refundPaymentPartiallyHandler{paymentService: paymentService}
+}
@@ -1,12 +1,17 @@
 package persistence
 
 import (
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/idempotence"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/app/service/async"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
 )
 
 type PersistentProvider interface {
 	PaymentRepository() domain.PaymentRepository
+	ReconciliationRunRepository() domain.ReconciliationRunRepository
+	StoreCreditRepository() domain.StoreCreditRepository
+	GiftCardRepository() domain.GiftCardRepository
+	IdempotenceKeyStore() idempotence.KeyStore
 	OrderAPI() async.OrderAPI
 }
 
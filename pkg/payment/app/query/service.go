@@ -4,16 +4,57 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+	"time"
 )
 
-var ErrPaymentNotFound = errors.New("payment not found")
+var (
+	ErrPaymentNotFound           = errors.New("payment not found")
+	ErrReconciliationRunNotFound = errors.New("reconciliation run not found")
+)
 
 type PaymentData struct {
-	OrderID     uuid.UUID
-	Status      domain.PaymentStatus
-	TotalAmount int
+	OrderID        uuid.UUID
+	Status         domain.PaymentStatus
+	TotalAmount    int
+	CreditAmount   int
+	RefundedAmount int
+}
+
+type ReconciliationMismatchData struct {
+	Type             domain.ReconciliationMismatchType
+	OrderID          uuid.UUID
+	PaymentStatus    *domain.PaymentStatus
+	PaymentAmount    *int
+	SettlementStatus *domain.SettlementStatus
+	SettlementAmount *int
+}
+
+type ReconciliationRunData struct {
+	ID             uuid.UUID
+	SettlementDate time.Time
+	RecordsCount   int
+	MatchedCount   int
+	MismatchCount  int
+	CreatedAt      time.Time
+	Mismatches     []ReconciliationMismatchData
+}
+
+type StoreCreditOperationData struct {
+	Type         domain.StoreCreditOperationType
+	Amount       int
+	OrderID      *uuid.UUID
+	GiftCardCode *string
+	CreatedAt    time.Time
+}
+
+type StoreCreditData struct {
+	Balance    int
+	Operations []StoreCreditOperationData
 }
 
 type PaymentQueryService interface {
 	GetPayment(orderID uuid.UUID) (*PaymentData, error)
+	ListReconciliationRuns() ([]ReconciliationRunData, error)
+	GetReconciliationRun(id uuid.UUID) (*ReconciliationRunData, error)
+	GetStoreCredit(userID uuid.UUID) (*StoreCreditData, error)
 }
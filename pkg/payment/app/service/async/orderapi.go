@@ -6,4 +6,5 @@ type OrderAPI interface {
 	NotifyPaymentAuthorized(orderID uuid.UUID) error
 	NotifyPaymentCompleted(orderID uuid.UUID) error
 	NotifyPaymentCompletionRejected(orderID uuid.UUID) error
+	NotifyPaymentRefunded(orderID uuid.UUID) error
 }
@@ -0,0 +1,18 @@
+package service
+
+import "github.com/google/uuid"
+
+type GatewayEventType int
+
+const (
+	GatewayEventCaptureSucceeded GatewayEventType = iota
+	GatewayEventCaptureFailed
+	GatewayEventRefundSucceeded
+)
+
+type GatewayEvent struct {
+	ID      string
+	Type    GatewayEventType
+	OrderID uuid.UUID
+	Amount  int
+}
@@ -4,19 +4,33 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/idempotence"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/app/persistence"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
 )
 
+const (
+	gatewayEventKeyPrefix  = "gateway_event_"
+	partialRefundKeyPrefix = "partial_refund_"
+)
+
+var (
+	ErrPaymentNotFound            = errors.New("payment not found")
+	ErrGatewayEventAmountMismatch = errors.New("gateway event amount mismatch")
+	ErrPaymentNotRefundable       = errors.New("payment is not refundable")
+	ErrGatewayEventTooEarly       = errors.New("payment is not ready for gateway event")
+)
+
 type PaymentService struct {
 	ufw    persistence.UnitOfWork
 	logger log.Logger
 }
 
-func (s *PaymentService) AuthorizePayment(orderID uuid.UUID, totalAmount int) error {
-	// TODO: authorize payment from payment gateway
+func (s *PaymentService) AuthorizePayment(orderID uuid.UUID, userID uuid.UUID, totalAmount int) error {
+	// TODO: authorize gateway amount from payment gateway
 
+	var creditAmount int
 	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
 		payment, err := p.PaymentRepository().GetByID(orderID)
 		if err != nil && !errors.Is(err, domain.ErrPaymentNotFound) {
@@ -26,10 +40,17 @@ func (s *PaymentService) AuthorizePayment(orderID uuid.UUID, totalAmount int) er
 			return nil
 		}
 
+		creditAmount, err = chargeStoreCredit(orderID, userID, totalAmount, p)
+		if err != nil {
+			return err
+		}
+
 		payment = &domain.Payment{
-			OrderID:     orderID,
-			TotalAmount: totalAmount,
-			Status:      domain.PaymentStatusAuthorized,
+			OrderID:      orderID,
+			UserID:       userID,
+			TotalAmount:  totalAmount,
+			CreditAmount: creditAmount,
+			Status:       domain.PaymentStatusAuthorized,
 		}
 
 		err = p.PaymentRepository().Store(payment)
@@ -46,22 +67,23 @@ func (s *PaymentService) AuthorizePayment(orderID uuid.UUID, totalAmount int) er
 	if err != nil {
 		s.logger.WithError(err).With(log.Fields{
 			"orderID":     orderID,
+			"userID":      userID,
 			"totalAmount": totalAmount,
 		}).Error("failed to authorize payment")
 		return err
 	}
 
 	s.logger.With(log.Fields{
-		"orderID":     orderID,
-		"totalAmount": totalAmount,
+		"orderID":      orderID,
+		"userID":       userID,
+		"totalAmount":  totalAmount,
+		"creditAmount": creditAmount,
 	}).Info("payment authorized")
 	return nil
 }
 
 func (s *PaymentService) CompletePayment(orderID uuid.UUID) error {
-	// TODO: complete payment from payment gateway
-
-	const testFailedCompletion = false // test rollback at last saga's stage
+	// capture is confirmed asynchronously by the payment gateway, see HandleGatewayEvent
 
 	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
 		payment, err := p.PaymentRepository().GetByID(orderID)
@@ -75,25 +97,14 @@ func (s *PaymentService) CompletePayment(orderID uuid.UUID) error {
 			return nil
 		}
 
-		payment.Status = domain.PaymentStatusCompleted
-		err = p.PaymentRepository().Store(payment)
-		if err != nil {
-			return fmt.Errorf("failed to store completed payment: %w", err)
+		if payment.GatewayAmount() == 0 {
+			return completePayment(payment, p)
 		}
 
-		if testFailedCompletion {
-			err = p.OrderAPI().NotifyPaymentCompletionRejected(orderID)
-			if err != nil {
-				return fmt.Errorf("failed to store completed payment: %w", err)
-			}
-
-			payment.Status = domain.PaymentStatusRejected
-			err = p.PaymentRepository().Store(payment)
-		} else {
-			err = p.OrderAPI().NotifyPaymentCompleted(orderID)
-		}
+		payment.Status = domain.PaymentStatusCompletionPending
+		err = p.PaymentRepository().Store(payment)
 		if err != nil {
-			return fmt.Errorf("failed to notify payment completion: %w", err)
+			return fmt.Errorf("failed to store completion pending payment: %w", err)
 		}
 		return nil
 	})
@@ -106,7 +117,59 @@ func (s *PaymentService) CompletePayment(orderID uuid.UUID) error {
 
 	s.logger.With(log.Fields{
 		"orderID": orderID,
-	}).Info("payment completed")
+	}).Info("payment completion requested")
+	return nil
+}
+
+func (s *PaymentService) HandleGatewayEvent(e *GatewayEvent) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		// the key is rolled back together with the transaction if the event is not applied
+		err := p.IdempotenceKeyStore().StoreUnique(gatewayEventKeyPrefix + e.ID)
+		if errors.Is(err, idempotence.ErrKeyAlreadyExists) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store gateway event key: %w", err)
+		}
+
+		payment, err := p.PaymentRepository().GetByID(e.OrderID)
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return ErrPaymentNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		expectedAmount := payment.GatewayAmount()
+		if e.Type == GatewayEventRefundSucceeded {
+			expectedAmount = payment.RefundableGatewayAmount()
+		}
+		if expectedAmount != e.Amount {
+			return ErrGatewayEventAmountMismatch
+		}
+
+		switch e.Type {
+		case GatewayEventCaptureSucceeded:
+			return handleCaptureSucceeded(payment, p)
+		case GatewayEventCaptureFailed:
+			return handleCaptureFailed(payment, p)
+		case GatewayEventRefundSucceeded:
+			return handleRefundSucceeded(payment, p)
+		default:
+			return fmt.Errorf("unknown gateway event type %v", e.Type)
+		}
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"eventID": e.ID,
+			"orderID": e.OrderID,
+		}).Error("failed to handle gateway event")
+		return err
+	}
+
+	s.logger.With(log.Fields{
+		"eventID": e.ID,
+		"orderID": e.OrderID,
+	}).Info("gateway event handled")
 	return nil
 }
 
@@ -131,7 +194,7 @@ func (s *PaymentService) CancelPayment(orderID uuid.UUID) error {
 		if err != nil {
 			return fmt.Errorf("failed to store cancelled payment: %w", err)
 		}
-		return nil
+		return returnStoreCredit(payment, p)
 	})
 	if err != nil {
 		s.logger.WithError(err).With(log.Fields{
@@ -146,6 +209,232 @@ func (s *PaymentService) CancelPayment(orderID uuid.UUID) error {
 	return nil
 }
 
+func (s *PaymentService) RefundPayment(orderID uuid.UUID) error {
+	// refund is confirmed asynchronously by the payment gateway, see HandleGatewayEvent
+
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		payment, err := p.PaymentRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		if payment.Status != domain.PaymentStatusCompleted {
+			return nil
+		}
+
+		if payment.RefundableGatewayAmount() == 0 {
+			return handleRefundSucceeded(payment, p)
+		}
+
+		payment.Status = domain.PaymentStatusRefundPending
+		err = p.PaymentRepository().Store(payment)
+		if err != nil {
+			return fmt.Errorf("failed to store refund pending payment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"orderID": orderID,
+		}).Error("failed to refund payment")
+		return err
+	}
+
+	s.logger.With(log.Fields{
+		"orderID": orderID,
+	}).Info("payment refund requested")
+	return nil
+}
+
+func (s *PaymentService) RefundPaymentPartially(orderID, refundID uuid.UUID, amount int) error {
+	// the returned part of a partially delivered order is refunded to the user's store credit
+
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		err := p.IdempotenceKeyStore().StoreUnique(partialRefundKeyPrefix + refundID.String())
+		if errors.Is(err, idempotence.ErrKeyAlreadyExists) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store partial refund key: %w", err)
+		}
+
+		payment, err := p.PaymentRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		if payment.Status != domain.PaymentStatusCompleted || payment.UserID == uuid.Nil {
+			return nil
+		}
+
+		if amount > payment.TotalAmount-payment.RefundedAmount {
+			amount = payment.TotalAmount - payment.RefundedAmount
+		}
+		if amount <= 0 {
+			return nil
+		}
+		payment.RefundedAmount += amount
+		if payment.RefundedAmount == payment.TotalAmount {
+			payment.Status = domain.PaymentStatusRefunded
+		}
+		err = p.PaymentRepository().Store(payment)
+		if err != nil {
+			return fmt.Errorf("failed to store partially refunded payment: %w", err)
+		}
+
+		return addStoreCredit(&domain.StoreCreditOperation{
+			UserID:  payment.UserID,
+			Type:    domain.StoreCreditOperationRefund,
+			Amount:  amount,
+			OrderID: payment.OrderID,
+		}, p)
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"orderID": orderID,
+			"amount":  amount,
+		}).Error("failed to refund payment partially")
+		return err
+	}
+
+	s.logger.With(log.Fields{
+		"orderID": orderID,
+		"amount":  amount,
+	}).Info("payment refunded partially")
+	return nil
+}
+
+func (s *PaymentService) RefundToStoreCredit(orderID uuid.UUID) error {
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		payment, err := p.PaymentRepository().GetByID(orderID)
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return ErrPaymentNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+		if payment.Status != domain.PaymentStatusCompleted {
+			return ErrPaymentNotRefundable
+		}
+		if payment.UserID == uuid.Nil {
+			return ErrPaymentNotRefundable
+		}
+
+		payment.Status = domain.PaymentStatusRefunded
+		err = p.PaymentRepository().Store(payment)
+		if err != nil {
+			return fmt.Errorf("failed to store refunded payment: %w", err)
+		}
+
+		err = addStoreCredit(&domain.StoreCreditOperation{
+			UserID:  payment.UserID,
+			Type:    domain.StoreCreditOperationRefund,
+			Amount:  payment.TotalAmount - payment.RefundedAmount,
+			OrderID: payment.OrderID,
+		}, p)
+		if err != nil {
+			return err
+		}
+
+		err = p.OrderAPI().NotifyPaymentRefunded(payment.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to notify payment refunded: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"orderID": orderID,
+		}).Error("failed to refund payment to store credit")
+		return err
+	}
+
+	s.logger.With(log.Fields{
+		"orderID": orderID,
+	}).Info("payment refunded to store credit")
+	return nil
+}
+
+func completePayment(payment *domain.Payment, p persistence.PersistentProvider) error {
+	payment.Status = domain.PaymentStatusCompleted
+	err := p.PaymentRepository().Store(payment)
+	if err != nil {
+		return fmt.Errorf("failed to store completed payment: %w", err)
+	}
+
+	err = p.OrderAPI().NotifyPaymentCompleted(payment.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to notify payment completed: %w", err)
+	}
+	return nil
+}
+
+func handleCaptureSucceeded(payment *domain.Payment, p persistence.PersistentProvider) error {
+	if payment.Status == domain.PaymentStatusAuthorized {
+		return ErrGatewayEventTooEarly
+	}
+	if payment.Status != domain.PaymentStatusCompletionPending {
+		return nil
+	}
+	return completePayment(payment, p)
+}
+
+func handleCaptureFailed(payment *domain.Payment, p persistence.PersistentProvider) error {
+	if payment.Status == domain.PaymentStatusAuthorized {
+		return ErrGatewayEventTooEarly
+	}
+	if payment.Status != domain.PaymentStatusCompletionPending {
+		return nil
+	}
+
+	payment.Status = domain.PaymentStatusRejected
+	err := p.PaymentRepository().Store(payment)
+	if err != nil {
+		return fmt.Errorf("failed to store rejected payment: %w", err)
+	}
+
+	err = returnStoreCredit(payment, p)
+	if err != nil {
+		return err
+	}
+
+	err = p.OrderAPI().NotifyPaymentCompletionRejected(payment.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to notify payment completion rejected: %w", err)
+	}
+	return nil
+}
+
+func handleRefundSucceeded(payment *domain.Payment, p persistence.PersistentProvider) error {
+	if payment.Status == domain.PaymentStatusAuthorized || payment.Status == domain.PaymentStatusCompletionPending {
+		return ErrGatewayEventTooEarly
+	}
+	if payment.Status != domain.PaymentStatusCompleted && payment.Status != domain.PaymentStatusRefundPending {
+		return nil
+	}
+
+	payment.Status = domain.PaymentStatusRefunded
+	err := p.PaymentRepository().Store(payment)
+	if err != nil {
+		return fmt.Errorf("failed to store refunded payment: %w", err)
+	}
+
+	err = returnStoreCredit(payment, p)
+	if err != nil {
+		return err
+	}
+
+	err = p.OrderAPI().NotifyPaymentRefunded(payment.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to notify payment refunded: %w", err)
+	}
+	return nil
+}
+
 func NewPaymentService(ufw persistence.UnitOfWork, logger log.Logger) *PaymentService {
 	return &PaymentService{ufw: ufw, logger: logger}
 }
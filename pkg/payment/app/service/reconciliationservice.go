@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+	"time"
+)
+
+type ReconciliationService struct {
+	ufw    persistence.UnitOfWork
+	logger log.Logger
+}
+
+func (s *ReconciliationService) Reconcile(settlementDate time.Time, records []domain.SettlementRecord) (*domain.ReconciliationRun, error) {
+	settlementDate = time.Date(settlementDate.Year(), settlementDate.Month(), settlementDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	var run *domain.ReconciliationRun
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		settlements := groupSettlementsByOrderID(records)
+		orderIDs := make([]uuid.UUID, 0, len(settlements))
+		for orderID := range settlements {
+			orderIDs = append(orderIDs, orderID)
+		}
+
+		payments, err := p.PaymentRepository().FindByIDs(orderIDs)
+		if err != nil {
+			return fmt.Errorf("failed to find payments: %w", err)
+		}
+		settledPayments, err := p.PaymentRepository().FindUpdatedBetween(
+			[]domain.PaymentStatus{domain.PaymentStatusCompleted, domain.PaymentStatusRefunded},
+			settlementDate,
+			settlementDate.AddDate(0, 0, 1),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to find settled payments: %w", err)
+		}
+
+		run = &domain.ReconciliationRun{
+			ID:             p.ReconciliationRunRepository().NextID(),
+			SettlementDate: settlementDate,
+			RecordsCount:   len(records),
+		}
+		reconcilePayments(run, settlements, payments, settledPayments)
+
+		err = p.ReconciliationRunRepository().Store(run)
+		if err != nil {
+			return fmt.Errorf("failed to store reconciliation run: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"settlementDate": settlementDate,
+		}).Error("failed to reconcile payments")
+		return nil, err
+	}
+
+	s.logger.With(log.Fields{
+		"runID":          run.ID,
+		"settlementDate": settlementDate,
+		"matched":        run.MatchedCount,
+		"mismatches":     len(run.Mismatches),
+	}).Info("payments reconciled")
+	return run, nil
+}
+
+func groupSettlementsByOrderID(records []domain.SettlementRecord) map[uuid.UUID]domain.SettlementRecord {
+	result := make(map[uuid.UUID]domain.SettlementRecord, len(records))
+	for _, record := range records {
+		existing, ok := result[record.OrderID]
+		if ok && existing.Status == domain.SettlementStatusRefunded {
+			continue // refund is the final settlement state of the order
+		}
+		result[record.OrderID] = record
+	}
+	return result
+}
+
+func reconcilePayments(
+	run *domain.ReconciliationRun,
+	settlements map[uuid.UUID]domain.SettlementRecord,
+	payments []domain.Payment,
+	settledPayments []domain.Payment,
+) {
+	paymentsByOrderID := make(map[uuid.UUID]domain.Payment, len(payments))
+	for _, payment := range payments {
+		paymentsByOrderID[payment.OrderID] = payment
+	}
+
+	for orderID, settlement := range settlements {
+		settlement := settlement
+		payment, ok := paymentsByOrderID[orderID]
+		if !ok {
+			run.Mismatches = append(run.Mismatches, domain.ReconciliationMismatch{
+				Type:       domain.ReconciliationMismatchMissingOnOurSide,
+				OrderID:    orderID,
+				Settlement: &settlement,
+			})
+			continue
+		}
+
+		matched := true
+		if payment.GatewayAmount() != settlement.Amount {
+			matched = false
+			run.Mismatches = append(run.Mismatches, domain.ReconciliationMismatch{
+				Type:       domain.ReconciliationMismatchAmountDiffers,
+				OrderID:    orderID,
+				Payment:    &payment,
+				Settlement: &settlement,
+			})
+		}
+		if getSettledPaymentStatus(payment.Status) != getExpectedPaymentStatus(settlement.Status) {
+			matched = false
+			run.Mismatches = append(run.Mismatches, domain.ReconciliationMismatch{
+				Type:       domain.ReconciliationMismatchStatusDiffers,
+				OrderID:    orderID,
+				Payment:    &payment,
+				Settlement: &settlement,
+			})
+		}
+		if matched {
+			run.MatchedCount++
+		}
+	}
+
+	for _, payment := range settledPayments {
+		payment := payment
+		if _, ok := settlements[payment.OrderID]; ok {
+			continue
+		}
+		if payment.GatewayAmount() == 0 {
+			continue // paid by store credit only, never reaches the gateway
+		}
+		run.Mismatches = append(run.Mismatches, domain.ReconciliationMismatch{
+			Type:    domain.ReconciliationMismatchMissingAtGateway,
+			OrderID: payment.OrderID,
+			Payment: &payment,
+		})
+	}
+}
+
+func getSettledPaymentStatus(status domain.PaymentStatus) domain.PaymentStatus {
+	if status == domain.PaymentStatusRefundPending {
+		return domain.PaymentStatusCompleted // gateway keeps the capture until the refund is settled
+	}
+	return status
+}
+
+func getExpectedPaymentStatus(status domain.SettlementStatus) domain.PaymentStatus {
+	if status == domain.SettlementStatusRefunded {
+		return domain.PaymentStatusRefunded
+	}
+	return domain.PaymentStatusCompleted
+}
+
+func NewReconciliationService(ufw persistence.UnitOfWork, logger log.Logger) *ReconciliationService {
+	return &ReconciliationService{ufw: ufw, logger: logger}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/log"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/app/persistence"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+)
+
+var (
+	ErrInvalidGiftCard         = errors.New("invalid gift card")
+	ErrGiftCardNotFound        = errors.New("gift card not found")
+	ErrGiftCardAlreadyRedeemed = errors.New("gift card is already redeemed")
+)
+
+type StoreCreditService struct {
+	ufw    persistence.UnitOfWork
+	logger log.Logger
+}
+
+func (s *StoreCreditService) AddGiftCard(code string, amount int) error {
+	if code == "" || amount <= 0 {
+		return ErrInvalidGiftCard
+	}
+
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		giftCard, err := p.GiftCardRepository().LockByCode(code)
+		if err != nil && !errors.Is(err, domain.ErrGiftCardNotFound) {
+			return fmt.Errorf("failed to get gift card: %w", err)
+		}
+		if err == nil && giftCard.IsRedeemed() {
+			return ErrGiftCardAlreadyRedeemed
+		}
+
+		err = p.GiftCardRepository().Store(&domain.GiftCard{
+			Code:   code,
+			Amount: amount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store gift card: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"giftCardCode": code,
+		}).Error("failed to add gift card")
+		return err
+	}
+
+	s.logger.With(log.Fields{
+		"giftCardCode": code,
+		"amount":       amount,
+	}).Info("gift card added")
+	return nil
+}
+
+func (s *StoreCreditService) RedeemGiftCard(userID uuid.UUID, code string) (int, error) {
+	var amount int
+	err := s.ufw.Execute(func(p persistence.PersistentProvider) error {
+		giftCard, err := p.GiftCardRepository().LockByCode(code)
+		if errors.Is(err, domain.ErrGiftCardNotFound) {
+			return ErrGiftCardNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get gift card: %w", err)
+		}
+		if giftCard.IsRedeemed() {
+			return ErrGiftCardAlreadyRedeemed
+		}
+
+		giftCard.RedeemedBy = userID
+		err = p.GiftCardRepository().Store(giftCard)
+		if err != nil {
+			return fmt.Errorf("failed to store redeemed gift card: %w", err)
+		}
+
+		amount = giftCard.Amount
+		return addStoreCredit(&domain.StoreCreditOperation{
+			UserID:       userID,
+			Type:         domain.StoreCreditOperationGiftCardRedemption,
+			Amount:       giftCard.Amount,
+			GiftCardCode: giftCard.Code,
+		}, p)
+	})
+	if errors.Is(err, ErrGiftCardNotFound) || errors.Is(err, ErrGiftCardAlreadyRedeemed) {
+		return 0, err
+	}
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{
+			"userID":       userID,
+			"giftCardCode": code,
+		}).Error("failed to redeem gift card")
+		return 0, err
+	}
+
+	s.logger.With(log.Fields{
+		"userID":       userID,
+		"giftCardCode": code,
+		"amount":       amount,
+	}).Info("gift card redeemed")
+	return amount, nil
+}
+
+func chargeStoreCredit(orderID, userID uuid.UUID, totalAmount int, p persistence.PersistentProvider) (int, error) {
+	if userID == uuid.Nil {
+		return 0, nil
+	}
+
+	account, err := p.StoreCreditRepository().Lock(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock store credit: %w", err)
+	}
+
+	creditAmount := account.Balance
+	if creditAmount > totalAmount {
+		creditAmount = totalAmount
+	}
+	if creditAmount <= 0 {
+		return 0, nil
+	}
+
+	account.Balance -= creditAmount
+	err = p.StoreCreditRepository().Store(account)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store store credit: %w", err)
+	}
+
+	err = p.StoreCreditRepository().AddOperation(&domain.StoreCreditOperation{
+		UserID:  userID,
+		Type:    domain.StoreCreditOperationPaymentCharge,
+		Amount:  -creditAmount,
+		OrderID: orderID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to add store credit operation: %w", err)
+	}
+	return creditAmount, nil
+}
+
+func returnStoreCredit(payment *domain.Payment, p persistence.PersistentProvider) error {
+	if payment.RefundableCreditAmount() == 0 {
+		return nil
+	}
+	return addStoreCredit(&domain.StoreCreditOperation{
+		UserID:  payment.UserID,
+		Type:    domain.StoreCreditOperationPaymentReturn,
+		Amount:  payment.RefundableCreditAmount(),
+		OrderID: payment.OrderID,
+	}, p)
+}
+
+func addStoreCredit(operation *domain.StoreCreditOperation, p persistence.PersistentProvider) error {
+	account, err := p.StoreCreditRepository().Lock(operation.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to lock store credit: %w", err)
+	}
+
+	account.Balance += operation.Amount
+	err = p.StoreCreditRepository().Store(account)
+	if err != nil {
+		return fmt.Errorf("failed to store store credit: %w", err)
+	}
+
+	err = p.StoreCreditRepository().AddOperation(operation)
+	if err != nil {
+		return fmt.Errorf("failed to add store credit operation: %w", err)
+	}
+	return nil
+}
+
+func NewStoreCreditService(ufw persistence.UnitOfWork, logger log.Logger) *StoreCreditService {
+	return &StoreCreditService{ufw: ufw, logger: logger}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"errors"
 	"github.com/google/uuid"
+	"time"
 )
 
 type PaymentStatus int
@@ -12,17 +13,43 @@ const (
 	PaymentStatusCancelled
 	PaymentStatusCompleted
 	PaymentStatusRejected
+	PaymentStatusCompletionPending
+	PaymentStatusRefunded
+	PaymentStatusRefundPending
 )
 
 type Payment struct {
-	OrderID     uuid.UUID
-	TotalAmount int
-	Status      PaymentStatus
+	OrderID        uuid.UUID
+	UserID         uuid.UUID
+	TotalAmount    int
+	CreditAmount   int
+	RefundedAmount int
+	Status         PaymentStatus
+}
+
+// GatewayAmount is the part of the payment charged by the external payment gateway
+func (p *Payment) GatewayAmount() int {
+	return p.TotalAmount - p.CreditAmount
+}
+
+// RefundableCreditAmount is the store credit part left to refund, partial refunds are taken from it first
+func (p *Payment) RefundableCreditAmount() int {
+	if p.RefundedAmount >= p.CreditAmount {
+		return 0
+	}
+	return p.CreditAmount - p.RefundedAmount
+}
+
+// RefundableGatewayAmount is the payment gateway part left to refund
+func (p *Payment) RefundableGatewayAmount() int {
+	return p.TotalAmount - p.RefundedAmount - p.RefundableCreditAmount()
 }
 
 var ErrPaymentNotFound = errors.New("payment not found")
 
 type PaymentRepository interface {
 	GetByID(id uuid.UUID) (*Payment, error)
+	FindByIDs(ids []uuid.UUID) ([]Payment, error)
+	FindUpdatedBetween(statuses []PaymentStatus, from, to time.Time) ([]Payment, error)
 	Store(payment *Payment) error
 }
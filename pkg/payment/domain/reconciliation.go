@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type SettlementStatus int
+
+const (
+	SettlementStatusCaptured SettlementStatus = iota
+	SettlementStatusRefunded
+)
+
+type SettlementRecord struct {
+	OrderID uuid.UUID
+	Amount  int
+	Status  SettlementStatus
+}
+
+type ReconciliationMismatchType int
+
+const (
+	ReconciliationMismatchMissingOnOurSide ReconciliationMismatchType = iota
+	ReconciliationMismatchMissingAtGateway
+	ReconciliationMismatchAmountDiffers
+	ReconciliationMismatchStatusDiffers
+)
+
+type ReconciliationMismatch struct {
+	Type       ReconciliationMismatchType
+	OrderID    uuid.UUID
+	Payment    *Payment
+	Settlement *SettlementRecord
+}
+
+type ReconciliationRun struct {
+	ID             uuid.UUID
+	SettlementDate time.Time
+	RecordsCount   int
+	MatchedCount   int
+	Mismatches     []ReconciliationMismatch
+}
+
+type ReconciliationRunRepository interface {
+	NextID() uuid.UUID
+	Store(run *ReconciliationRun) error
+}
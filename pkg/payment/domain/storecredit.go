@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"github.com/google/uuid"
+)
+
+type StoreCreditAccount struct {
+	UserID  uuid.UUID
+	Balance int
+}
+
+type StoreCreditOperationType int
+
+const (
+	StoreCreditOperationGiftCardRedemption StoreCreditOperationType = iota
+	StoreCreditOperationRefund
+	StoreCreditOperationPaymentCharge
+	StoreCreditOperationPaymentReturn
+)
+
+type StoreCreditOperation struct {
+	UserID       uuid.UUID
+	Type         StoreCreditOperationType
+	Amount       int
+	OrderID      uuid.UUID
+	GiftCardCode string
+}
+
+type StoreCreditRepository interface {
+	Lock(userID uuid.UUID) (*StoreCreditAccount, error)
+	Store(account *StoreCreditAccount) error
+	AddOperation(operation *StoreCreditOperation) error
+}
+
+type GiftCard struct {
+	Code       string
+	Amount     int
+	RedeemedBy uuid.UUID
+}
+
+var (
+	ErrGiftCardNotFound        = errors.New("gift card not found")
+	ErrGiftCardAlreadyRedeemed = errors.New("gift card is already redeemed")
+)
+
+func (c *GiftCard) IsRedeemed() bool {
+	return c.RedeemedBy != uuid.Nil
+}
+
+type GiftCardRepository interface {
+	LockByCode(code string) (*GiftCard, error)
+	Store(giftCard *GiftCard) error
+}
@@ -0,0 +1,16 @@
+package gateway
+
+import "github.com/google/uuid"
+
+const (
+	WebhookEventCaptureSucceeded = "capture.succeeded"
+	WebhookEventCaptureFailed    = "capture.failed"
+	WebhookEventRefundSucceeded  = "refund.succeeded"
+)
+
+type WebhookEvent struct {
+	ID      string    `json:"id"`
+	Type    string    `json:"type"`
+	OrderID uuid.UUID `json:"order_id"`
+	Amount  int       `json:"amount"`
+}
@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+)
+
+type giftCardRepo struct {
+	client mysql.Client
+}
+
+func (r *giftCardRepo) LockByCode(code string) (*domain.GiftCard, error) {
+	const giftCardQuery = `SELECT code, amount, redeemed_by FROM gift_card WHERE code = ? FOR UPDATE`
+
+	var giftCardSqlx sqlxGiftCard
+	err := r.client.Get(&giftCardSqlx, giftCardQuery, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrGiftCardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.GiftCard{
+		Code:       giftCardSqlx.Code,
+		Amount:     giftCardSqlx.Amount,
+		RedeemedBy: giftCardSqlx.RedeemedBy,
+	}, nil
+}
+
+func (r *giftCardRepo) Store(giftCard *domain.GiftCard) error {
+	const giftCardQuery = `
+		INSERT INTO gift_card (code, amount, redeemed_by, created_at)
+		VALUES (?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			amount = VALUES(amount), redeemed_by = VALUES(redeemed_by), updated_at = NOW()
+	`
+
+	var binaryRedeemedBy []byte
+	if giftCard.IsRedeemed() {
+		var err error
+		binaryRedeemedBy, err = giftCard.RedeemedBy.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := r.client.Exec(giftCardQuery, giftCard.Code, giftCard.Amount, binaryRedeemedBy)
+	return err
+}
+
+func NewGiftCardRepository(client mysql.Client) domain.GiftCardRepository {
+	return &giftCardRepo{client: client}
+}
+
+type sqlxGiftCard struct {
+	Code       string    `db:"code"`
+	Amount     int       `db:"amount"`
+	RedeemedBy uuid.UUID `db:"redeemed_by"`
+}
@@ -15,7 +15,7 @@ type paymentQueryService struct {
 
 func (s *paymentQueryService) GetPayment(orderID uuid.UUID) (*query.PaymentData, error) {
 	const paymentQuery = `
-		SELECT order_id, status, total_amount
+		SELECT order_id, user_id, status, total_amount, credit_amount, refunded_amount
 		FROM payment
 		WHERE order_id = ?
 	`
@@ -35,9 +35,11 @@ func (s *paymentQueryService) GetPayment(orderID uuid.UUID) (*query.PaymentData,
 	}
 
 	return &query.PaymentData{
-		OrderID:     paymentSqlx.OrderID,
-		Status:      domain.PaymentStatus(paymentSqlx.Status),
-		TotalAmount: paymentSqlx.TotalAmount,
+		OrderID:        paymentSqlx.OrderID,
+		Status:         domain.PaymentStatus(paymentSqlx.Status),
+		TotalAmount:    paymentSqlx.TotalAmount,
+		CreditAmount:   paymentSqlx.CreditAmount,
+		RefundedAmount: paymentSqlx.RefundedAmount,
 	}, nil
 }
 
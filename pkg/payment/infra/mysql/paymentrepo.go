@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+	"time"
 )
 
 type paymentRepo struct {
@@ -14,7 +16,7 @@ type paymentRepo struct {
 
 func (r *paymentRepo) GetByID(id uuid.UUID) (*domain.Payment, error) {
 	const paymentQuery = `
-		SELECT order_id, status, total_amount
+		SELECT order_id, user_id, status, total_amount, credit_amount, refunded_amount
 		FROM ` + " `payment` " + `
 		WHERE order_id = ?
 	`
@@ -34,18 +36,76 @@ func (r *paymentRepo) GetByID(id uuid.UUID) (*domain.Payment, error) {
 	}
 
 	return &domain.Payment{
-		OrderID:     paymentSqlx.OrderID,
-		TotalAmount: paymentSqlx.TotalAmount,
-		Status:      domain.PaymentStatus(paymentSqlx.Status),
+		OrderID:        paymentSqlx.OrderID,
+		UserID:         paymentSqlx.UserID,
+		TotalAmount:    paymentSqlx.TotalAmount,
+		CreditAmount:   paymentSqlx.CreditAmount,
+		RefundedAmount: paymentSqlx.RefundedAmount,
+		Status:         domain.PaymentStatus(paymentSqlx.Status),
 	}, nil
 }
 
+func (r *paymentRepo) FindByIDs(ids []uuid.UUID) ([]domain.Payment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	binaryIDs := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		binaryID, err := id.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		binaryIDs = append(binaryIDs, binaryID)
+	}
+
+	paymentQuery, args, err := sqlx.In(`SELECT order_id, user_id, status, total_amount, credit_amount, refunded_amount FROM `+"`payment`"+` WHERE order_id IN (?)`, binaryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var paymentsSqlx []sqlxPayment
+	err = r.client.Select(&paymentsSqlx, paymentQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	return convertPayments(paymentsSqlx), nil
+}
+
+func (r *paymentRepo) FindUpdatedBetween(statuses []domain.PaymentStatus, from, to time.Time) ([]domain.Payment, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+
+	intStatuses := make([]int, 0, len(statuses))
+	for _, status := range statuses {
+		intStatuses = append(intStatuses, int(status))
+	}
+
+	paymentQuery, args, err := sqlx.In(`
+		SELECT order_id, user_id, status, total_amount, credit_amount, refunded_amount
+		FROM `+"`payment`"+`
+		WHERE status IN (?) AND updated_at >= ? AND updated_at < ?
+	`, intStatuses, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var paymentsSqlx []sqlxPayment
+	err = r.client.Select(&paymentsSqlx, paymentQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	return convertPayments(paymentsSqlx), nil
+}
+
 func (r *paymentRepo) Store(payment *domain.Payment) error {
 	const paymentQuery = `
-		INSERT INTO` + " `payment` " + `(order_id, status, total_amount, created_at)
-		VALUES (?, ?, ?, NOW())
+		INSERT INTO` + " `payment` " + `(order_id, user_id, status, total_amount, credit_amount, refunded_amount, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, NOW())
 		ON DUPLICATE KEY UPDATE
-			status = VALUES(status), total_amount = VALUES(total_amount), updated_at = NOW()
+			user_id = VALUES(user_id), status = VALUES(status), total_amount = VALUES(total_amount),
+			credit_amount = VALUES(credit_amount), refunded_amount = VALUES(refunded_amount), updated_at = NOW()
 	`
 
 	binaryOrderID, err := payment.OrderID.MarshalBinary()
@@ -53,7 +113,20 @@ func (r *paymentRepo) Store(payment *domain.Payment) error {
 		return err
 	}
 
-	_, err = r.client.Exec(paymentQuery, binaryOrderID, int(payment.Status), payment.TotalAmount)
+	binaryUserID, err := payment.UserID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(
+		paymentQuery,
+		binaryOrderID,
+		binaryUserID,
+		int(payment.Status),
+		payment.TotalAmount,
+		payment.CreditAmount,
+		payment.RefundedAmount,
+	)
 	return err
 }
 
@@ -61,8 +134,26 @@ func NewPaymentRepository(client mysql.Client) domain.PaymentRepository {
 	return &paymentRepo{client: client}
 }
 
+func convertPayments(paymentsSqlx []sqlxPayment) []domain.Payment {
+	result := make([]domain.Payment, 0, len(paymentsSqlx))
+	for _, paymentSqlx := range paymentsSqlx {
+		result = append(result, domain.Payment{
+			OrderID:        paymentSqlx.OrderID,
+			UserID:         paymentSqlx.UserID,
+			TotalAmount:    paymentSqlx.TotalAmount,
+			CreditAmount:   paymentSqlx.CreditAmount,
+			RefundedAmount: paymentSqlx.RefundedAmount,
+			Status:         domain.PaymentStatus(paymentSqlx.Status),
+		})
+	}
+	return result
+}
+
 type sqlxPayment struct {
-	OrderID     uuid.UUID `db:"order_id"`
-	Status      int       `db:"status"`
-	TotalAmount int       `db:"total_amount"`
+	OrderID        uuid.UUID `db:"order_id"`
+	UserID         uuid.UUID `db:"user_id"`
+	Status         int       `db:"status"`
+	TotalAmount    int       `db:"total_amount"`
+	CreditAmount   int       `db:"credit_amount"`
+	RefundedAmount int       `db:"refunded_amount"`
 }
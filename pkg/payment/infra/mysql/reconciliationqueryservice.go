@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/app/query"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+)
+
+func (s *paymentQueryService) ListReconciliationRuns() ([]query.ReconciliationRunData, error) {
+	const runsQuery = `
+		SELECT r.id, r.settlement_date, r.records_count, r.matched_count, r.created_at, COUNT(m.run_id) AS mismatch_count
+		FROM reconciliation_run r
+		LEFT JOIN reconciliation_mismatch m ON m.run_id = r.id
+		GROUP BY r.id
+		ORDER BY r.created_at DESC
+	`
+
+	var runsSqlx []struct {
+		sqlxReconciliationRun
+		MismatchCount int `db:"mismatch_count"`
+	}
+	err := s.client.Select(&runsSqlx, runsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]query.ReconciliationRunData, 0, len(runsSqlx))
+	for _, runSqlx := range runsSqlx {
+		result = append(result, query.ReconciliationRunData{
+			ID:             runSqlx.ID,
+			SettlementDate: runSqlx.SettlementDate,
+			RecordsCount:   runSqlx.RecordsCount,
+			MatchedCount:   runSqlx.MatchedCount,
+			MismatchCount:  runSqlx.MismatchCount,
+			CreatedAt:      runSqlx.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *paymentQueryService) GetReconciliationRun(id uuid.UUID) (*query.ReconciliationRunData, error) {
+	const runQuery = `
+		SELECT id, settlement_date, records_count, matched_count, created_at
+		FROM reconciliation_run
+		WHERE id = ?
+	`
+
+	binaryID, err := id.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var runSqlx sqlxReconciliationRun
+	err = s.client.Get(&runSqlx, runQuery, binaryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, query.ErrReconciliationRunNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	const mismatchesQuery = `
+		SELECT run_id, order_id, type, payment_status, payment_amount, settlement_status, settlement_amount
+		FROM reconciliation_mismatch
+		WHERE run_id = ?
+	`
+
+	var mismatchesSqlx []sqlxReconciliationMismatch
+	err = s.client.Select(&mismatchesSqlx, mismatchesQuery, binaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]query.ReconciliationMismatchData, 0, len(mismatchesSqlx))
+	for _, mismatchSqlx := range mismatchesSqlx {
+		mismatch := query.ReconciliationMismatchData{
+			Type:    domain.ReconciliationMismatchType(mismatchSqlx.Type),
+			OrderID: mismatchSqlx.OrderID,
+		}
+		if mismatchSqlx.PaymentStatus.Valid {
+			status := domain.PaymentStatus(mismatchSqlx.PaymentStatus.Int64)
+			amount := int(mismatchSqlx.PaymentAmount.Int64)
+			mismatch.PaymentStatus, mismatch.PaymentAmount = &status, &amount
+		}
+		if mismatchSqlx.SettlementStatus.Valid {
+			status := domain.SettlementStatus(mismatchSqlx.SettlementStatus.Int64)
+			amount := int(mismatchSqlx.SettlementAmount.Int64)
+			mismatch.SettlementStatus, mismatch.SettlementAmount = &status, &amount
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+
+	return &query.ReconciliationRunData{
+		ID:             runSqlx.ID,
+		SettlementDate: runSqlx.SettlementDate,
+		RecordsCount:   runSqlx.RecordsCount,
+		MatchedCount:   runSqlx.MatchedCount,
+		MismatchCount:  len(mismatches),
+		CreatedAt:      runSqlx.CreatedAt,
+		Mismatches:     mismatches,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+	"strings"
+	"time"
+)
+
+type reconciliationRunRepo struct {
+	client mysql.Client
+}
+
+func (r *reconciliationRunRepo) NextID() uuid.UUID {
+	return uuid.New()
+}
+
+func (r *reconciliationRunRepo) Store(run *domain.ReconciliationRun) error {
+	const runQuery = `
+		INSERT INTO reconciliation_run (id, settlement_date, records_count, matched_count, created_at)
+		VALUES (?, ?, ?, ?, NOW())
+	`
+
+	binaryRunID, err := run.ID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(runQuery, binaryRunID, run.SettlementDate, run.RecordsCount, run.MatchedCount)
+	if err != nil {
+		return err
+	}
+
+	if len(run.Mismatches) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO reconciliation_mismatch
+			(run_id, order_id, type, payment_status, payment_amount, settlement_status, settlement_amount)
+		VALUES %s%s
+	`, "(?, ?, ?, ?, ?, ?, ?)", strings.Repeat(", (?, ?, ?, ?, ?, ?, ?)", len(run.Mismatches)-1))
+	args := make([]any, 0, len(run.Mismatches)*7) // arguments count
+	for _, mismatch := range run.Mismatches {
+		binaryOrderID, err := mismatch.OrderID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		var paymentStatus, paymentAmount, settlementStatus, settlementAmount sql.NullInt64
+		if mismatch.Payment != nil {
+			paymentStatus = sql.NullInt64{Int64: int64(mismatch.Payment.Status), Valid: true}
+			paymentAmount = sql.NullInt64{Int64: int64(mismatch.Payment.GatewayAmount()), Valid: true}
+		}
+		if mismatch.Settlement != nil {
+			settlementStatus = sql.NullInt64{Int64: int64(mismatch.Settlement.Status), Valid: true}
+			settlementAmount = sql.NullInt64{Int64: int64(mismatch.Settlement.Amount), Valid: true}
+		}
+
+		args = append(args,
+			binaryRunID,
+			binaryOrderID,
+			int(mismatch.Type),
+			paymentStatus,
+			paymentAmount,
+			settlementStatus,
+			settlementAmount,
+		)
+	}
+
+	_, err = r.client.Exec(insertQuery, args...)
+	return err
+}
+
+func NewReconciliationRunRepository(client mysql.Client) domain.ReconciliationRunRepository {
+	return &reconciliationRunRepo{client: client}
+}
+
+type sqlxReconciliationRun struct {
+	ID             uuid.UUID `db:"id"`
+	SettlementDate time.Time `db:"settlement_date"`
+	RecordsCount   int       `db:"records_count"`
+	MatchedCount   int       `db:"matched_count"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+type sqlxReconciliationMismatch struct {
+	RunID            uuid.UUID     `db:"run_id"`
+	OrderID          uuid.UUID     `db:"order_id"`
+	Type             int           `db:"type"`
+	PaymentStatus    sql.NullInt64 `db:"payment_status"`
+	PaymentAmount    sql.NullInt64 `db:"payment_amount"`
+	SettlementStatus sql.NullInt64 `db:"settlement_status"`
+	SettlementAmount sql.NullInt64 `db:"settlement_amount"`
+}
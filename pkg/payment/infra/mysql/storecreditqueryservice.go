@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/app/query"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+	"time"
+)
+
+func (s *paymentQueryService) GetStoreCredit(userID uuid.UUID) (*query.StoreCreditData, error) {
+	const balanceQuery = `SELECT balance FROM store_credit WHERE user_id = ?`
+	const operationsQuery = `
+		SELECT type, amount, order_id, gift_card_code, created_at
+		FROM store_credit_operation
+		WHERE user_id = ?
+		ORDER BY id DESC
+	`
+
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	var balance int
+	err = s.client.Get(&balance, balanceQuery, binaryUserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	var operationsSqlx []sqlxStoreCreditOperation
+	err = s.client.Select(&operationsSqlx, operationsQuery, binaryUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]query.StoreCreditOperationData, 0, len(operationsSqlx))
+	for _, operationSqlx := range operationsSqlx {
+		operation := query.StoreCreditOperationData{
+			Type:      domain.StoreCreditOperationType(operationSqlx.Type),
+			Amount:    operationSqlx.Amount,
+			CreatedAt: operationSqlx.CreatedAt,
+		}
+		if operationSqlx.OrderID.Valid {
+			orderID := operationSqlx.OrderID.UUID
+			operation.OrderID = &orderID
+		}
+		if operationSqlx.GiftCardCode.Valid {
+			giftCardCode := operationSqlx.GiftCardCode.String
+			operation.GiftCardCode = &giftCardCode
+		}
+		operations = append(operations, operation)
+	}
+
+	return &query.StoreCreditData{
+		Balance:    balance,
+		Operations: operations,
+	}, nil
+}
+
+type sqlxStoreCreditOperation struct {
+	Type         int            `db:"type"`
+	Amount       int            `db:"amount"`
+	OrderID      uuid.NullUUID  `db:"order_id"`
+	GiftCardCode sql.NullString `db:"gift_card_code"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
@@ -0,0 +1,91 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+)
+
+type storeCreditRepo struct {
+	client mysql.Client
+}
+
+func (r *storeCreditRepo) Lock(userID uuid.UUID) (*domain.StoreCreditAccount, error) {
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.client.Exec(`
+		INSERT INTO store_credit (user_id, balance, created_at)
+		VALUES (?, 0, NOW())
+		ON DUPLICATE KEY UPDATE user_id = user_id
+	`, binaryUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var balance int
+	err = r.client.Get(&balance, `SELECT balance FROM store_credit WHERE user_id = ? FOR UPDATE`, binaryUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.StoreCreditAccount{
+		UserID:  userID,
+		Balance: balance,
+	}, nil
+}
+
+func (r *storeCreditRepo) Store(account *domain.StoreCreditAccount) error {
+	const accountQuery = `
+		INSERT INTO store_credit (user_id, balance, created_at)
+		VALUES (?, ?, NOW())
+		ON DUPLICATE KEY UPDATE
+			balance = VALUES(balance), updated_at = NOW()
+	`
+
+	binaryUserID, err := account.UserID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Exec(accountQuery, binaryUserID, account.Balance)
+	return err
+}
+
+func (r *storeCreditRepo) AddOperation(operation *domain.StoreCreditOperation) error {
+	const operationQuery = `
+		INSERT INTO store_credit_operation (user_id, type, amount, order_id, gift_card_code, created_at)
+		VALUES (?, ?, ?, ?, ?, NOW())
+	`
+
+	binaryUserID, err := operation.UserID.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	var binaryOrderID []byte
+	if operation.OrderID != uuid.Nil {
+		binaryOrderID, err = operation.OrderID.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	}
+	giftCardCode := sql.NullString{String: operation.GiftCardCode, Valid: operation.GiftCardCode != ""}
+
+	_, err = r.client.Exec(
+		operationQuery,
+		binaryUserID,
+		int(operation.Type),
+		operation.Amount,
+		binaryOrderID,
+		giftCardCode,
+	)
+	return err
+}
+
+func NewStoreCreditRepository(client mysql.Client) domain.StoreCreditRepository {
+	return &storeCreditRepo{client: client}
+}
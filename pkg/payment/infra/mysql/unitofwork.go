@@ -3,6 +3,7 @@ package mysql
 import (
 	"fmt"
 	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/idempotence"
 	"github.com/klwxsrx/arch-course-project/pkg/common/infra/mysql"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/app/persistence"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/app/service/async"
@@ -18,6 +19,22 @@ func (p *persistentProvider) PaymentRepository() domain.PaymentRepository {
 	return NewPaymentRepository(p.db)
 }
 
+func (p *persistentProvider) ReconciliationRunRepository() domain.ReconciliationRunRepository {
+	return NewReconciliationRunRepository(p.db)
+}
+
+func (p *persistentProvider) StoreCreditRepository() domain.StoreCreditRepository {
+	return NewStoreCreditRepository(p.db)
+}
+
+func (p *persistentProvider) GiftCardRepository() domain.GiftCardRepository {
+	return NewGiftCardRepository(p.db)
+}
+
+func (p *persistentProvider) IdempotenceKeyStore() idempotence.KeyStore {
+	return mysql.NewIdempotenceKeyStore(p.db)
+}
+
 func (p *persistentProvider) OrderAPI() async.OrderAPI {
 	return orderapi.New(p.eventDispatcher(p.db))
 }
@@ -68,6 +68,24 @@ func (a *api) NotifyPaymentCompletionRejected(orderID uuid.UUID) error {
 	return nil
 }
 
+func (a *api) NotifyPaymentRefunded(orderID uuid.UUID) error {
+	jsonID, err := json.Marshal(orderID)
+	if err != nil {
+		return errors.New("failed to encode orderID")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "payment_refunded",
+		TopicName: orderEventTopicName,
+		Key:       orderID.String(),
+		Body:      jsonID,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
 func New(eventDispatcher event.Dispatcher) async.OrderAPI {
 	return &api{eventDispatcher: eventDispatcher}
 }
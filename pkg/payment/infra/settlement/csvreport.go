@@ -0,0 +1,103 @@
+package settlement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	columnOrderID = "order_id"
+	columnAmount  = "amount"
+	columnStatus  = "status"
+
+	statusCaptured = "captured"
+	statusRefunded = "refunded"
+)
+
+// ParseCSVReport reads gateway settlement report with order_id, amount and status columns in any order
+func ParseCSVReport(r io.Reader) ([]domain.SettlementRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("empty settlement report")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report header: %w", err)
+	}
+	columns, err := getColumnIndexes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []domain.SettlementRecord
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report row: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		record, err := parseRow(row, columns)
+		if err != nil {
+			return nil, fmt.Errorf("invalid report row at line %d: %w", line, err)
+		}
+		result = append(result, *record)
+	}
+	return result, nil
+}
+
+func getColumnIndexes(header []string) (map[string]int, error) {
+	result := make(map[string]int, len(header))
+	for i, column := range header {
+		result[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range []string{columnOrderID, columnAmount, columnStatus} {
+		if _, ok := result[column]; !ok {
+			return nil, fmt.Errorf("report column %s not found", column)
+		}
+	}
+	return result, nil
+}
+
+func parseRow(row []string, columns map[string]int) (*domain.SettlementRecord, error) {
+	orderID, err := uuid.Parse(row[columns[columnOrderID]])
+	if err != nil {
+		return nil, fmt.Errorf("invalid order id: %w", err)
+	}
+	amount, err := strconv.Atoi(row[columns[columnAmount]])
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+	status, err := parseStatus(row[columns[columnStatus]])
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SettlementRecord{
+		OrderID: orderID,
+		Amount:  amount,
+		Status:  status,
+	}, nil
+}
+
+func parseStatus(status string) (domain.SettlementStatus, error) {
+	switch strings.ToLower(status) {
+	case statusCaptured:
+		return domain.SettlementStatusCaptured, nil
+	case statusRefunded:
+		return domain.SettlementStatusRefunded, nil
+	default:
+		return 0, fmt.Errorf("unknown settlement status %s", status)
+	}
+}
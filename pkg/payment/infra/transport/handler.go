@@ -11,26 +11,74 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/payment/app/query"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/app/service"
 	"github.com/klwxsrx/arch-course-project/pkg/payment/domain"
+	"github.com/klwxsrx/arch-course-project/pkg/payment/infra/gateway"
+	"io/ioutil"
 	"net/http"
+	"time"
 )
 
-const healthEndpoint = "/healthz"
+const (
+	healthEndpoint       = "/healthz"
+	settlementDateLayout = "2006-01-02"
+)
 
 type route struct {
 	Name    string
 	Method  string
 	Pattern string
-	Handler func(*service.PaymentService, query.PaymentQueryService, http.ResponseWriter, *http.Request)
+	Handler func(*service.PaymentService, *service.StoreCreditService, query.PaymentQueryService, http.ResponseWriter, *http.Request)
 }
 
-func getRoutes() []route {
+func getRoutes(webhookSecret []byte) []route {
 	return []route{
+		{
+			"gatewayWebhook",
+			http.MethodPost,
+			"/webhook/payment/gateway",
+			newGatewayWebhookHandler(webhookSecret),
+		},
 		{
 			"getPayment",
 			http.MethodGet,
 			"/payment/{orderID}",
 			getPaymentHandler,
 		},
+		{
+			"refundToStoreCredit",
+			http.MethodPost,
+			"/payment/{orderID}/refund/store-credit",
+			refundToStoreCreditHandler,
+		},
+		{
+			"addGiftCard",
+			http.MethodPut,
+			"/payment/gift-cards",
+			addGiftCardHandler,
+		},
+		{
+			"redeemGiftCard",
+			http.MethodPost,
+			"/web/payment/gift-cards/redeem",
+			redeemGiftCardHandler,
+		},
+		{
+			"getStoreCredit",
+			http.MethodGet,
+			"/web/payment/store-credit",
+			getStoreCreditHandler,
+		},
+		{
+			"listReconciliationRuns",
+			http.MethodGet,
+			"/payment/reconciliation/runs",
+			listReconciliationRunsHandler,
+		},
+		{
+			"getReconciliationRun",
+			http.MethodGet,
+			"/payment/reconciliation/runs/{runID}",
+			getReconciliationRunHandler,
+		},
 		{
 			"health",
 			http.MethodGet,
@@ -40,7 +88,7 @@ func getRoutes() []route {
 	}
 }
 
-func getPaymentHandler(_ *service.PaymentService, srv query.PaymentQueryService, w http.ResponseWriter, r *http.Request) {
+func getPaymentHandler(_ *service.PaymentService, _ *service.StoreCreditService, srv query.PaymentQueryService, w http.ResponseWriter, r *http.Request) {
 	orderID, err := parseUUID(mux.Vars(r)["orderID"])
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -64,13 +112,17 @@ func getPaymentHandler(_ *service.PaymentService, srv query.PaymentQueryService,
 	}
 
 	result := struct {
-		OrderID     uuid.UUID `json:"order_id"`
-		Status      string    `json:"status"`
-		TotalAmount int       `json:"total_amount"`
+		OrderID        uuid.UUID `json:"order_id"`
+		Status         string    `json:"status"`
+		TotalAmount    int       `json:"total_amount"`
+		CreditAmount   int       `json:"credit_amount"`
+		RefundedAmount int       `json:"refunded_amount"`
 	}{
 		data.OrderID,
 		textStatus,
 		data.TotalAmount,
+		data.CreditAmount,
+		data.RefundedAmount,
 	}
 
 	resultJSON, err := json.Marshal(result)
@@ -87,7 +139,302 @@ func getPaymentHandler(_ *service.PaymentService, srv query.PaymentQueryService,
 	}
 }
 
-func healthCheckHandler(_ *service.PaymentService, _ query.PaymentQueryService, w http.ResponseWriter, _ *http.Request) {
+func refundToStoreCreditHandler(srv *service.PaymentService, _ *service.StoreCreditService, _ query.PaymentQueryService, w http.ResponseWriter, r *http.Request) {
+	orderID, err := parseUUID(mux.Vars(r)["orderID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.RefundToStoreCredit(orderID)
+	switch {
+	case errors.Is(err, service.ErrPaymentNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, service.ErrPaymentNotRefundable):
+		w.WriteHeader(http.StatusConflict)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func addGiftCardHandler(_ *service.PaymentService, srv *service.StoreCreditService, _ query.PaymentQueryService, w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Code   string `json:"code"`
+		Amount int    `json:"amount"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = srv.AddGiftCard(body.Code, body.Amount)
+	switch {
+	case errors.Is(err, service.ErrInvalidGiftCard):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, service.ErrGiftCardAlreadyRedeemed):
+		w.WriteHeader(http.StatusConflict)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func redeemGiftCardHandler(_ *service.PaymentService, srv *service.StoreCreditService, _ query.PaymentQueryService, w http.ResponseWriter, r *http.Request) {
+	userID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	amount, err := srv.RedeemGiftCard(userID, body.Code)
+	switch {
+	case errors.Is(err, service.ErrGiftCardNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrGiftCardAlreadyRedeemed):
+		w.WriteHeader(http.StatusConflict)
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		Amount int `json:"amount"`
+	}{amount})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getStoreCreditHandler(_ *service.PaymentService, _ *service.StoreCreditService, srv query.PaymentQueryService, w http.ResponseWriter, r *http.Request) {
+	userID, err := parseAuthUserID(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	data, err := srv.GetStoreCredit(userID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type operationJSONSchema struct {
+		Type         string     `json:"type"`
+		Amount       int        `json:"amount"`
+		OrderID      *uuid.UUID `json:"order_id"`
+		GiftCardCode *string    `json:"gift_card_code"`
+		CreatedAt    time.Time  `json:"created_at"`
+	}
+
+	operations := make([]operationJSONSchema, 0, len(data.Operations))
+	for _, operation := range data.Operations {
+		operationType, err := getTextStoreCreditOperationType(operation.Type)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		operations = append(operations, operationJSONSchema{
+			Type:         operationType,
+			Amount:       operation.Amount,
+			OrderID:      operation.OrderID,
+			GiftCardCode: operation.GiftCardCode,
+			CreatedAt:    operation.CreatedAt,
+		})
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		Balance    int                   `json:"balance"`
+		Operations []operationJSONSchema `json:"operations"`
+	}{
+		data.Balance,
+		operations,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+type reconciliationRunJSONSchema struct {
+	ID             uuid.UUID `json:"id"`
+	SettlementDate string    `json:"settlement_date"`
+	RecordsCount   int       `json:"records_count"`
+	MatchedCount   int       `json:"matched_count"`
+	MismatchCount  int       `json:"mismatch_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func listReconciliationRunsHandler(_ *service.PaymentService, _ *service.StoreCreditService, srv query.PaymentQueryService, w http.ResponseWriter, _ *http.Request) {
+	runs, err := srv.ListReconciliationRuns()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]reconciliationRunJSONSchema, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, getReconciliationRunJSONSchema(&run))
+	}
+
+	err = json.NewEncoder(w).Encode(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getReconciliationRunHandler(_ *service.PaymentService, _ *service.StoreCreditService, srv query.PaymentQueryService, w http.ResponseWriter, r *http.Request) {
+	runID, err := parseUUID(mux.Vars(r)["runID"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	run, err := srv.GetReconciliationRun(runID)
+	if errors.Is(err, query.ErrReconciliationRunNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	type mismatchJSONSchema struct {
+		Type             string    `json:"type"`
+		OrderID          uuid.UUID `json:"order_id"`
+		PaymentStatus    *string   `json:"payment_status"`
+		PaymentAmount    *int      `json:"payment_amount"`
+		SettlementStatus *string   `json:"settlement_status"`
+		SettlementAmount *int      `json:"settlement_amount"`
+	}
+
+	mismatches := make([]mismatchJSONSchema, 0, len(run.Mismatches))
+	for _, mismatch := range run.Mismatches {
+		mismatchType, err := getTextMismatchType(mismatch.Type)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		mismatchJSON := mismatchJSONSchema{
+			Type:             mismatchType,
+			OrderID:          mismatch.OrderID,
+			PaymentAmount:    mismatch.PaymentAmount,
+			SettlementAmount: mismatch.SettlementAmount,
+		}
+		if mismatch.PaymentStatus != nil {
+			status, err := getTextStatus(*mismatch.PaymentStatus)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			mismatchJSON.PaymentStatus = &status
+		}
+		if mismatch.SettlementStatus != nil {
+			status, err := getTextSettlementStatus(*mismatch.SettlementStatus)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			mismatchJSON.SettlementStatus = &status
+		}
+		mismatches = append(mismatches, mismatchJSON)
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		reconciliationRunJSONSchema
+		Mismatches []mismatchJSONSchema `json:"mismatches"`
+	}{
+		getReconciliationRunJSONSchema(run),
+		mismatches,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func getReconciliationRunJSONSchema(run *query.ReconciliationRunData) reconciliationRunJSONSchema {
+	return reconciliationRunJSONSchema{
+		ID:             run.ID,
+		SettlementDate: run.SettlementDate.Format(settlementDateLayout),
+		RecordsCount:   run.RecordsCount,
+		MatchedCount:   run.MatchedCount,
+		MismatchCount:  run.MismatchCount,
+		CreatedAt:      run.CreatedAt,
+	}
+}
+
+func newGatewayWebhookHandler(secret []byte) func(*service.PaymentService, *service.StoreCreditService, query.PaymentQueryService, http.ResponseWriter, *http.Request) {
+	return func(srv *service.PaymentService, _ *service.StoreCreditService, _ query.PaymentQueryService, w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if !gateway.VerifySignature(secret, body, r.Header.Get(gateway.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var webhookEvent gateway.WebhookEvent
+		err = json.Unmarshal(body, &webhookEvent)
+		if err != nil || webhookEvent.ID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		eventType, err := getGatewayEventType(webhookEvent.Type)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		err = srv.HandleGatewayEvent(&service.GatewayEvent{
+			ID:      webhookEvent.ID,
+			Type:    eventType,
+			OrderID: webhookEvent.OrderID,
+			Amount:  webhookEvent.Amount,
+		})
+		if errors.Is(err, service.ErrPaymentNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, service.ErrGatewayEventAmountMismatch) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		if errors.Is(err, service.ErrGatewayEventTooEarly) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func healthCheckHandler(_ *service.PaymentService, _ *service.StoreCreditService, _ query.PaymentQueryService, w http.ResponseWriter, _ *http.Request) {
 	_ = json.NewEncoder(w).Encode(struct {
 		Status string `json:"status"`
 	}{"OK"})
@@ -103,34 +450,106 @@ func getTextStatus(status domain.PaymentStatus) (string, error) {
 		return "completed", nil
 	case domain.PaymentStatusRejected:
 		return "rejected", nil
+	case domain.PaymentStatusCompletionPending:
+		return "completion_pending", nil
+	case domain.PaymentStatusRefunded:
+		return "refunded", nil
+	case domain.PaymentStatusRefundPending:
+		return "refund_pending", nil
 	default:
 		return "", errors.New(fmt.Sprintf("unknown status %v", status))
 	}
 }
 
+func getTextStoreCreditOperationType(typ domain.StoreCreditOperationType) (string, error) {
+	switch typ {
+	case domain.StoreCreditOperationGiftCardRedemption:
+		return "gift_card_redemption", nil
+	case domain.StoreCreditOperationRefund:
+		return "refund", nil
+	case domain.StoreCreditOperationPaymentCharge:
+		return "payment_charge", nil
+	case domain.StoreCreditOperationPaymentReturn:
+		return "payment_return", nil
+	default:
+		return "", fmt.Errorf("unknown store credit operation type %v", typ)
+	}
+}
+
+func getTextMismatchType(typ domain.ReconciliationMismatchType) (string, error) {
+	switch typ {
+	case domain.ReconciliationMismatchMissingOnOurSide:
+		return "missing_on_our_side", nil
+	case domain.ReconciliationMismatchMissingAtGateway:
+		return "missing_at_gateway", nil
+	case domain.ReconciliationMismatchAmountDiffers:
+		return "amount_differs", nil
+	case domain.ReconciliationMismatchStatusDiffers:
+		return "status_differs", nil
+	default:
+		return "", fmt.Errorf("unknown mismatch type %v", typ)
+	}
+}
+
+func getTextSettlementStatus(status domain.SettlementStatus) (string, error) {
+	switch status {
+	case domain.SettlementStatusCaptured:
+		return "captured", nil
+	case domain.SettlementStatusRefunded:
+		return "refunded", nil
+	default:
+		return "", fmt.Errorf("unknown settlement status %v", status)
+	}
+}
+
+func getGatewayEventType(typ string) (service.GatewayEventType, error) {
+	switch typ {
+	case gateway.WebhookEventCaptureSucceeded:
+		return service.GatewayEventCaptureSucceeded, nil
+	case gateway.WebhookEventCaptureFailed:
+		return service.GatewayEventCaptureFailed, nil
+	case gateway.WebhookEventRefundSucceeded:
+		return service.GatewayEventRefundSucceeded, nil
+	default:
+		return 0, fmt.Errorf("unknown gateway event type %s", typ)
+	}
+}
+
+func parseAuthUserID(r *http.Request) (uuid.UUID, error) {
+	id := r.Header.Get("X-Auth-User-ID")
+	return uuid.Parse(id)
+}
+
 func parseUUID(str string) (uuid.UUID, error) {
 	return uuid.Parse(str)
 }
 
 func getHandlerFunc(
 	paymentService *service.PaymentService,
+	storeCreditService *service.StoreCreditService,
 	paymentQueryService query.PaymentQueryService,
-	f func(*service.PaymentService, query.PaymentQueryService, http.ResponseWriter, *http.Request),
+	f func(*service.PaymentService, *service.StoreCreditService, query.PaymentQueryService, http.ResponseWriter, *http.Request),
 ) func(http.ResponseWriter, *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		f(paymentService, paymentQueryService, w, r)
+		f(paymentService, storeCreditService, paymentQueryService, w, r)
 	}
 }
 
-func NewHTTPHandler(paymentService *service.PaymentService, queryService query.PaymentQueryService, logger log.Logger) (http.Handler, error) {
+func NewHTTPHandler(
+	paymentService *service.PaymentService,
+	storeCreditService *service.StoreCreditService,
+	queryService query.PaymentQueryService,
+	webhookSecret []byte,
+	logger log.Logger,
+) (http.Handler, error) {
 	router := mux.NewRouter()
 
-	for _, route := range getRoutes() {
+	for _, route := range getRoutes(webhookSecret) {
 		router.
 			Methods(route.Method).
 			Path(route.Pattern).
 			Name(route.Name).
-			HandlerFunc(getHandlerFunc(paymentService, queryService, route.Handler))
+			HandlerFunc(getHandlerFunc(paymentService, storeCreditService, queryService, route.Handler))
 	}
 
 	router.Use(transport.NewLoggingMiddleware(logger, []string{healthEndpoint}))
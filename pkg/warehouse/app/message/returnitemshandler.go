@@ -0,0 +1,39 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/message"
+	"github.com/klwxsrx/arch-course-project/pkg/warehouse/app/service"
+)
+
+type returnItemsHandler struct {
+	service *service.WarehouseService
+}
+
+func (h *returnItemsHandler) TopicName() string {
+	return warehouseEventTopicName
+}
+
+func (h *returnItemsHandler) Type() string {
+	return "return_items"
+}
+
+func (h *returnItemsHandler) Handle(msg *message.Message) error {
+	var orderID uuid.UUID
+	err := json.Unmarshal(msg.Body, &orderID)
+	if err != nil {
+		return fmt.Errorf("failed to decode message")
+	}
+
+	err = h.service.ReturnOrderItems(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to return order items: %w", err)
+	}
+	return nil
+}
+
+func NewReturnItemsHandler(service *service.WarehouseService) message.Handler {
+	return &returnItemsHandler{service: service}
+}
@@ -10,6 +10,7 @@ type PersistentProvider interface {
 	Stock() domain.Stock
 	IdempotenceKeyStore() idempotence.KeyStore
 	OrderAPI() async.OrderAPI
+	StockEventAPI() async.StockEventAPI
 }
 
 type UnitOfWork interface {
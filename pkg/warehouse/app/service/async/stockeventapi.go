@@ -0,0 +1,9 @@
+package async
+
+import "github.com/google/uuid"
+
+type StockEventAPI interface {
+	NotifyItemBackInStock(itemID uuid.UUID, quantity int) error
+	NotifyItemOutOfStock(itemID uuid.UUID) error
+	NotifyItemStockSynced(itemID uuid.UUID, quantity int) error
+}
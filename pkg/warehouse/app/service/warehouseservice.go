@@ -58,14 +58,15 @@ func (s *WarehouseService) AddItems(idempotenceKey string, itemID uuid.UUID, qua
 			return err
 		}
 
-		op := &domain.StockOperation{
-			ID:           p.Stock().NextID(),
-			ItemID:       itemID,
-			Type:         domain.StockOperationTypeArrival,
-			ItemQuantity: quantity,
-		}
-
-		return p.Stock().Update(op)
+		return s.notifyItemsStockChanged(p, []uuid.UUID{itemID}, func() error {
+			op := &domain.StockOperation{
+				ID:           p.Stock().NextID(),
+				ItemID:       itemID,
+				Type:         domain.StockOperationTypeArrival,
+				ItemQuantity: quantity,
+			}
+			return p.Stock().Update(op)
+		})
 	})
 	if err != nil && !errors.Is(err, ErrItemAlreadyAdded) {
 		s.logger.WithError(err).With(log.Fields{
@@ -77,6 +78,32 @@ func (s *WarehouseService) AddItems(idempotenceKey string, itemID uuid.UUID, qua
 	return err
 }
 
+func (s *WarehouseService) SyncStockAvailability() error {
+	var itemsCount int
+	err := s.unitOfWork.Execute("", func(p persistence.PersistentProvider) error {
+		items, err := p.Stock().GetAllItemsQuantity()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			err = p.StockEventAPI().NotifyItemStockSynced(item.ItemID, item.Quantity)
+			if err != nil {
+				return fmt.Errorf("failed to notify item stock synced: %w", err)
+			}
+		}
+		itemsCount = len(items)
+		return nil
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to sync stock availability")
+		return err
+	}
+
+	s.logger.With(log.Fields{"itemsCount": itemsCount}).Info("stock availability synced")
+	return nil
+}
+
 func (s *WarehouseService) ReserveOrderItems(orderID uuid.UUID, itemsQuantity []domain.ItemQuantity) error {
 	if len(itemsQuantity) == 0 {
 		return nil
@@ -114,18 +141,24 @@ func (s *WarehouseService) ReserveOrderItems(orderID uuid.UUID, itemsQuantity []
 			return nil
 		}
 
-		for _, item := range itemsQuantity {
-			op := &domain.StockOperation{
-				ID:           p.Stock().NextID(),
-				ItemID:       item.ItemID,
-				Type:         domain.StockOperationTypeReservation,
-				ItemQuantity: -1 * item.Quantity,
-				OrderID:      &orderID,
-			}
-			err := p.Stock().Update(op)
-			if err != nil {
-				return err
+		err = s.notifyItemsStockChanged(p, itemIDs, func() error {
+			for _, item := range itemsQuantity {
+				op := &domain.StockOperation{
+					ID:           p.Stock().NextID(),
+					ItemID:       item.ItemID,
+					Type:         domain.StockOperationTypeReservation,
+					ItemQuantity: -1 * item.Quantity,
+					OrderID:      &orderID,
+				}
+				err := p.Stock().Update(op)
+				if err != nil {
+					return err
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 
 		err = p.OrderAPI().NotifyItemsReserved(orderID)
@@ -151,10 +184,14 @@ func (s *WarehouseService) DeleteOrderItemsReservation(orderID uuid.UUID) error
 		}
 
 		ids := make([]uuid.UUID, 0, len(ops))
+		itemIDs := make([]uuid.UUID, 0, len(ops))
 		for _, op := range ops {
 			ids = append(ids, op.ID)
+			itemIDs = append(itemIDs, op.ItemID)
 		}
-		return p.Stock().Delete(ids)
+		return s.notifyItemsStockChanged(p, itemIDs, func() error {
+			return p.Stock().Delete(ids)
+		})
 	})
 
 	if err != nil {
@@ -163,6 +200,87 @@ func (s *WarehouseService) DeleteOrderItemsReservation(orderID uuid.UUID) error
 	return err
 }
 
+func (s *WarehouseService) ReturnOrderItems(orderID uuid.UUID) error {
+	err := s.unitOfWork.Execute("", func(p persistence.PersistentProvider) error {
+		return s.returnOrderItems(p, orderID, nil)
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to return order items")
+	}
+	return err
+}
+
+func (s *WarehouseService) ReturnOrderItemsPartially(orderID uuid.UUID, itemsQuantity []domain.ItemQuantity) error {
+	for _, item := range itemsQuantity {
+		if item.Quantity <= 0 {
+			return ErrInvalidQuantity
+		}
+	}
+
+	err := s.unitOfWork.Execute("", func(p persistence.PersistentProvider) error {
+		returnQuantity := make(map[uuid.UUID]int, len(itemsQuantity))
+		for _, item := range itemsQuantity {
+			returnQuantity[item.ItemID] += item.Quantity
+		}
+		return s.returnOrderItems(p, orderID, returnQuantity)
+	})
+	if err != nil {
+		s.logger.WithError(err).With(log.Fields{"orderID": orderID}).Error("failed to return order items partially")
+	}
+	return err
+}
+
+// returnOrderItems returns all order items to stock when returnQuantity is nil
+func (s *WarehouseService) returnOrderItems(p persistence.PersistentProvider, orderID uuid.UUID, returnQuantity map[uuid.UUID]int) error {
+	ops, err := p.Stock().GetOrderOperations(orderID)
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if op.Type == domain.StockOperationTypeReturn {
+			return nil
+		}
+	}
+
+	returnOps := make([]*domain.StockOperation, 0, len(ops))
+	itemIDs := make([]uuid.UUID, 0, len(ops))
+	for _, op := range ops {
+		if op.Type != domain.StockOperationTypeReservation && op.Type != domain.StockOperationTypeSale {
+			continue
+		}
+
+		quantity := -1 * op.ItemQuantity
+		if returnQuantity != nil {
+			if returnQuantity[op.ItemID] < quantity {
+				quantity = returnQuantity[op.ItemID]
+			}
+			returnQuantity[op.ItemID] -= quantity
+		}
+		if quantity <= 0 {
+			continue
+		}
+
+		returnOps = append(returnOps, &domain.StockOperation{
+			ID:           p.Stock().NextID(),
+			ItemID:       op.ItemID,
+			Type:         domain.StockOperationTypeReturn,
+			ItemQuantity: quantity,
+			OrderID:      &orderID,
+		})
+		itemIDs = append(itemIDs, op.ItemID)
+	}
+
+	return s.notifyItemsStockChanged(p, itemIDs, func() error {
+		for _, op := range returnOps {
+			err := p.Stock().Update(op)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (s *WarehouseService) checkAvailableItemsEnough(actualQuantity, expectedQuantity []domain.ItemQuantity) bool {
 	findActualQuantity := func(itemID uuid.UUID) (int, bool) {
 		for _, actualItem := range actualQuantity {
@@ -185,6 +303,49 @@ func (s *WarehouseService) checkAvailableItemsEnough(actualQuantity, expectedQua
 	return true
 }
 
+func (s *WarehouseService) notifyItemsStockChanged(
+	p persistence.PersistentProvider,
+	itemIDs []uuid.UUID,
+	update func() error,
+) error {
+	if len(itemIDs) == 0 {
+		return update()
+	}
+
+	before, err := p.Stock().GetAvailableItemsQuantity(itemIDs)
+	if err != nil {
+		return err
+	}
+	err = update()
+	if err != nil {
+		return err
+	}
+	after, err := p.Stock().GetAvailableItemsQuantity(itemIDs)
+	if err != nil {
+		return err
+	}
+
+	wasAvailable := make(map[uuid.UUID]bool, len(before))
+	for _, item := range before {
+		wasAvailable[item.ItemID] = item.Quantity > 0
+	}
+	for _, item := range after {
+		if item.Quantity > 0 && !wasAvailable[item.ItemID] {
+			err = p.StockEventAPI().NotifyItemBackInStock(item.ItemID, item.Quantity)
+			if err != nil {
+				return fmt.Errorf("failed to notify item back in stock: %w", err)
+			}
+		}
+		if item.Quantity <= 0 && wasAvailable[item.ItemID] {
+			err = p.StockEventAPI().NotifyItemOutOfStock(item.ItemID)
+			if err != nil {
+				return fmt.Errorf("failed to notify item out of stock: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
 func NewWarehouseService(unitOfWork persistence.UnitOfWork, logger log.Logger) *WarehouseService {
 	return &WarehouseService{
 		unitOfWork: unitOfWork,
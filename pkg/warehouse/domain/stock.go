@@ -8,6 +8,7 @@ const (
 	StockOperationTypeArrival = iota
 	StockOperationTypeReservation
 	StockOperationTypeSale
+	StockOperationTypeReturn
 )
 
 type StockOperation struct {
@@ -26,6 +27,7 @@ type ItemQuantity struct {
 type Stock interface {
 	NextID() uuid.UUID
 	GetAvailableItemsQuantity(itemIDs []uuid.UUID) ([]ItemQuantity, error)
+	GetAllItemsQuantity() ([]ItemQuantity, error)
 	GetOrderOperations(orderID uuid.UUID) ([]StockOperation, error)
 	Update(op *StockOperation) error
 	Delete(opIDs []uuid.UUID) error
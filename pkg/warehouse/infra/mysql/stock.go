@@ -61,6 +61,22 @@ func (s *stock) GetAvailableItemsQuantity(itemIDs []uuid.UUID) ([]domain.ItemQua
 	return result, nil
 }
 
+func (s *stock) GetAllItemsQuantity() ([]domain.ItemQuantity, error) {
+	const query = `
+		SELECT item_id, SUM(quantity) AS quantity
+		FROM stock_balance
+		WHERE deleted_at IS NULL
+		GROUP BY item_id
+	`
+
+	var result []domain.ItemQuantity
+	err := s.client.Select(&result, query)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (s *stock) GetOrderOperations(orderID uuid.UUID) ([]domain.StockOperation, error) {
 	const query = `
 		SELECT id, item_id, type, quantity, order_id
@@ -9,6 +9,7 @@ import (
 	"github.com/klwxsrx/arch-course-project/pkg/warehouse/app/service/async"
 	"github.com/klwxsrx/arch-course-project/pkg/warehouse/domain"
 	"github.com/klwxsrx/arch-course-project/pkg/warehouse/infra/orderapi"
+	"github.com/klwxsrx/arch-course-project/pkg/warehouse/infra/stockeventapi"
 )
 
 type persistentProvider struct {
@@ -27,6 +28,10 @@ func (p *persistentProvider) OrderAPI() async.OrderAPI {
 	return orderapi.New(p.eventDispatcher(p.db))
 }
 
+func (p *persistentProvider) StockEventAPI() async.StockEventAPI {
+	return stockeventapi.New(p.eventDispatcher(p.db))
+}
+
 func (p *persistentProvider) eventDispatcher(db mysql.Client) event.Dispatcher {
 	return event.NewDispatcher(mysql.NewMessageStore(db))
 }
@@ -0,0 +1,81 @@
+package stockeventapi
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/klwxsrx/arch-course-project/pkg/common/app/event"
+	"github.com/klwxsrx/arch-course-project/pkg/warehouse/app/service/async"
+)
+
+const stockEventTopicName = "stock_event"
+
+type api struct {
+	eventDispatcher event.Dispatcher
+}
+
+func (a *api) NotifyItemBackInStock(itemID uuid.UUID, quantity int) error {
+	body, err := json.Marshal(struct {
+		ItemID   uuid.UUID `json:"item_id"`
+		Quantity int       `json:"quantity"`
+	}{itemID, quantity})
+	if err != nil {
+		return errors.New("failed to encode stock event")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "item_back_in_stock",
+		TopicName: stockEventTopicName,
+		Key:       itemID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func (a *api) NotifyItemOutOfStock(itemID uuid.UUID) error {
+	body, err := json.Marshal(struct {
+		ItemID uuid.UUID `json:"item_id"`
+	}{itemID})
+	if err != nil {
+		return errors.New("failed to encode stock event")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "item_out_of_stock",
+		TopicName: stockEventTopicName,
+		Key:       itemID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func (a *api) NotifyItemStockSynced(itemID uuid.UUID, quantity int) error {
+	body, err := json.Marshal(struct {
+		ItemID   uuid.UUID `json:"item_id"`
+		Quantity int       `json:"quantity"`
+	}{itemID, quantity})
+	if err != nil {
+		return errors.New("failed to encode stock event")
+	}
+
+	err = a.eventDispatcher.Dispatch(&event.Event{
+		Type:      "item_stock_synced",
+		TopicName: stockEventTopicName,
+		Key:       itemID.String(),
+		Body:      body,
+	})
+	if err != nil {
+		return errors.New("failed to dispatch message")
+	}
+	return nil
+}
+
+func New(eventDispatcher event.Dispatcher) async.StockEventAPI {
+	return &api{eventDispatcher: eventDispatcher}
+}
@@ -34,6 +34,12 @@ func getRoutes() []route {
 			"/warehouse/items",
 			addItemsHandler,
 		},
+		{
+			"syncStockAvailability",
+			http.MethodPost,
+			"/warehouse/items/sync",
+			syncStockAvailabilityHandler,
+		},
 		{
 			"health",
 			http.MethodGet,
@@ -112,6 +118,15 @@ func addItemsHandler(srv *service.WarehouseService, w http.ResponseWriter, r *ht
 	}
 }
 
+func syncStockAvailabilityHandler(srv *service.WarehouseService, w http.ResponseWriter, _ *http.Request) {
+	err := srv.SyncStockAvailability()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func healthCheckHandler(_ *service.WarehouseService, w http.ResponseWriter, _ *http.Request) {
 	_ = json.NewEncoder(w).Encode(struct {
 		Status string `json:"status"`